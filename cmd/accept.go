@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/internal/accept"
+	"github.com/timchurchard/opendime-utils/internal/report"
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// acceptNow clock for the receipt and history. A variable so tests can fix it
+var acceptNow = time.Now
+
+// AcceptMain entrypoint for the accept command. The arguments are verify.txt files or Opendime directories
+func AcceptMain(out io.Writer) int {
+	const (
+		defaultEmpty  = ""
+		usageMounted  = "Check every mounted Opendime as well as the verify.txt files given"
+		usageHistory  = "Path to the history file (default $OPENDIME_HISTORY or history.json in the user config dir)"
+		usageFirmware = "Comma separated firmware versions to accept as known in addition to the built in list"
+		usageSpent    = "Check the spend history of every derived address"
+		usageJobs     = "Number of Opendimes to check at once"
+		usageNetwork  = "Network: mainnet, testnet, signet or regtest"
+		usageFormat   = "Output format: text, json, csv or yaml"
+	)
+	var (
+		mounted       bool
+		historyFn     string
+		knownFirmware string
+		spent         bool
+		jobs          int
+		networkName   string
+		formatName    string
+	)
+
+	flag.BoolVar(&mounted, "mounted", false, usageMounted)
+
+	flag.StringVar(&historyFn, "history", defaultEmpty, usageHistory)
+	flag.StringVar(&knownFirmware, "firmware", defaultEmpty, usageFirmware)
+
+	flag.BoolVar(&spent, "spent", true, usageSpent)
+	flag.IntVar(&jobs, "jobs", accept.DefaultJobs, usageJobs)
+
+	flag.StringVar(&networkName, "network", string(pkg.MainNet), usageNetwork)
+	flag.StringVar(&networkName, "n", string(pkg.MainNet), usageNetwork+" (shorthand)")
+
+	flag.StringVar(&formatName, "format", string(report.Text), usageFormat)
+
+	flag.Usage = func() {
+		fmt.Fprintf(out, "Usage of %s: accept [options] VERIFYTXT|DIR ...\n", os.Args[0])
+
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	format, err := report.ParseFormat(formatName)
+	if err != nil {
+		fmt.Fprintf(out, "Invalid format: %v", err)
+		return 1
+	}
+
+	network, err := pkg.ParseNetwork(networkName)
+	if err != nil {
+		return fail(out, format, "Invalid network: %v", err)
+	}
+
+	var verifyTxtFns []string
+	for _, arg := range flag.Args() {
+		// An Opendime directory (or mount point) rather than its verify.txt
+		if info, err := os.Stat(arg); err == nil && info.IsDir() {
+			arg = filepath.Join(arg, pkg.VerifyTxtPath)
+		}
+
+		verifyTxtFns = append(verifyTxtFns, arg)
+	}
+
+	if mounted {
+		devices, err := discoverDevices()
+		if err != nil {
+			return fail(out, format, "Unable to find Opendime: %v", err)
+		}
+
+		for _, device := range devices {
+			verifyTxtFns = append(verifyTxtFns, device.VerifyTxt)
+		}
+	}
+
+	if len(verifyTxtFns) == 0 {
+		return usage(out, format)
+	}
+
+	seen, err := openHistory(historyFn, knownFirmware)
+	if err != nil {
+		return fail(out, format, "Unable to find history: %v", err)
+	}
+
+	now := acceptNow()
+
+	receipt := accept.Check(verifyTxtFns, accept.Options{
+		Network: network,
+		Fiat:    defaultCurrency,
+		History: seen,
+		Spent:   spent,
+		Jobs:    jobs,
+		Now:     now,
+	})
+
+	if format == report.Text {
+		printReceipt(out, receipt, now)
+	} else if code := writeReport(out, format, report.NewAccept(receipt, now, defaultCurrency)); code != 0 {
+		return code
+	}
+
+	if receipt.Failed > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+func printReceipt(out io.Writer, receipt accept.Receipt, now time.Time) {
+	fmt.Fprintf(out, "Receipt %s\n", now.Format(time.DateTime))
+
+	for idx, result := range receipt.Results {
+		fmt.Fprintf(out, "\n#%d %s\n", idx+1, result.VerifyTxt)
+		fmt.Fprintf(out, "%s%s\n", padLabel("Opendime:"), result.Address)
+		fmt.Fprintf(out, "%s%s\n", padLabel("Serial:"), result.Serial)
+
+		for _, balance := range result.Balances {
+			fmt.Fprintf(out, "%s%.08f = %s%.02f\n", padLabel("- "+balance.Coin), balance.Amount, defaultSymbol, balance.Value)
+		}
+
+		if result.Unchecked > 0 {
+			fmt.Fprintf(out, "%s%d address(es) could not be checked\n", padLabel("Not checked:"), result.Unchecked)
+		}
+
+		if result.SpendUnchecked > 0 {
+			fmt.Fprintf(out, "%s%d address(es) could not be checked\n", padLabel("Spends not checked:"), result.SpendUnchecked)
+		}
+
+		fmt.Fprintf(out, "%s%s%.02f\n", padLabel("Value:"), defaultSymbol, result.Value)
+
+		printVerdict(out, &result.Verdict)
+	}
+
+	accepted := len(receipt.Results) - receipt.Failed
+
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%s%d of %d\n", padLabel("Accepted:"), accepted, len(receipt.Results))
+	fmt.Fprintf(out, "%s%s%.02f\n", padLabel("Total value:"), defaultSymbol, receipt.Value)
+
+	if receipt.Failed > 0 {
+		fmt.Fprintf(out, "%d Opendime(s) FAILED, do not accept them\n", receipt.Failed)
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+	"github.com/timchurchard/opendime-utils/pkg/emulator"
+)
+
+func Test_AcceptMain(t *testing.T) {
+	const (
+		cliName    = "accept"
+		genuineOut = "Serial:\t\t\t\t\tTESTSERIAL\nNot checked:\t\t\t\t16 address(es) could not be checked\nSpends not checked:\t\t\t16 address(es) could not be checked\nValue:\t\t\t\t\t$0.00\nVerdict:\t\t\t\tWARN\n" +
+			"- 16 derived address(es) could not be checked for spends\n- 16 derived address(es) balance could not be checked, the value may be higher\n\nAccepted:\t\t\t\t1 of 1\nTotal value:\t\t\t\t$0.00\n"
+	)
+
+	// Regtest has no balance or spend history APIs so nothing is fetched
+	genuine, clone := t.TempDir(), t.TempDir()
+	if _, err := emulator.Create(genuine, emulator.Options{Network: pkg.RegTest, Serial: "TESTSERIAL"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := emulator.Create(clone, emulator.Options{Network: pkg.RegTest, Serial: "TESTSERIAL"}); err != nil {
+		t.Fatal(err)
+	}
+
+	historyFn := filepath.Join(t.TempDir(), "history.json")
+
+	oldArgs, oldNow := os.Args, acceptNow
+	defer func() { os.Args, acceptNow = oldArgs, oldNow }()
+
+	acceptNow = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    int
+		wantOut []string
+	}{
+		{"genuine", []string{genuine}, 0, []string{"Receipt 2024-05-01 12:00:00\n\n#1 " + filepath.Join(genuine, pkg.VerifyTxtPath) + "\n", genuineOut}},
+		{"replay", []string{filepath.Join(genuine, pkg.VerifyTxtPath)}, 0, []string{"Verdict:\t\t\t\tWARN\n- nonce "}},
+		{"clone", []string{genuine, clone}, 1, []string{"#2 ", "Verdict:\t\t\t\tFAIL\n- serial TESTSERIAL was seen with a different key", "Accepted:\t\t\t\t1 of 2\n", "1 Opendime(s) FAILED, do not accept them\n"}},
+		{"clone csv", []string{"-format", "csv", genuine, clone}, 1, []string{"verify_txt,opendime,serial,value,currency,unchecked,spend_unchecked,verdict,accepted\n", ",TESTSERIAL,0.00,usd,16,16,FAIL,false\n"}},
+		{"no Opendimes json", []string{"-format", "json"}, 1, []string{"{\n  \"error\": \"Missing arguments, run with -h for usage\"\n}\n"}},
+		{"no Opendimes", []string{}, 1, []string{"Usage of accept: accept [options] VERIFYTXT|DIR ...\n"}},
+	}
+	for _, tt := range tests {
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = append([]string{cliName, "-n", "regtest", "-history", historyFn, "-jobs", "1"}, tt.args...)
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+
+			if got := AcceptMain(out); got != tt.want {
+				t.Errorf("AcceptMain() = %v, want %v", got, tt.want)
+			}
+
+			for _, wantOut := range tt.wantOut {
+				if gotOut := out.String(); !strings.Contains(gotOut, wantOut) {
+					t.Errorf("AcceptMain() = %q, want %q", gotOut, wantOut)
+				}
+			}
+		})
+	}
+}
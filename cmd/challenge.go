@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/internal"
+	"github.com/timchurchard/opendime-utils/internal/report"
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// challengePollInterval how often verify.txt is re-read while waiting for the Opendime
+var challengePollInterval = time.Second / 4
+
+// ChallengeMain entrypoint for the challenge command
+func ChallengeMain(out io.Writer) int {
+	const (
+		defaultEmpty   = ""
+		defaultDevice  = "auto"
+		defaultTimeout = 30 * time.Second
+		usageVerbose   = "Verbose mode"
+		usagePath      = "Path to the root of a mounted Opendime (default discover with -device)"
+		usageDevice    = "Mounted Opendime to challenge: auto or device index"
+		usageTimeout   = "How long to wait for the Opendime to sign the nonce"
+		usageNetwork   = "Network: mainnet, testnet, signet or regtest"
+		usageFormat    = "Output format: text, json, csv or yaml"
+	)
+	var (
+		verbose     bool
+		path        string
+		device      string
+		timeout     time.Duration
+		networkName string
+		formatName  string
+	)
+
+	flag.BoolVar(&verbose, "verbose", false, usageVerbose)
+	flag.BoolVar(&verbose, "v", false, usageVerbose)
+
+	flag.StringVar(&path, "path", defaultEmpty, usagePath)
+	flag.StringVar(&path, "p", defaultEmpty, usagePath+" (shorthand)")
+
+	flag.StringVar(&device, "device", defaultDevice, usageDevice)
+
+	flag.DurationVar(&timeout, "timeout", defaultTimeout, usageTimeout)
+
+	flag.StringVar(&networkName, "network", string(pkg.MainNet), usageNetwork)
+	flag.StringVar(&networkName, "n", string(pkg.MainNet), usageNetwork+" (shorthand)")
+
+	flag.StringVar(&formatName, "format", string(report.Text), usageFormat)
+
+	flag.Usage = func() {
+		fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
+
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	format, err := report.ParseFormat(formatName)
+	if err != nil {
+		fmt.Fprintf(out, "Invalid format: %v", err)
+		return 1
+	}
+
+	network, err := pkg.ParseNetwork(networkName)
+	if err != nil {
+		return fail(out, format, "Invalid network: %v", err)
+	}
+
+	if path == "" {
+		devices, err := discoverDevices()
+		if err == nil {
+			var selected internal.Device
+			selected, err = internal.SelectDevice(devices, device)
+			path = selected.MountPoint
+		}
+		if err != nil {
+			return fail(out, format, "Unable to find Opendime: %v", err)
+		}
+	}
+
+	nonce, err := internal.NewChallengeNonce()
+	if err != nil {
+		return fail(out, format, "Unable to make nonce: %v", err)
+	}
+
+	text := format == report.Text
+
+	if verbose && text {
+		fmt.Fprintf(out, "Challenge nonce: %s\n", nonce)
+	}
+
+	result, err := internal.Challenge(network, path, nonce, timeout, challengePollInterval)
+	if err != nil {
+		return fail(out, format, "Challenge failed: %v", err)
+	}
+
+	if !text {
+		return writeReport(out, format, report.NewChallenge(path, result))
+	}
+
+	if verbose {
+		printStatement(out, result.Statement)
+	}
+
+	fmt.Fprintf(out, "Challenge passed: %s signed our fresh nonce\n", result.VerifiedMessage.Address)
+
+	return 0
+}
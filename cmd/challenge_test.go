@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/internal/emulatortest"
+	"github.com/timchurchard/opendime-utils/pkg/emulator"
+)
+
+func Test_ChallengeMain(t *testing.T) {
+	const cliName = "challenge"
+
+	oldArgs, oldPoll := os.Args, challengePollInterval
+	defer func() { os.Args, challengePollInterval = oldArgs, oldPoll }()
+
+	challengePollInterval = 5 * time.Millisecond
+
+	tests := []struct {
+		name          string
+		format        string
+		watch         bool
+		want          int
+		wantOutPrefix string
+	}{
+		{"device signs nonce", "text", true, 0, "Challenge passed: 13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu signed our fresh nonce\n"},
+		{"static copy", "text", false, 1, "Challenge failed: timed out waiting for the Opendime to sign the challenge nonce (verify.txt nonce is still "},
+		{"device signs nonce json", "json", true, 0, "{\n  \"mountPoint\": \""},
+		{"static copy json", "json", false, 1, "{\n  \"error\": \"Challenge failed: timed out waiting for the Opendime to sign the challenge nonce"},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+
+		device, err := emulator.Create(dir, emulator.Options{Key: emulatortest.TestWif})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = []string{cliName, "-p", dir, "-timeout", "500ms", "-format", tt.format}
+
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.watch {
+				stop := make(chan struct{})
+				defer close(stop)
+
+				go device.Watch(stop, challengePollInterval) //nolint:errcheck
+			}
+
+			out := &bytes.Buffer{}
+			if got := ChallengeMain(out); got != tt.want {
+				t.Errorf("ChallengeMain() = %v, want %v", got, tt.want)
+			}
+
+			if gotOut := out.String(); !strings.HasPrefix(gotOut, tt.wantOutPrefix) {
+				t.Errorf("ChallengeMain() = %q, want prefix %q", gotOut, tt.wantOutPrefix)
+			}
+		})
+	}
+}
@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	ecies "github.com/ecies/go/v2"
 
+	"github.com/timchurchard/opendime-utils/internal/report"
 	"github.com/timchurchard/opendime-utils/pkg"
 )
 
@@ -21,24 +23,32 @@ func CryptMain(out io.Writer) int { // nolint: gocyclo // todo: This func is sup
 		usageVerbose    = "Verbose mode"
 		usageEncrypt    = "Encrypt"
 		usageDecrypt    = "Decrypt"
-		usageVerifyTxt  = "Path to OPENDIME/advanced/verify.txt (to encrypt for) alternative to passing (address, signature and message)"
-		usageAddress    = "Bitcoin or Litecoin address to encrypt for. Optional with verify.txt"
-		usageSignature  = "Bitcoin or Litecoin signature (required if verify.txt not used)"
+		usageVerifyTxt  = "Path to OPENDIME/advanced/verify.txt or a message signed by any wallet (to encrypt for, - for stdin) alternative to passing (address, signature and message)"
+		usageAddress    = "Bitcoin, Litecoin or Ethereum (0x) address to encrypt for. Optional with verify.txt"
+		usageSignature  = "Bitcoin or Litecoin signature, or Ethereum personal_sign hex signature (required if verify.txt not used)"
 		usageMessage    = "Bitcoin message (required if verify.txt not used)"
-		usageKey        = "Private key in WIF format (for decrypt)"
+		usageKey        = "Private key in WIF or hex (eg an Ethereum 0x key) format (for decrypt)"
 		usageInput      = "Input string to encrypt/decrypt"
 		usageInputFile  = "Path to input file"
 		usageOutput     = "Output as string"
 		usageOutputFile = "Path to output file"
+		usageNetwork    = "Network: mainnet, testnet, signet or regtest"
+		usageDevice     = "Encrypt for a mounted Opendime: auto or device index"
+		usageFormat     = "Output format: text, json, csv or yaml"
 	)
 	var (
 		err             error
 		verifiedMessage pkg.VerifiedMessage
+		network         pkg.Network
+		networkName     string
+		formatName      string
+		format          report.Format
 		verbose         bool
 		encrypt         bool
 		decrypt         bool
 		output          bool
 		verifyTxtFn     string
+		device          string
 		address         string
 		signature       string
 		message         string
@@ -61,6 +71,7 @@ func CryptMain(out io.Writer) int { // nolint: gocyclo // todo: This func is sup
 	flag.BoolVar(&decrypt, "d", false, usageDecrypt)
 
 	flag.StringVar(&verifyTxtFn, "verifytxt", defaultEmpty, usageVerifyTxt)
+	flag.StringVar(&device, "device", defaultEmpty, usageDevice)
 
 	flag.StringVar(&address, "address", defaultEmpty, usageAddress)
 	flag.StringVar(&address, "a", defaultEmpty, usageAddress+" (shorthand)")
@@ -83,6 +94,11 @@ func CryptMain(out io.Writer) int { // nolint: gocyclo // todo: This func is sup
 	flag.StringVar(&inputFn, "inputfile", defaultEmpty, usageInputFile)
 	flag.StringVar(&outputFn, "outputfile", defaultEmpty, usageOutputFile)
 
+	flag.StringVar(&networkName, "network", string(pkg.MainNet), usageNetwork)
+	flag.StringVar(&networkName, "n", string(pkg.MainNet), usageNetwork+" (shorthand)")
+
+	flag.StringVar(&formatName, "format", string(report.Text), usageFormat)
+
 	flag.Usage = func() {
 		fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
 
@@ -94,40 +110,56 @@ func CryptMain(out io.Writer) int { // nolint: gocyclo // todo: This func is sup
 	// note! terrible sleep because of flag.Parse not finished by the next if ??
 	time.Sleep(time.Millisecond)
 
+	format, err = report.ParseFormat(formatName)
+	if err != nil {
+		fmt.Fprintf(out, "Invalid format: %v", err)
+		return 1
+	}
+
+	network, err = pkg.ParseNetwork(networkName)
+	if err != nil {
+		return fail(out, format, "Invalid network: %v", err)
+	}
+
+	if device != "" {
+		verifyTxtFn, err = deviceVerifyTxt(device)
+		if err != nil {
+			return fail(out, format, "Unable to find Opendime: %v", err)
+		}
+	}
+
 	if verifyTxtFn != "" {
-		address, signature, message, err = pkg.ParseVerifyTxt(verifyTxtFn)
+		address, signature, message, err = parseVerifyTxt(verifyTxtFn)
 		if errors.Is(err, os.ErrNotExist) {
-			fmt.Fprintf(out, "File '%s' not found", verifyTxtFn)
-			return 1
+			return fail(out, format, "File '%s' not found", verifyTxtFn)
 		}
 		if err != nil {
-			fmt.Fprintf(out, "Unable to parse verify.txt: %v", err)
-			return 1
+			return fail(out, format, "Unable to parse verify.txt: %v", err)
 		}
 	} else if address == "" && privateKey == "" {
 		// verify.txt or (address, signature, message) is required so print usage
-		flag.Usage()
-		return 1
+		return usage(out, format)
 	}
 
 	if privateKey == "" {
-		verifiedMessage, err = pkg.VerifyMessage(address, signature, message)
+		verifiedMessage, err = pkg.VerifyMessage(network, address, signature, message)
 		if err != nil {
-			fmt.Fprintf(out, "Unable to verify signature: %v", err)
-			return 1
+			return fail(out, format, "Unable to verify signature: %v", err)
 		}
 	}
 
 	if input == "" && inputFn == "" {
-		flag.Usage()
-		return 1
+		return usage(out, format)
 	}
 	if !output && outputFn == "" {
-		flag.Usage()
-		return 1
+		return usage(out, format)
 	}
 
 	if encrypt && !decrypt {
+		if verifiedMessage.PublicKeyHex == "" {
+			return fail(out, format, "Unable to encrypt: signature for %s does not reveal the public key", address)
+		}
+
 		publicKey, _ := ecies.NewPublicKeyFromHex(verifiedMessage.PublicKeyHex)
 
 		if input != "" {
@@ -135,78 +167,102 @@ func CryptMain(out io.Writer) int { // nolint: gocyclo // todo: This func is sup
 		} else {
 			data, err := os.ReadFile(inputFn)
 			if err != nil {
-				fmt.Fprintf(out, "Error reading input file: %s %v", inputFn, err)
-				return 1
+				return fail(out, format, "Error reading input file: %s %v", inputFn, err)
 			}
 
 			cipherText, err = ecies.Encrypt(publicKey, []byte(data))
 			if err != nil {
-				fmt.Fprintf(out, "Error encrypting data file: %s %v", inputFn, err)
-				return 1
+				return fail(out, format, "Error encrypting data file: %s %v", inputFn, err)
 			}
 		}
 
 		if err != nil {
-			fmt.Fprintf(out, "Error encrypting data: %v", err)
+			return fail(out, format, "Error encrypting data: %v", err)
 		}
 
-		fmt.Fprintf(out, "Encrypted message for %s\n", address)
+		result := report.Crypt{Operation: report.Encrypt, Address: address}
+
 		if output {
-			fmt.Fprintf(out, "%s\n", base64.StdEncoding.EncodeToString(cipherText))
+			result.SetOutput(cipherText)
 		} else {
 			err = os.WriteFile(outputFn, cipherText, 0o600)
 			if err != nil {
-				fmt.Fprintf(out, "Error writing output file: %s %v", outputFn, err)
-				return 1
+				return fail(out, format, "Error writing output file: %s %v", outputFn, err)
 			}
+			result.File = outputFn
+		}
+
+		if format != report.Text {
+			return writeCrypt(out, format, result)
+		}
+
+		fmt.Fprintf(out, "Encrypted message for %s\n", address)
+		if output {
+			fmt.Fprintf(out, "%s\n", result.Output)
+		} else {
 			fmt.Fprintf(out, "Written to file: %s\n", outputFn)
 		}
 	} else if decrypt && !encrypt {
-		_, secretHex, _, err := pkg.ValidateWif(privateKey)
+		// A hex key (eg an Ethereum 0x key) works as well as a WIF
+		_, secretHex, _, err := pkg.ValidateWif(network, parseKey(network, strings.TrimPrefix(strings.TrimPrefix(privateKey, "0x"), "0X")))
 		if err != nil {
-			fmt.Fprintf(out, "Error decoding WIF: %v", err)
-			return 1
+			return fail(out, format, "Error decoding WIF: %v", err)
 		}
 
 		privateKey, err := ecies.NewPrivateKeyFromHex(secretHex)
 		if err != nil {
-			fmt.Fprintf(out, "Error building private key: %v", err)
-			return 1
+			return fail(out, format, "Error building private key: %v", err)
 		}
 
 		if input != "" {
 			rawInput, err = base64.StdEncoding.DecodeString(input)
 			if err != nil {
-				fmt.Fprintf(out, "Error decoding base64 input: %v", err)
-				return 1
+				return fail(out, format, "Error decoding base64 input: %v", err)
 			}
 		} else {
 			rawInput, err = os.ReadFile(inputFn)
 			if err != nil {
-				fmt.Fprintf(out, "Error reading input file: %s %v", inputFn, err)
-				return 1
+				return fail(out, format, "Error reading input file: %s %v", inputFn, err)
 			}
 		}
 
 		plainText, err = ecies.Decrypt(privateKey, rawInput)
 		if err != nil {
-			fmt.Fprintf(out, "Error decrypting: %v", err)
-			return 1
+			return fail(out, format, "Error decrypting: %v", err)
 		}
 
+		result := report.Crypt{Operation: report.Decrypt}
+
 		if output {
-			fmt.Fprintf(out, "Decrypted message\n%s\n", plainText)
+			result.SetOutput(plainText)
 		} else {
 			err = os.WriteFile(outputFn, plainText, 0o600)
 			if err != nil {
-				fmt.Fprintf(out, "Error writing output file: %s %v", outputFn, err)
-				return 1
+				return fail(out, format, "Error writing output file: %s %v", outputFn, err)
 			}
+			result.File = outputFn
+		}
+
+		if format != report.Text {
+			return writeCrypt(out, format, result)
+		}
+
+		if output {
+			fmt.Fprintf(out, "Decrypted message\n%s\n", plainText)
+		} else {
 			fmt.Fprintf(out, "Decrypted message\nWritten to file: %s\n", outputFn)
 		}
 	} else {
-		flag.Usage()
-		return 1
+		return usage(out, format)
+	}
+
+	return 0
+}
+
+// writeCrypt writes the crypt report in a machine readable format and returns the exit code
+func writeCrypt(out io.Writer, format report.Format, result report.Crypt) int {
+	if err := report.Write(out, format, result); err != nil {
+		return fail(out, format, "Error writing output: %v", err)
 	}
 
 	return 0
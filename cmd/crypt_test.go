@@ -2,10 +2,15 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"flag"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+
+	"github.com/timchurchard/opendime-utils/internal/report"
 )
 
 func Test_CryptMain(t *testing.T) {
@@ -51,6 +56,48 @@ func Test_CryptMain(t *testing.T) {
 			},
 			want:    0,
 			wantOut: validDecryptOut,
+		}, {
+			name: "valid decrypt testnet key",
+			args: []string{
+				"-i",
+				"BFHzllfvzCZbKFXMnTUKitlPlAqiuKXEvs2PopPKx205bZS0GHdvmUaAG2p0R9aBJ3rSiHXrmG4DY7SZS3BKuRyj8Udv2thl/zdAkbuNjs1q98i6FPHLIkAsOaTveAH8cFsFlcwEAZIeA9ExqdpoNhyIU01yS0E=",
+				"-k",
+				"cRT4vRkMK3s5EQm1eHJZ6TQh6LES9QmcZPtbiHbv4gdzdX4LWjLA",
+				"-network",
+				"testnet",
+				"-d",
+				"-o",
+			},
+			want:    0,
+			wantOut: validDecryptOut,
+		}, {
+			name: "invalid testnet key on mainnet",
+			args: []string{
+				"-i",
+				"BFHzllfvzCZbKFXMnTUKitlPlAqiuKXEvs2PopPKx205bZS0GHdvmUaAG2p0R9aBJ3rSiHXrmG4DY7SZS3BKuRyj8Udv2thl/zdAkbuNjs1q98i6FPHLIkAsOaTveAH8cFsFlcwEAZIeA9ExqdpoNhyIU01yS0E=",
+				"-k",
+				"cRT4vRkMK3s5EQm1eHJZ6TQh6LES9QmcZPtbiHbv4gdzdX4LWjLA",
+				"-d",
+				"-o",
+			},
+			want:    1,
+			wantOut: "Error decoding WIF: WIF malformed/wrong prefix byte",
+		}, {
+			name: "invalid encrypt to bip322 p2tr",
+			args: []string{
+				"--address",
+				"bc1ppv609nr0vr25u07u95waq5lucwfm6tde4nydujnu8npg4q75mr5sxq8lt3",
+				"--message",
+				"Hello World",
+				"--signature",
+				"AUHd69PrJQEv+oKTfZ8l+WROBHuy9HKrbFCJu7U1iK2iiEy1vMU5EfMtjc+VSHM7aU0SDbak5IUZRVno2P5mjSafAQ==",
+				"-e",
+				"-i",
+				"Test Message for crypt",
+				"-o",
+			},
+			want:    1,
+			wantOut: "Unable to encrypt: signature for bc1ppv609nr0vr25u07u95waq5lucwfm6tde4nydujnu8npg4q75mr5sxq8lt3 does not reveal the public key",
 		},
 	}
 	for _, tt := range tests {
@@ -71,3 +118,96 @@ func Test_CryptMain(t *testing.T) {
 		})
 	}
 }
+
+func Test_CryptMainFormat(t *testing.T) {
+	const (
+		cliName    = "crypt"
+		cipherText = "BFHzllfvzCZbKFXMnTUKitlPlAqiuKXEvs2PopPKx205bZS0GHdvmUaAG2p0R9aBJ3rSiHXrmG4DY7SZS3BKuRyj8Udv2thl/zdAkbuNjs1q98i6FPHLIkAsOaTveAH8cFsFlcwEAZIeA9ExqdpoNhyIU01yS0E="
+		key        = "L165TWkVszAp4yHkFsVRj8udU6w2UxfvVMk8bs9QZZyzNmwWVprK"
+	)
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	outputFn := filepath.Join(t.TempDir(), "plain.txt")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    int
+		wantOut string
+	}{
+		{"decrypt json", []string{"-i", cipherText, "-k", key, "-d", "-o", "-format", "json"}, 0,
+			"{\n  \"operation\": \"decrypt\",\n  \"output\": \"Test Message for crypt\",\n  \"encoding\": \"text\"\n}\n"},
+		{"decrypt to file yaml", []string{"-i", cipherText, "-k", key, "-d", "-outputfile", outputFn, "-format", "yaml"}, 0,
+			"operation: decrypt\nfile: " + strconv.Quote(outputFn) + "\n"},
+		{"decrypt csv", []string{"-i", cipherText, "-k", key, "-d", "-o", "-format", "csv"}, 0,
+			"operation,address,output,encoding,file\ndecrypt,,Test Message for crypt,text,\n"},
+		{"error json", []string{"-i", cipherText, "-k", "cRT4vRkMK3s5EQm1eHJZ6TQh6LES9QmcZPtbiHbv4gdzdX4LWjLA", "-d", "-o", "-format", "json"}, 1,
+			"{\n  \"error\": \"Error decoding WIF: WIF malformed/wrong prefix byte\"\n}\n"},
+		{"usage yaml", []string{"-d", "-format", "yaml"}, 1, "error: \"Missing arguments, run with -h for usage\"\n"},
+		{"unknown format", []string{"-format", "xml"}, 1, "Invalid format: unknown format 'xml' must be text, json, csv or yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+			os.Args = append([]string{cliName}, tt.args...)
+
+			out := &bytes.Buffer{}
+			if got := CryptMain(out); got != tt.want {
+				t.Errorf("CryptMain() = %v, want %v", got, tt.want)
+			}
+
+			if gotOut := out.String(); gotOut != tt.wantOut {
+				t.Errorf("CryptMain() = %q, want %q", gotOut, tt.wantOut)
+			}
+		})
+	}
+
+	// Encrypted output changes every time so decode it and check the fields
+	flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+	os.Args = []string{cliName, "--address", "133r6sCjLq6NbmSLjxuypDuSeocwenu1Qg", "--message", "Hello World", "--signature",
+		"Hz0pDxS09fEdjKrSJenLYsz05gakT6eW9GdzKDopnlwUL3bf3mQeJcS+takCIMuavK/NLOorXWXZCqV0KBMlwgU=", "-e", "-i", "secret", "-o", "-format", "json"}
+
+	out := &bytes.Buffer{}
+	if got := CryptMain(out); got != 0 {
+		t.Errorf("CryptMain() = %v, want 0", got)
+	}
+
+	var result report.Crypt
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil || result.Operation != report.Encrypt ||
+		result.Address != "133r6sCjLq6NbmSLjxuypDuSeocwenu1Qg" || result.Encoding != "base64" || result.Output == "" {
+		t.Errorf("CryptMain() = %s, %v", out.String(), err)
+	}
+}
+
+func Test_CryptMainEthereum(t *testing.T) {
+	const cliName = "crypt"
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	// Encrypt for an Ethereum user from their personal_sign signature
+	flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+	os.Args = []string{cliName, "-a", "0xCb19D769c583599DbD7D6D78Eb3279a362672747", "-m", "Hello World", "-s",
+		"0x4dcbefe707f377a2ae1463dbbd02637c706f2a7d080bd8e8d023d51c1a95a47d762f227bdcda89a3e6090073437e8f4beeb74781eb0605fb6ae9bf53378de8701b",
+		"-e", "-i", "Test Message for crypt", "-o"}
+
+	out := &bytes.Buffer{}
+	if exit := CryptMain(out); exit != 0 {
+		t.Fatalf("CryptMain() encrypt exit = %d, out %s", exit, out.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+
+	// And they decrypt with their 0x (or 0X) private key
+	for _, key := range []string{"0xdc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3", "0XDC192045A9261A395445D220890D0969FD7DD2BACEC12B9AB3C9827CB0DF7BF3"} {
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = []string{cliName, "-k", key, "-d", "-i", lines[len(lines)-1], "-o"}
+
+		out.Reset()
+		if exit := CryptMain(out); exit != 0 || out.String() != "Decrypted message\nTest Message for crypt\n" {
+			t.Errorf("CryptMain() decrypt with %s exit = %d, out %q", key, exit, out.String())
+		}
+	}
+}
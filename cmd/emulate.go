@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/internal/report"
+	"github.com/timchurchard/opendime-utils/pkg"
+	"github.com/timchurchard/opendime-utils/pkg/emulator"
+)
+
+// EmulateMain entrypoint for the emulate command
+func EmulateMain(out io.Writer) int {
+	const (
+		defaultEmpty  = ""
+		usageDir      = "Directory to create the emulated Opendime in"
+		usageCoin     = "Coin eg Litecoin (default Bitcoin)"
+		usageKey      = "Private key WIF (default generate a new key)"
+		usageSerial   = "Serial number (default random)"
+		usageFirmware = "Firmware version"
+		usageUnsealed = "Make an unsealed Opendime with private-key.txt"
+		usageWatch    = "Keep running and answer challenge nonces for this long eg 5m (default exit straight away)"
+		usageNetwork  = "Network: mainnet, testnet, signet or regtest"
+		usageFormat   = "Output format: text, json, csv or yaml"
+	)
+	var (
+		dir         string
+		options     emulator.Options
+		watch       time.Duration
+		networkName string
+		formatName  string
+	)
+
+	flag.StringVar(&dir, "dir", defaultEmpty, usageDir)
+	flag.StringVar(&dir, "d", defaultEmpty, usageDir+" (shorthand)")
+
+	flag.StringVar(&options.Coin, "coin", defaultEmpty, usageCoin)
+	flag.StringVar(&options.Key, "key", defaultEmpty, usageKey)
+	flag.StringVar(&options.Key, "k", defaultEmpty, usageKey+" (shorthand)")
+	flag.StringVar(&options.Serial, "serial", defaultEmpty, usageSerial)
+	flag.StringVar(&options.Firmware, "firmware", emulator.DefaultFirmware, usageFirmware)
+	flag.BoolVar(&options.Unsealed, "unsealed", false, usageUnsealed)
+
+	flag.DurationVar(&watch, "watch", 0, usageWatch)
+
+	flag.StringVar(&networkName, "network", string(pkg.MainNet), usageNetwork)
+	flag.StringVar(&networkName, "n", string(pkg.MainNet), usageNetwork+" (shorthand)")
+
+	flag.StringVar(&formatName, "format", string(report.Text), usageFormat)
+
+	flag.Usage = func() {
+		fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
+
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	format, err := report.ParseFormat(formatName)
+	if err != nil {
+		fmt.Fprintf(out, "Invalid format: %v", err)
+		return 1
+	}
+
+	network, err := pkg.ParseNetwork(networkName)
+	if err != nil {
+		return fail(out, format, "Invalid network: %v", err)
+	}
+	options.Network = network
+
+	if dir == "" {
+		return usage(out, format)
+	}
+
+	device, err := emulator.Create(dir, options)
+	if err != nil {
+		return fail(out, format, "Unable to emulate Opendime: %v", err)
+	}
+
+	text := format == report.Text
+
+	if text {
+		state := "sealed"
+		if device.Unsealed {
+			state = "unsealed"
+		}
+
+		fmt.Fprintf(out, "Emulated %s %s Opendime in %s\n", state, device.Coin, device.Dir)
+		fmt.Fprintf(out, "%s%s\n", padLabel("Address:"), device.Address)
+		fmt.Fprintf(out, "%s%s\n", padLabel("Serial:"), device.Serial)
+	} else if code := writeReport(out, format, report.Emulate{
+		Dir:      device.Dir,
+		Coin:     device.Coin,
+		Network:  device.Network,
+		Address:  device.Address,
+		Serial:   device.Serial,
+		Firmware: device.Firmware,
+		Unsealed: device.Unsealed,
+	}); code != 0 {
+		return code
+	}
+
+	if watch > 0 {
+		if text {
+			fmt.Fprintf(out, "Answering challenges for %v\n", watch)
+		}
+
+		stop := make(chan struct{})
+		time.AfterFunc(watch, func() { close(stop) })
+
+		if err := device.Watch(stop, challengePollInterval); err != nil {
+			return fail(out, format, "Unable to answer challenge: %v", err)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/timchurchard/opendime-utils/internal"
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+func Test_EmulateMain(t *testing.T) {
+	const cliName = "emulate"
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	dir, litecoinDir := filepath.Join(t.TempDir(), "OPENDIME"), filepath.Join(t.TempDir(), "OPENDIME")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    int
+		wantOut string
+	}{
+		{
+			name:    "unsealed with key",
+			args:    []string{"-d", dir, "-k", "5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC", "-serial", "TESTSERIAL", "-unsealed"},
+			want:    0,
+			wantOut: "Emulated unsealed Bitcoin Opendime in " + dir + "\nAddress:\t\t\t\t13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu\nSerial:\t\t\t\t\tTESTSERIAL\n",
+		}, {
+			name:    "unknown coin",
+			args:    []string{"-d", dir, "-coin", "Ethereum"},
+			want:    1,
+			wantOut: "Unable to emulate Opendime: coin 'Ethereum' can not be emulated, it has no signed messages",
+		}, {
+			name:    "litecoin yaml",
+			args:    []string{"-d", litecoinDir, "-k", "5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC", "-coin", "Litecoin", "-serial", "TESTSERIAL", "-format", "yaml"},
+			want:    0,
+			wantOut: "dir: \"" + litecoinDir + "\"\ncoin: Litecoin\nnetwork: mainnet\naddress: LMrjSZiwF9FG73FTUP9pv4Tp7vtudfiMG7\nserial: TESTSERIAL\nfirmware: \"2.4.0\"\nunsealed: false\n",
+		},
+	}
+	for _, tt := range tests {
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = append([]string{cliName}, tt.args...)
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if got := EmulateMain(out); got != tt.want {
+				t.Errorf("EmulateMain() = %v, want %v", got, tt.want)
+			}
+
+			if gotOut := out.String(); gotOut != tt.wantOut {
+				t.Errorf("EmulateMain() = %q, want %q", gotOut, tt.wantOut)
+			}
+		})
+	}
+
+	// The emulated Opendime passes inspect
+	inspection, err := internal.InspectVolume(pkg.MainNet, dir)
+	if err != nil || !inspection.OK() || !inspection.PrivateKeyAgrees {
+		t.Errorf("InspectVolume() = %+v, %v", inspection, err)
+	}
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/timchurchard/opendime-utils/internal"
+	"github.com/timchurchard/opendime-utils/internal/report"
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// InspectMain entrypoint for the inspect command
+func InspectMain(out io.Writer) int {
+	const (
+		defaultEmpty  = ""
+		defaultDevice = "auto"
+		usageVerbose  = "Verbose mode"
+		usagePath     = "Path to the root of a mounted Opendime (default discover with -device)"
+		usageDevice   = "Mounted Opendime to inspect: auto or device index"
+		usageNetwork  = "Network: mainnet, testnet, signet or regtest"
+		usageFormat   = "Output format: text, json, csv or yaml"
+	)
+	var (
+		verbose     bool
+		path        string
+		device      string
+		networkName string
+		formatName  string
+	)
+
+	flag.BoolVar(&verbose, "verbose", false, usageVerbose)
+	flag.BoolVar(&verbose, "v", false, usageVerbose)
+
+	flag.StringVar(&path, "path", defaultEmpty, usagePath)
+	flag.StringVar(&path, "p", defaultEmpty, usagePath+" (shorthand)")
+
+	flag.StringVar(&device, "device", defaultDevice, usageDevice)
+
+	flag.StringVar(&networkName, "network", string(pkg.MainNet), usageNetwork)
+	flag.StringVar(&networkName, "n", string(pkg.MainNet), usageNetwork+" (shorthand)")
+
+	flag.StringVar(&formatName, "format", string(report.Text), usageFormat)
+
+	flag.Usage = func() {
+		fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
+
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	format, err := report.ParseFormat(formatName)
+	if err != nil {
+		fmt.Fprintf(out, "Invalid format: %v", err)
+		return 1
+	}
+
+	network, err := pkg.ParseNetwork(networkName)
+	if err != nil {
+		return fail(out, format, "Invalid network: %v", err)
+	}
+
+	if path == "" {
+		devices, err := discoverDevices()
+		if err == nil {
+			var selected internal.Device
+			selected, err = internal.SelectDevice(devices, device)
+			path = selected.MountPoint
+		}
+		if err != nil {
+			return fail(out, format, "Unable to find Opendime: %v", err)
+		}
+	}
+
+	inspection, err := internal.InspectVolume(network, path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fail(out, format, "'%s' is not an Opendime, verify.txt not found", path)
+	}
+	if err != nil {
+		return fail(out, format, "Unable to parse verify.txt: %v", err)
+	}
+
+	if format == report.Text {
+		printInspection(out, inspection, verbose)
+	} else if code := writeReport(out, format, report.NewInspect(inspection)); code != 0 {
+		return code
+	}
+
+	if !inspection.OK() {
+		return 1
+	}
+
+	return 0
+}
+
+func printInspection(out io.Writer, inspection internal.Inspection, verbose bool) {
+	state := "sealed"
+	if inspection.Unsealed {
+		state = "UNSEALED"
+	}
+
+	signature := "valid"
+	if !inspection.SignatureValid {
+		signature = "INVALID"
+	}
+
+	fmt.Fprintf(out, "%s%s\n", padLabel("Opendime:"), inspection.MountPoint)
+	fmt.Fprintf(out, "%s%s\n", padLabel("State:"), state)
+	fmt.Fprintf(out, "%s%s\n", padLabel("Firmware:"), inspection.Statement.Firmware)
+	fmt.Fprintf(out, "%s%s\n", padLabel("Serial:"), inspection.Statement.Serial)
+	fmt.Fprintf(out, "%s%s\n", padLabel("Coin:"), inspection.Statement.Coin)
+	fmt.Fprintf(out, "%s%s\n", padLabel("Address:"), inspection.Address)
+	fmt.Fprintf(out, "%s%s\n", padLabel("Signature:"), signature)
+
+	if verbose {
+		for _, file := range inspection.Files {
+			agrees := "agrees"
+			switch {
+			case len(file.Addresses) == 0:
+				agrees = "no address"
+			case !file.Agrees:
+				agrees = "DISAGREES"
+			}
+
+			fmt.Fprintf(out, "%s%s\n", padLabel("- "+file.Name), agrees)
+		}
+	}
+
+	if inspection.PrivateKeyChecked {
+		privateKey := "derives address"
+		if !inspection.PrivateKeyAgrees {
+			privateKey = "DOES NOT derive address"
+		}
+
+		fmt.Fprintf(out, "%s%s\n", padLabel("Private key:"), privateKey)
+	}
+
+	if inspection.OK() {
+		fmt.Fprintln(out, "All files agree")
+		return
+	}
+
+	fmt.Fprintf(out, "Found %d problem(s):\n", len(inspection.Problems))
+	for _, problem := range inspection.Problems {
+		fmt.Fprintf(out, "- %s\n", problem)
+	}
+}
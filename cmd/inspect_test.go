@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/timchurchard/opendime-utils/internal"
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+func Test_InspectMain(t *testing.T) {
+	const (
+		cliName         = "inspect"
+		expectedDetails = "State:\t\t\t\t\tsealed\nFirmware:\t\t\t\t2.4.0\nSerial:\t\t\t\t\tDDRRNOCZJRIFCIBAEBJDOJQY74\nCoin:\t\t\t\t\tBTC\nAddress:\t\t\t\t1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR\nSignature:\t\t\t\tvalid\n"
+	)
+
+	verifyTxt, err := os.ReadFile("../verify.txt_tips")
+	if err != nil {
+		t.Skip("verify.txt_tips not found")
+	}
+
+	media := t.TempDir()
+	good := filepath.Join(media, "OPENDIME")
+	bad := filepath.Join(t.TempDir(), "BADDIME")
+
+	for dir, address := range map[string]string{good: "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR", bad: "1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f"} {
+		if err := os.MkdirAll(filepath.Join(dir, "advanced"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, pkg.VerifyTxtPath), verifyTxt, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, pkg.AddressTxtName), []byte(address+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldArgs, oldDiscover := os.Args, discoverDevices
+	defer func() { os.Args, discoverDevices = oldArgs, oldDiscover }()
+
+	discoverDevices = internal.Discoverer{Roots: []string{media}}.Discover
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    int
+		wantOut string
+	}{
+		{
+			name:    "auto discover verbose",
+			args:    []string{"-v"},
+			want:    0,
+			wantOut: "Opendime:\t\t\t\t" + good + "\n" + expectedDetails + "- address.txt\t\t\t\tagrees\nAll files agree\n",
+		}, {
+			name:    "path disagrees",
+			args:    []string{"-p", bad},
+			want:    1,
+			wantOut: "Opendime:\t\t\t\t" + bad + "\n" + expectedDetails + "Found 1 problem(s):\n- address.txt mentions 1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f not 1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR\n",
+		}, {
+			name:    "not an opendime",
+			args:    []string{"-p", media},
+			want:    1,
+			wantOut: "'" + media + "' is not an Opendime, verify.txt not found",
+		}, {
+			name:    "no device",
+			args:    []string{"-device", "3"},
+			want:    1,
+			wantOut: "Unable to find Opendime: device index 3 out of range, found 1 Opendimes",
+		}, {
+			name: "path disagrees csv",
+			args: []string{"-p", bad, "-format", "csv"},
+			want: 1,
+			wantOut: "mount_point,address,serial,firmware,coin,unsealed,signature_valid,private_key_agrees,problems\n" + bad +
+				",1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR,DDRRNOCZJRIFCIBAEBJDOJQY74,2.4.0,BTC,false,true,,address.txt mentions 1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f not 1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR\n",
+		}, {
+			name:    "not an opendime json",
+			args:    []string{"-p", media, "-format", "json"},
+			want:    1,
+			wantOut: "{\n  \"error\": \"'" + media + "' is not an Opendime, verify.txt not found\"\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		// reset flags else panic
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = append([]string{cliName}, tt.args...)
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if got := InspectMain(out); got != tt.want {
+				t.Errorf("InspectMain() = %v, want %v", got, tt.want)
+			}
+
+			if gotOut := out.String(); gotOut != tt.wantOut {
+				t.Errorf("InspectMain() = %q, want %q", gotOut, tt.wantOut)
+			}
+		})
+	}
+}
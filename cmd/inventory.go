@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/internal/inventory"
+	"github.com/timchurchard/opendime-utils/internal/report"
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// inventoryNow clock for the date a record is added. A variable so tests can fix it
+var inventoryNow = time.Now
+
+// InventoryMain entrypoint for the inventory command. The first argument is the sub command
+// (add, list, show, remove or export)
+func InventoryMain(out io.Writer) int {
+	const (
+		defaultEmpty   = ""
+		usageStore     = "Path to the inventory file (default $OPENDIME_INVENTORY or inventory.json in the user config dir)"
+		usageLabel     = "Label for the Opendime (add)"
+		usageVerifyTxt = "Path to OPENDIME/advanced/verify.txt to add"
+		usageDevice    = "Add a mounted Opendime: auto or device index"
+		usageAddress   = "Bitcoin or Litecoin address to add. Optional with verify.txt"
+		usageSignature = "Bitcoin or Litecoin signature (required if verify.txt not used)"
+		usageMessage   = "Bitcoin message (required if verify.txt not used)"
+		usageFormat    = "Output format: text, json, csv or yaml (export writes json for text)"
+		usageNetwork   = "Network: mainnet, testnet, signet or regtest"
+	)
+	var (
+		storeFn     string
+		label       string
+		verifyTxtFn string
+		device      string
+		address     string
+		signature   string
+		message     string
+		formatName  string
+		networkName string
+		subCommand  string
+	)
+
+	if len(os.Args) > 1 {
+		subCommand = os.Args[1]
+		os.Args = append([]string{os.Args[0] + " " + subCommand}, os.Args[2:]...)
+	}
+
+	flag.StringVar(&storeFn, "store", defaultEmpty, usageStore)
+
+	flag.StringVar(&label, "label", defaultEmpty, usageLabel)
+	flag.StringVar(&label, "l", defaultEmpty, usageLabel+" (shorthand)")
+
+	flag.StringVar(&verifyTxtFn, "verifytxt", defaultEmpty, usageVerifyTxt)
+	flag.StringVar(&device, "device", defaultEmpty, usageDevice)
+
+	flag.StringVar(&address, "address", defaultEmpty, usageAddress)
+	flag.StringVar(&address, "a", defaultEmpty, usageAddress+" (shorthand)")
+
+	flag.StringVar(&signature, "signature", defaultEmpty, usageSignature)
+	flag.StringVar(&signature, "s", defaultEmpty, usageSignature+" (shorthand)")
+
+	flag.StringVar(&message, "message", defaultEmpty, usageMessage)
+	flag.StringVar(&message, "m", defaultEmpty, usageMessage+" (shorthand)")
+
+	flag.StringVar(&formatName, "format", string(report.Text), usageFormat)
+
+	flag.StringVar(&networkName, "network", string(pkg.MainNet), usageNetwork)
+	flag.StringVar(&networkName, "n", string(pkg.MainNet), usageNetwork+" (shorthand)")
+
+	flag.Usage = func() {
+		fmt.Fprintf(out, "Usage of %s: inventory add|list|show KEY|remove KEY|export\n", os.Args[0])
+
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	format, err := report.ParseFormat(formatName)
+	if err != nil {
+		fmt.Fprintf(out, "Invalid format: %v", err)
+		return 1
+	}
+
+	network, err := pkg.ParseNetwork(networkName)
+	if err != nil {
+		return fail(out, format, "Invalid network: %v", err)
+	}
+
+	if storeFn == "" {
+		storeFn, err = inventory.DefaultPath()
+		if err != nil {
+			return fail(out, format, "Unable to find inventory: %v", err)
+		}
+	}
+
+	inv := inventory.Open(storeFn)
+
+	switch subCommand {
+	case "add":
+		if device != "" {
+			verifyTxtFn, err = deviceVerifyTxt(device)
+			if err != nil {
+				return fail(out, format, "Unable to find Opendime: %v", err)
+			}
+		}
+
+		if verifyTxtFn != "" {
+			address, signature, message, err = pkg.ParseVerifyTxt(verifyTxtFn)
+			if errors.Is(err, os.ErrNotExist) {
+				return fail(out, format, "File '%s' not found", verifyTxtFn)
+			}
+			if err != nil {
+				return fail(out, format, "Unable to parse verify.txt: %v", err)
+			}
+		} else if address == "" {
+			return usage(out, format)
+		}
+
+		record, err := inventory.NewRecord(network, address, signature, message, label, inventoryNow())
+		if err != nil {
+			return fail(out, format, "Unable to add: %v", err)
+		}
+
+		if err := inv.Add(record); err != nil {
+			return fail(out, format, "Unable to add: %v", err)
+		}
+
+		if format != report.Text {
+			return writeReport(out, format, report.Record{Record: record})
+		}
+
+		fmt.Fprintf(out, "Added %s to inventory\n", record.Key())
+	case "list", "export":
+		records, err := inv.List()
+		if err != nil {
+			return fail(out, format, "Unable to read inventory: %v", err)
+		}
+
+		// export is for other tools so it is never text
+		if subCommand == "export" && format == report.Text {
+			format = report.JSON
+		}
+
+		if format != report.Text {
+			return writeReport(out, format, report.Inventory(records))
+		}
+
+		if len(records) == 0 {
+			fmt.Fprintln(out, "Inventory is empty")
+		}
+
+		for _, record := range records {
+			fmt.Fprintf(out, "%s%s\t%s\t%s\n", padLabel(record.Key()), record.Added.Format(time.DateOnly), record.Address, record.Label)
+		}
+	case "show":
+		record, err := inv.Get(flag.Arg(0))
+		if err != nil {
+			return fail(out, format, "Unable to show: %v", err)
+		}
+
+		if format != report.Text {
+			return writeReport(out, format, report.Record{Record: record})
+		}
+
+		printRecord(out, record)
+	case "remove":
+		record, err := inv.Remove(flag.Arg(0))
+		if err != nil {
+			return fail(out, format, "Unable to remove: %v", err)
+		}
+
+		if format != report.Text {
+			return writeReport(out, format, report.Record{Record: record})
+		}
+
+		fmt.Fprintf(out, "Removed %s from inventory\n", record.Key())
+	default:
+		return usage(out, format)
+	}
+
+	return 0
+}
+
+func printRecord(out io.Writer, record inventory.Record) {
+	fmt.Fprintf(out, "%s%s\n", padLabel("Serial:"), record.Serial)
+	fmt.Fprintf(out, "%s%s\n", padLabel("Label:"), record.Label)
+	fmt.Fprintf(out, "%s%s\n", padLabel("Network:"), record.Network)
+	fmt.Fprintf(out, "%s%s\n", padLabel("Firmware:"), record.Firmware)
+	fmt.Fprintf(out, "%s%s\n", padLabel("Added:"), record.Added.Format(time.DateTime))
+	fmt.Fprintf(out, "%s%s\n", padLabel("Public key hex:"), record.PublicKeyHex)
+	fmt.Fprintf(out, "Addresses for Opendime:\t%s\n", record.Address)
+
+	for _, addressType := range pkg.AddressTypes() {
+		if derived, ok := record.Addresses[addressType.ID]; ok {
+			fmt.Fprintf(out, "%s %s\n", padLabel("- "+addressType.Label()), derived)
+		}
+	}
+}
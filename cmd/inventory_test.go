@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_InventoryMain(t *testing.T) {
+	const (
+		cliName       = "inventory"
+		tipsShowStart = "Serial:\t\t\t\t\tDDRRNOCZJRIFCIBAEBJDOJQY74\nLabel:\t\t\t\t\ttips\nNetwork:\t\t\t\tmainnet\nFirmware:\t\t\t\t2.4.0\nAdded:\t\t\t\t\t2024-05-01 12:00:00\nPublic key hex:\t\t\t\t04f27deec87586e475f828cb3cd34d2a02a674c204875e91b90ce4ce1e8773289587979932eef0c5f76c5d5fc692db94749e4efba67b692f564190c4b36ca8763a\nAddresses for Opendime:\t1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR\n- Bitcoin P2PKH\t\t\t\t 1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR\n- Bitcoin P2PKH (Compressed)\t\t 129azYLPaG55Kb7z1TgvBbj6nRjYFcNMqE\n"
+	)
+
+	if _, err := os.Stat("../verify.txt_tips"); err != nil {
+		t.Skip("verify.txt_tips not found")
+	}
+
+	oldArgs, oldNow := os.Args, inventoryNow
+	defer func() { os.Args, inventoryNow = oldArgs, oldNow }()
+
+	inventoryNow = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	storeFn := filepath.Join(t.TempDir(), "inventory.json")
+
+	// Each step runs against the same inventory, in order
+	steps := []struct {
+		name          string
+		args          []string
+		want          int
+		wantOutPrefix string
+	}{
+		{"list empty", []string{"list"}, 0, "Inventory is empty\n"},
+		{"add verify.txt", []string{"add", "-verifytxt", "../verify.txt_tips", "-l", "tips"}, 0, "Added DDRRNOCZJRIFCIBAEBJDOJQY74 to inventory\n"},
+		{"add litecoin", []string{"add", "-verifytxt", "../litecoin_verify.txt_tips"}, 0, "Added PZZUNUKLGRIFCICKJIYDEEIC74 to inventory\n"},
+		{"add duplicate", []string{"add", "-verifytxt", "../verify.txt_tips"}, 1, "Unable to add: DDRRNOCZJRIFCIBAEBJDOJQY74 already in inventory"},
+		{"add bad signature", []string{"add", "-a", "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR", "-s", "G1pnvdb0RfKfv3Jhg4x0XBQqv1KQx3WFRaxTiUVN84fpIzxOBgapJb/Dpy6auJ28xcHaBxl3XHBbJejfokjgtmg=", "-m", "Hello"}, 1, "Unable to add: unable to verify signature: "},
+		{"list", []string{"list"}, 0, "DDRRNOCZJRIFCIBAEBJDOJQY74\t\t2024-05-01\t1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR\ttips\nPZZUNUKLGRIFCICKJIYDEEIC74\t\t2024-05-01\tLhNxvyyxBGv1Z9CKUaYPE5azvFCMnDMbRN\t\n"},
+		{"show by label", []string{"show", "tips"}, 0, tipsShowStart},
+		{"export csv", []string{"export", "-format", "csv"}, 0, "serial,label,network,address,public_key,firmware,added,BitcoinP2PKH,"},
+		{"export json", []string{"export"}, 0, "[\n  {\n    \"serial\": \"DDRRNOCZJRIFCIBAEBJDOJQY74\",\n    \"label\": \"tips\",\n"},
+		{"show yaml", []string{"show", "-format", "yaml", "tips"}, 0, "serial: DDRRNOCZJRIFCIBAEBJDOJQY74\nlabel: tips\nnetwork: mainnet\n"},
+		{"export yaml", []string{"export", "-format", "yaml"}, 0, "- serial: DDRRNOCZJRIFCIBAEBJDOJQY74\n  label: tips\n"},
+		{"remove by address", []string{"remove", "LhNxvyyxBGv1Z9CKUaYPE5azvFCMnDMbRN"}, 0, "Removed PZZUNUKLGRIFCICKJIYDEEIC74 from inventory\n"},
+		{"show removed", []string{"show", "PZZUNUKLGRIFCICKJIYDEEIC74"}, 1, "Unable to show: PZZUNUKLGRIFCICKJIYDEEIC74 not found in inventory"},
+		{"show removed json", []string{"show", "-format", "json", "PZZUNUKLGRIFCICKJIYDEEIC74"}, 1, "{\n  \"error\": \"Unable to show: PZZUNUKLGRIFCICKJIYDEEIC74 not found in inventory\"\n}\n"},
+		{"unknown sub command", []string{"frobnicate"}, 1, "Usage of inventory frobnicate: inventory add|list|show KEY|remove KEY|export\n"},
+	}
+	for _, step := range steps {
+		// reset flags else panic
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = append([]string{cliName}, step.args[0])
+		os.Args = append(os.Args, append([]string{"-store", storeFn}, step.args[1:]...)...)
+
+		t.Run(step.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if got := InventoryMain(out); got != step.want {
+				t.Errorf("InventoryMain() = %v, want %v (%s)", got, step.want, out.String())
+			}
+
+			if gotOut := out.String(); !strings.HasPrefix(gotOut, step.wantOutPrefix) {
+				t.Errorf("InventoryMain() = %q, want prefix %q", gotOut, step.wantOutPrefix)
+			}
+		})
+	}
+}
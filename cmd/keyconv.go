@@ -1,19 +1,17 @@
 package cmd
 
 import (
+	"bufio"
 	"encoding/hex"
 	"flag"
 	"fmt"
 	"io"
+	"strings"
 
 	ecies "github.com/ecies/go/v2"
+	"github.com/timchurchard/opendime-utils/internal/report"
 	"github.com/timchurchard/opendime-utils/pkg"
-)
-
-const (
-	prefixBitcoinHex  = "80"
-	prefixLitecoinHex = "b0"
-	prefixDogecoinHex = "9e"
+	"github.com/timchurchard/opendime-utils/pkg/qr"
 )
 
 // KeyconvMain entrypoint for the keyconv command
@@ -21,58 +19,162 @@ func KeyconvMain(out io.Writer, key string) int {
 	balance := flag.Bool("b", false, "Show balances")
 	makeAddrs := flag.Bool("a", false, "Make addresses")
 	verbose := flag.Bool("v", false, "Verbose mode")
+	const usageNetwork = "Network: mainnet, testnet, signet or regtest"
+
+	var networkName string
+	flag.StringVar(&networkName, "network", string(pkg.MainNet), usageNetwork)
+	flag.StringVar(&networkName, "n", string(pkg.MainNet), usageNetwork+" (shorthand)")
+	showQR := flag.Bool("qr", false, "Draw every private key as a QR code (asks for confirmation)")
+	qrLevelName := flag.String("qr-level", qr.M.String(), "QR error correction level: L, M, Q or H")
+	qrQuiet := flag.Int("qr-quiet", defaultQRQuiet, "QR quiet zone in modules")
+	qrInvert := flag.Bool("qr-invert", false, "Draw QR codes for dark text on a light background")
+	formatName := flag.String("format", string(report.Text), "Output format: text, json, csv or yaml")
 	flag.Parse()
 
-	// Check if key is hex and convert to wif
-	_, err := hex.DecodeString(key)
-	if err == nil {
-		key = pkg.ToWif(prefixBitcoinHex, key, false)
-	}
-
-	mode, secretExponentHex, isCompressed, err := pkg.ValidateWif(key)
+	format, err := report.ParseFormat(*formatName)
 	if err != nil {
 		fmt.Fprintf(out, "Error: %v", err)
 		return 1
 	}
 
-	fmt.Fprintf(out, "Original WIF: %s %s compressed=%v\n", mode, key, isCompressed)
+	network, err := pkg.ParseNetwork(networkName)
+	if err != nil {
+		return fail(out, format, "Error: %v", err)
+	}
+
+	qrLevel, err := parseQROptions(*qrLevelName, *qrQuiet)
+	if err != nil {
+		return fail(out, format, "Error: %v", err)
+	}
+
+	if *showQR && format != report.Text {
+		return fail(out, format, "Error: QR codes can only be drawn with -format text")
+	}
+
+	key = parseKey(network, key)
+
+	modes, secretExponentHex, isCompressed, err := pkg.ValidateWif(network, key)
+	if err != nil {
+		return fail(out, format, "Error: %v", err)
+	}
+
+	// The WIF prefix alone cannot tell apart coins that share it. Coins that sign alike (eg Bitcoin and Bitcoin Cash)
+	// are shown as the first registered, otherwise every coin eg Bitcoin/Litecoin on testnet
+	mode, err := pkg.WifCoin(modes)
+	if err != nil {
+		mode = strings.Join(modes, "/")
+	}
+
+	if *showQR && !confirm(out, "Anyone who sees (or photographs) a private key QR code can take its coins. Type yes to draw them: ") {
+		fmt.Fprintln(out, "Not drawing QR codes")
+		*showQR = false
+	}
+
+	text := format == report.Text
+	result := report.Keyconv{WIF: key, Coin: mode, Compressed: isCompressed, Keys: []report.Key{}}
+
+	if text {
+		fmt.Fprintf(out, "Original WIF: %s %s compressed=%v\n", mode, key, isCompressed)
+	}
 	if *verbose {
-		fmt.Fprintf(out, " - Secret exponent: %s\n", secretExponentHex)
+		result.SecretExponent = secretExponentHex
+
+		if text {
+			fmt.Fprintf(out, " - Secret exponent: %s\n", secretExponentHex)
+		}
 	}
 
-	fmt.Fprintln(out, "")
-	fmt.Fprintf(out, "Bitcoin P2PKH:\t\t\t%s\n", pkg.ToWif(prefixBitcoinHex, secretExponentHex, false))
-	fmt.Fprintf(out, "Bitcoin P2PKH (Compressed):\t%s\n", pkg.ToWif(prefixBitcoinHex, secretExponentHex, true))
-	fmt.Fprintf(out, "Bitcoin P2WPKH:\t\t\tp2wpkh:%s\n\n", pkg.ToWif(prefixBitcoinHex, secretExponentHex, true))
+	var lastCoin *pkg.Coin
+	for _, addressType := range pkg.AddressTypes() {
+		// Coins do not exist on every network (eg Litecoin on signet)
+		params, ok := addressType.Coin.Params(network)
+		if !ok || addressType.Key == nil {
+			continue
+		}
+
+		wif := addressType.Key(secretExponentHex, params)
+
+		if !text {
+			result.Keys = append(result.Keys, report.Key{
+				ID:    addressType.ID,
+				Coin:  addressType.Coin.Name,
+				Type:  addressType.Name,
+				Label: addressType.Label(),
+				Key:   wif,
+			})
+
+			continue
+		}
+
+		if addressType.Coin != lastCoin {
+			fmt.Fprintln(out, "")
+			lastCoin = addressType.Coin
+		}
 
-	fmt.Fprintf(out, "Litecoin P2PKH:\t\t\t%s\n", pkg.ToWif(prefixLitecoinHex, secretExponentHex, false))
-	fmt.Fprintf(out, "Litecoin P2PKH (Compressed):\t%s\n", pkg.ToWif(prefixLitecoinHex, secretExponentHex, true))
-	fmt.Fprintf(out, "Litecoin P2WPKH:\t\tp2wpkh:%s\n\n", pkg.ToWif(prefixLitecoinHex, secretExponentHex, true))
+		if *showQR {
+			if err := printQR(out, addressType.Label(), wif, qrLevel, *qrQuiet, *qrInvert); err != nil {
+				return fail(out, format, "Unable to make QR code: %v", err)
+			}
 
-	fmt.Fprintf(out, "Dogecoin P2PKH:\t\t\t%s\n\n", pkg.ToWif(prefixDogecoinHex, secretExponentHex, false))
+			continue
+		}
 
-	fmt.Fprintf(out, "Ethereum:\t\t\t0x%s\n", secretExponentHex)
+		fmt.Fprintf(out, "%s%s\n", padLabel(addressType.Label()+":"), wif)
+	}
 
 	if *makeAddrs {
 		privKey, err := ecies.NewPrivateKeyFromHex(secretExponentHex)
 		if err != nil {
-			fmt.Fprintf(out, "Failed to make private key: %v", err)
-			return 1
+			return fail(out, format, "Failed to make private key: %v", err)
 		}
 
 		verifiedMessage := pkg.VerifiedMessage{
 			Address:      "TODO",
 			PublicKeyHex: privKey.PublicKey.Hex(false),
+			Network:      network,
 		}
 
 		addresses, err := pkg.GetAddresses(verifiedMessage)
 		if err != nil {
-			fmt.Fprintf(out, "Failed to make addresses: %v", err)
-			return 1
+			return fail(out, format, "Failed to make addresses: %v", err)
+		}
+
+		if text {
+			prettyPrintAddresses(out, network, addresses, *balance)
+			return 0
 		}
 
-		prettyPrintAddresses(out, addresses, *balance)
+		result.Addresses = report.NewAddresses(addresses)
+		if *balance {
+			checkBalances(network, result.Addresses)
+		}
+	}
+
+	if !text {
+		if err := report.Write(out, format, result); err != nil {
+			return fail(out, format, "Error: %v", err)
+		}
 	}
 
 	return 0
 }
+
+// parseKey converts a hex secret exponent into an (uncompressed) Bitcoin WIF for the network. Anything else is
+// assumed to be a WIF and returned as is
+func parseKey(network pkg.Network, key string) string {
+	if _, err := hex.DecodeString(key); err == nil {
+		return pkg.ToWif(pkg.WifPrefixHex(network, pkg.Bitcoin), key, false)
+	}
+
+	return key
+}
+
+// confirm prints the prompt and returns true if the answer read from stdin is yes
+func confirm(out io.Writer, prompt string) bool {
+	fmt.Fprint(out, prompt)
+
+	answer, _ := bufio.NewReader(stdin).ReadString('\n')
+	fmt.Fprintln(out)
+
+	return strings.EqualFold(strings.TrimSpace(answer), "yes")
+}
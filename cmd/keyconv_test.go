@@ -2,19 +2,25 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"flag"
 	"os"
+	"regexp"
+	"strings"
 	"testing"
+
+	"github.com/timchurchard/opendime-utils/internal/report"
 )
 
 func Test_KeyconvMain(t *testing.T) {
 	const (
 		cliName                         = "keyconv"
 		bitcoinInvalid                  = "Error: WIF malformed/wrong length"
-		bitcoinValidCompressedOutput    = "Original WIF: Bitcoin Kx1rJ3afrZvj7jztGupxrtrFoA9SK37CA3ZnwtWDTRt7MQdyvozL compressed=true\n\nBitcoin P2PKH:\t\t\t5HzjtSWNwjML4LiD54MqTFvJQ9R7eAYJwAC4AGJ3NPreEj7B6oN\nBitcoin P2PKH (Compressed):\tKx1rJ3afrZvj7jztGupxrtrFoA9SK37CA3ZnwtWDTRt7MQdyvozL\nBitcoin P2WPKH:\t\t\tp2wpkh:Kx1rJ3afrZvj7jztGupxrtrFoA9SK37CA3ZnwtWDTRt7MQdyvozL\n\nLitecoin P2PKH:\t\t\t6uJUMa3ur9pCXic4at9oEehUMcyaqxzLhqbDsTK55rBFvZ7W4XJ\nLitecoin P2PKH (Compressed):\tT3r7jnsrFwuKtadkpYmq5FPdk1nkP885yFU3oh8m2Q4GsJDSoPhY\nLitecoin P2WPKH:\t\tp2wpkh:T3r7jnsrFwuKtadkpYmq5FPdk1nkP885yFU3oh8m2Q4GsJDSoPhY\n\nDogecoin P2PKH:\t\t\t6JK5BPyLWVe86x9NGSyp4suXsZtf9HpaYLKHUkvwC44H3PzLLJn\n\nEthereum:\t\t\t0x17bc6f773fe98bb1a72adf6e3e89366b7e19ee76d88a023b248fceebfedf1e5d\n"
-		bitcoinValidUncompVerboseOutput = "Original WIF: Bitcoin 5JcB6S5ob2WBQdqUC2km8Me9KcDYb9nXGkM5ynL9RascRHfggQs compressed=false\n - Secret exponent: 6831d5d095a1391cebc94315a8f67579ea9c3df8fe8278df682fc536f2f7f907\n\nBitcoin P2PKH:\t\t\t5JcB6S5ob2WBQdqUC2km8Me9KcDYb9nXGkM5ynL9RascRHfggQs\nBitcoin P2PKH (Compressed):\tKziFYyiCdBdnwKaw1gk9mU6wj8SNRjmvcWKeyd5WBWWPKw3EBTqt\nBitcoin P2WPKH:\t\t\tp2wpkh:KziFYyiCdBdnwKaw1gk9mU6wj8SNRjmvcWKeyd5WBWWPKw3EBTqt\n\nLitecoin P2PKH:\t\t\t6uuuZZdLVSy3t1jKhrYiukRKH5n1nxEZ3RkFgyMB93CE75ctC2U\nLitecoin P2PKH (Compressed):\tT6YWzj1P2ZcPiADoZKh1ypeKfz5gVpnpRiDuqRi3kUgYqpbrYbAG\nLitecoin P2WPKH:\t\tp2wpkh:T6YWzj1P2ZcPiADoZKh1ypeKfz5gVpnpRiDuqRi3kUgYqpbrYbAG\n\nDogecoin P2PKH:\t\t\t6JvWPPYm9nnyTFGdPRNjjydNo2h66H4nsvUKJGy3FF5FDwoFpCE\n\nEthereum:\t\t\t0x6831d5d095a1391cebc94315a8f67579ea9c3df8fe8278df682fc536f2f7f907\n"
-		bitcoinHexOutput                = "Original WIF: Bitcoin 5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC compressed=false\n\nBitcoin P2PKH:\t\t\t5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC\nBitcoin P2PKH (Compressed):\tL4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ\nBitcoin P2WPKH:\t\t\tp2wpkh:L4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ\n\nLitecoin P2PKH:\t\t\t6vnxAJ79bpSu2KfcZhJt82AQYo7fuiHtbLZpR1FzqP6B4iaR7rR\nLitecoin P2PKH (Compressed):\tTARpU2W8xgRJcqPRyJuvdz8WwLYSjfFYiZMrGx9CGYVqR5Yuj4fE\nLitecoin P2WPKH:\t\tp2wpkh:TARpU2W8xgRJcqPRyJuvdz8WwLYSjfFYiZMrGx9CGYVqR5Yuj4fE\n\nDogecoin P2PKH:\t\t\t6KoYz82aGAGpbZCvFG8txFNU4k2kD388RqHt2JsrwayCBYPGU1z\n\nEthereum:\t\t\t0xdc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3\n"
-		bitcoinHexOutputAddrs           = "Original WIF: Bitcoin 5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC compressed=false\n\nBitcoin P2PKH:\t\t\t5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC\nBitcoin P2PKH (Compressed):\tL4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ\nBitcoin P2WPKH:\t\t\tp2wpkh:L4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ\n\nLitecoin P2PKH:\t\t\t6vnxAJ79bpSu2KfcZhJt82AQYo7fuiHtbLZpR1FzqP6B4iaR7rR\nLitecoin P2PKH (Compressed):\tTARpU2W8xgRJcqPRyJuvdz8WwLYSjfFYiZMrGx9CGYVqR5Yuj4fE\nLitecoin P2WPKH:\t\tp2wpkh:TARpU2W8xgRJcqPRyJuvdz8WwLYSjfFYiZMrGx9CGYVqR5Yuj4fE\n\nDogecoin P2PKH:\t\t\t6KoYz82aGAGpbZCvFG8txFNU4k2kD388RqHt2JsrwayCBYPGU1z\n\nEthereum:\t\t\t0xdc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3\nAddresses for Opendime:\tTODO\n- Bitcoin P2PKH\t\t\t 13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu \n- Bitcoin P2PKH (Compressed)\t 1A9GW5SxA4qW5ZhymW1uPaj6N4vnVJVWZW \n- Bitcoin P2WPKH\t\t bc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqzfhzrq \n- Ethereum\t\t\t 0xCb19D769c583599DbD7D6D78Eb3279a362672747 \n- Litecoin P2PKH\t\t LMrjSZiwF9FG73FTUP9pv4Tp7vtudfiMG7 \n- Litecoin P2PKH (Compressed)\t LUNDmHknEj5ZLNQ8we1CfbnraHJ4iPRtuG \n- Litecoin P2WPKH\t\t ltc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqx4dxms \n- Dogecoin P2PKH\t\t D7msicMkTtuVPEju2qA6BoZenrFvrMCm24 \n"
+		bitcoinValidCompressedOutput    = "Original WIF: Bitcoin Kx1rJ3afrZvj7jztGupxrtrFoA9SK37CA3ZnwtWDTRt7MQdyvozL compressed=true\n\nBitcoin P2PKH:\t\t\t\t5HzjtSWNwjML4LiD54MqTFvJQ9R7eAYJwAC4AGJ3NPreEj7B6oN\nBitcoin P2PKH (Compressed):\t\tKx1rJ3afrZvj7jztGupxrtrFoA9SK37CA3ZnwtWDTRt7MQdyvozL\nBitcoin P2SH-P2WPKH:\t\t\tp2wpkh-p2sh:Kx1rJ3afrZvj7jztGupxrtrFoA9SK37CA3ZnwtWDTRt7MQdyvozL\nBitcoin P2WPKH:\t\t\t\tp2wpkh:Kx1rJ3afrZvj7jztGupxrtrFoA9SK37CA3ZnwtWDTRt7MQdyvozL\nBitcoin P2TR:\t\t\t\ttr(Kx1rJ3afrZvj7jztGupxrtrFoA9SK37CA3ZnwtWDTRt7MQdyvozL)\n\nEthereum:\t\t\t\t0x17bc6f773fe98bb1a72adf6e3e89366b7e19ee76d88a023b248fceebfedf1e5d\n\nLitecoin P2PKH:\t\t\t\t6uJUMa3ur9pCXic4at9oEehUMcyaqxzLhqbDsTK55rBFvZ7W4XJ\nLitecoin P2PKH (Compressed):\t\tT3r7jnsrFwuKtadkpYmq5FPdk1nkP885yFU3oh8m2Q4GsJDSoPhY\nLitecoin P2SH-P2WPKH:\t\t\tp2wpkh-p2sh:T3r7jnsrFwuKtadkpYmq5FPdk1nkP885yFU3oh8m2Q4GsJDSoPhY\nLitecoin P2WPKH:\t\t\tp2wpkh:T3r7jnsrFwuKtadkpYmq5FPdk1nkP885yFU3oh8m2Q4GsJDSoPhY\nLitecoin P2TR:\t\t\t\ttr(T3r7jnsrFwuKtadkpYmq5FPdk1nkP885yFU3oh8m2Q4GsJDSoPhY)\n\nDogecoin P2PKH:\t\t\t\t6JK5BPyLWVe86x9NGSyp4suXsZtf9HpaYLKHUkvwC44H3PzLLJn\n\nBitcoin Cash P2PKH:\t\t\t5HzjtSWNwjML4LiD54MqTFvJQ9R7eAYJwAC4AGJ3NPreEj7B6oN\nBitcoin Cash P2PKH (Compressed):\tKx1rJ3afrZvj7jztGupxrtrFoA9SK37CA3ZnwtWDTRt7MQdyvozL\n\nDash P2PKH:\t\t\t\t7qjKJ4coi4kYJgsoy7XmwdV9zpDh3garjjaEheaAvCN7jxQz5fw\nDash P2PKH (Compressed):\t\tXC5mkJy3AFZBB51GJfpqN83GiBR1kqiSXcuhUQqQmoACkZm5ZBHy\n"
+		bitcoinValidUncompVerboseOutput = "Original WIF: Bitcoin 5JcB6S5ob2WBQdqUC2km8Me9KcDYb9nXGkM5ynL9RascRHfggQs compressed=false\n - Secret exponent: 6831d5d095a1391cebc94315a8f67579ea9c3df8fe8278df682fc536f2f7f907\n\nBitcoin P2PKH:\t\t\t\t5JcB6S5ob2WBQdqUC2km8Me9KcDYb9nXGkM5ynL9RascRHfggQs\nBitcoin P2PKH (Compressed):\t\tKziFYyiCdBdnwKaw1gk9mU6wj8SNRjmvcWKeyd5WBWWPKw3EBTqt\nBitcoin P2SH-P2WPKH:\t\t\tp2wpkh-p2sh:KziFYyiCdBdnwKaw1gk9mU6wj8SNRjmvcWKeyd5WBWWPKw3EBTqt\nBitcoin P2WPKH:\t\t\t\tp2wpkh:KziFYyiCdBdnwKaw1gk9mU6wj8SNRjmvcWKeyd5WBWWPKw3EBTqt\nBitcoin P2TR:\t\t\t\ttr(KziFYyiCdBdnwKaw1gk9mU6wj8SNRjmvcWKeyd5WBWWPKw3EBTqt)\n\nEthereum:\t\t\t\t0x6831d5d095a1391cebc94315a8f67579ea9c3df8fe8278df682fc536f2f7f907\n\nLitecoin P2PKH:\t\t\t\t6uuuZZdLVSy3t1jKhrYiukRKH5n1nxEZ3RkFgyMB93CE75ctC2U\nLitecoin P2PKH (Compressed):\t\tT6YWzj1P2ZcPiADoZKh1ypeKfz5gVpnpRiDuqRi3kUgYqpbrYbAG\nLitecoin P2SH-P2WPKH:\t\t\tp2wpkh-p2sh:T6YWzj1P2ZcPiADoZKh1ypeKfz5gVpnpRiDuqRi3kUgYqpbrYbAG\nLitecoin P2WPKH:\t\t\tp2wpkh:T6YWzj1P2ZcPiADoZKh1ypeKfz5gVpnpRiDuqRi3kUgYqpbrYbAG\nLitecoin P2TR:\t\t\t\ttr(T6YWzj1P2ZcPiADoZKh1ypeKfz5gVpnpRiDuqRi3kUgYqpbrYbAG)\n\nDogecoin P2PKH:\t\t\t\t6JvWPPYm9nnyTFGdPRNjjydNo2h66H4nsvUKJGy3FF5FDwoFpCE\n\nBitcoin Cash P2PKH:\t\t\t5JcB6S5ob2WBQdqUC2km8Me9KcDYb9nXGkM5ynL9RascRHfggQs\nBitcoin Cash P2PKH (Compressed):\tKziFYyiCdBdnwKaw1gk9mU6wj8SNRjmvcWKeyd5WBWWPKw3EBTqt\n\nDash P2PKH:\t\t\t\t7rLkW4CEMMuPez1565vhcjCzvH27zfq55KjGXAcGyPP5vaVmH7J\nDash P2PKH (Compressed):\t\tXEnB1F6ZvsGEzebK3Sk2GhHxe9hwsYPAz5fZW9QhVsnUj6BLYAQv\n"
+		bitcoinHexOutput                = "Original WIF: Bitcoin 5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC compressed=false\n\nBitcoin P2PKH:\t\t\t\t5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC\nBitcoin P2PKH (Compressed):\t\tL4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ\nBitcoin P2SH-P2WPKH:\t\t\tp2wpkh-p2sh:L4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ\nBitcoin P2WPKH:\t\t\t\tp2wpkh:L4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ\nBitcoin P2TR:\t\t\t\ttr(L4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ)\n\nEthereum:\t\t\t\t0xdc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3\n\nLitecoin P2PKH:\t\t\t\t6vnxAJ79bpSu2KfcZhJt82AQYo7fuiHtbLZpR1FzqP6B4iaR7rR\nLitecoin P2PKH (Compressed):\t\tTARpU2W8xgRJcqPRyJuvdz8WwLYSjfFYiZMrGx9CGYVqR5Yuj4fE\nLitecoin P2SH-P2WPKH:\t\t\tp2wpkh-p2sh:TARpU2W8xgRJcqPRyJuvdz8WwLYSjfFYiZMrGx9CGYVqR5Yuj4fE\nLitecoin P2WPKH:\t\t\tp2wpkh:TARpU2W8xgRJcqPRyJuvdz8WwLYSjfFYiZMrGx9CGYVqR5Yuj4fE\nLitecoin P2TR:\t\t\t\ttr(TARpU2W8xgRJcqPRyJuvdz8WwLYSjfFYiZMrGx9CGYVqR5Yuj4fE)\n\nDogecoin P2PKH:\t\t\t\t6KoYz82aGAGpbZCvFG8txFNU4k2kD388RqHt2JsrwayCBYPGU1z\n\nBitcoin Cash P2PKH:\t\t\t5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC\nBitcoin Cash P2PKH (Compressed):\tL4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ\n\nDash P2PKH:\t\t\t\t7sDo6ng3TjPEoHwMwvgrpzx6BzMn7RtQdEYqFCX6fjH2t4C8UYw\nDash P2PKH (Compressed):\t\tXJfUUYbKrz59uKkwTRxvvrn9uWAi7NquGvoVwfqr1wbmJM8RMj5y\n"
+		signetHexOutputAddrs            = "Original WIF: Bitcoin 93FrGuPAHd4AX1H3gDQqDDwCEyuus5P4Ac2bnSbUTfWc9q6qjVT compressed=false\n\nBitcoin P2PKH:\t\t\t\t93FrGuPAHd4AX1H3gDQqDDwCEyuus5P4Ac2bnSbUTfWc9q6qjVT\nBitcoin P2PKH (Compressed):\t\tcUxYVCCozN8y1SDpp5nBnx6CciCYL2LLyPc4XZyACgyg9w3hbwcG\nBitcoin P2SH-P2WPKH:\t\t\tp2wpkh-p2sh:cUxYVCCozN8y1SDpp5nBnx6CciCYL2LLyPc4XZyACgyg9w3hbwcG\nBitcoin P2WPKH:\t\t\t\tp2wpkh:cUxYVCCozN8y1SDpp5nBnx6CciCYL2LLyPc4XZyACgyg9w3hbwcG\nBitcoin P2TR:\t\t\t\ttr(cUxYVCCozN8y1SDpp5nBnx6CciCYL2LLyPc4XZyACgyg9w3hbwcG)\n\nEthereum:\t\t\t\t0xdc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3\nAddresses for Opendime:\tTODO\n- Bitcoin P2PKH\t\t\t\t mi9jUQW5yWSTdM2v1p8uTxcNmi8LT5i2sS \n- Bitcoin P2PKH (Compressed)\t\t mpfDo8Xvy6GkrgBbV4zHDVwRE4XVRX9Hit \n- Bitcoin P2SH-P2WPKH\t\t\t 2N7GPhu7nVgdEKHx4XcUPgNJe5BFoMvmHk5 \n- Bitcoin P2WPKH\t\t\t tb1qv3ykvnp4qzktqz65l925jgpe3tpkktvqg0v3cn \n- Bitcoin P2TR\t\t\t\t tb1pswmpmx5nwpenu2fjdd59f8ulrhw27zf7ag8rxjxugx85033zeukqtrvfxx \n- Ethereum\t\t\t\t 0xCb19D769c583599DbD7D6D78Eb3279a362672747 \n"
+		bitcoinHexOutputAddrs           = "Original WIF: Bitcoin 5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC compressed=false\n\nBitcoin P2PKH:\t\t\t\t5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC\nBitcoin P2PKH (Compressed):\t\tL4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ\nBitcoin P2SH-P2WPKH:\t\t\tp2wpkh-p2sh:L4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ\nBitcoin P2WPKH:\t\t\t\tp2wpkh:L4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ\nBitcoin P2TR:\t\t\t\ttr(L4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ)\n\nEthereum:\t\t\t\t0xdc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3\n\nLitecoin P2PKH:\t\t\t\t6vnxAJ79bpSu2KfcZhJt82AQYo7fuiHtbLZpR1FzqP6B4iaR7rR\nLitecoin P2PKH (Compressed):\t\tTARpU2W8xgRJcqPRyJuvdz8WwLYSjfFYiZMrGx9CGYVqR5Yuj4fE\nLitecoin P2SH-P2WPKH:\t\t\tp2wpkh-p2sh:TARpU2W8xgRJcqPRyJuvdz8WwLYSjfFYiZMrGx9CGYVqR5Yuj4fE\nLitecoin P2WPKH:\t\t\tp2wpkh:TARpU2W8xgRJcqPRyJuvdz8WwLYSjfFYiZMrGx9CGYVqR5Yuj4fE\nLitecoin P2TR:\t\t\t\ttr(TARpU2W8xgRJcqPRyJuvdz8WwLYSjfFYiZMrGx9CGYVqR5Yuj4fE)\n\nDogecoin P2PKH:\t\t\t\t6KoYz82aGAGpbZCvFG8txFNU4k2kD388RqHt2JsrwayCBYPGU1z\n\nBitcoin Cash P2PKH:\t\t\t5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC\nBitcoin Cash P2PKH (Compressed):\tL4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ\n\nDash P2PKH:\t\t\t\t7sDo6ng3TjPEoHwMwvgrpzx6BzMn7RtQdEYqFCX6fjH2t4C8UYw\nDash P2PKH (Compressed):\t\tXJfUUYbKrz59uKkwTRxvvrn9uWAi7NquGvoVwfqr1wbmJM8RMj5y\nAddresses for Opendime:\tTODO\n- Bitcoin P2PKH\t\t\t\t 13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu \n- Bitcoin P2PKH (Compressed)\t\t 1A9GW5SxA4qW5ZhymW1uPaj6N4vnVJVWZW \n- Bitcoin P2SH-P2WPKH\t\t\t 3FiBeABktE7t7WKWrUrX4RKNrq3dVbokdR \n- Bitcoin P2WPKH\t\t\t bc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqzfhzrq \n- Bitcoin P2TR\t\t\t\t bc1pswmpmx5nwpenu2fjdd59f8ulrhw27zf7ag8rxjxugx85033zeukqut6xuf \n- Ethereum\t\t\t\t 0xCb19D769c583599DbD7D6D78Eb3279a362672747 \n- Litecoin P2PKH\t\t\t LMrjSZiwF9FG73FTUP9pv4Tp7vtudfiMG7 \n- Litecoin P2PKH (Compressed)\t\t LUNDmHknEj5ZLNQ8we1CfbnraHJ4iPRtuG \n- Litecoin P2SH-P2WPKH\t\t\t MMvKx3biqLyJv1bQxMqrt4ZnBXe5ZETyYb \n- Litecoin P2WPKH\t\t\t ltc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqx4dxms \n- Litecoin P2TR\t\t\t\t ltc1pswmpmx5nwpenu2fjdd59f8ulrhw27zf7ag8rxjxugx85033zeukql05kxv \n- Dogecoin P2PKH\t\t\t D7msicMkTtuVPEju2qA6BoZenrFvrMCm24 \n- Bitcoin Cash P2PKH\t\t\t bitcoincash:qqwwfur42pxtqd22xx50fea3x3lsu40jds3xjspyqj \n- Bitcoin Cash P2PKH (Compressed)\t bitcoincash:qpjyjejvx5q2evqt2nu42jfq8x9vx6edsqjtne9lvq \n- Dash P2PKH\t\t\t\t XdKd1c518CDo1B9tA8UkVa5qk47KZSAgbE \n- Dash P2PKH (Compressed)\t\t Xjq7LL6r7n46EWJZdPL8F7QtCQWUczLj2P \n"
 	)
 
 	// We manipulate the Args to set them up for the testcases, after this test we restore the initial args
@@ -36,6 +42,9 @@ func Test_KeyconvMain(t *testing.T) {
 		{"bitcoin valid uncomp verbose", args{flags: []string{"-v"}, key: "5JcB6S5ob2WBQdqUC2km8Me9KcDYb9nXGkM5ynL9RascRHfggQs"}, 0, bitcoinValidUncompVerboseOutput},
 		{"bitcoin hex", args{flags: []string{}, key: "dc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3"}, 0, bitcoinHexOutput},
 		{"bitcoin hex addrs", args{flags: []string{"-a"}, key: "dc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3"}, 0, bitcoinHexOutputAddrs},
+		{"signet hex addrs", args{flags: []string{"-a", "-network", "signet"}, key: "dc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3"}, 0, signetHexOutputAddrs},
+		{"signet hex addrs shorthand", args{flags: []string{"-a", "-n", "signet"}, key: "dc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3"}, 0, signetHexOutputAddrs},
+		{"testnet key on mainnet", args{flags: []string{}, key: "cMqgyhQGdBTb1gPd9xZ8ELuxZGdkRLZ6oNGjt9q7RcN9B672pLMm"}, 1, "Error: WIF malformed/wrong prefix byte"},
 	}
 	for _, tt := range tests {
 		// reset flags else panic
@@ -54,3 +63,118 @@ func Test_KeyconvMain(t *testing.T) {
 		})
 	}
 }
+
+func Test_KeyconvMainQR(t *testing.T) {
+	const (
+		cliName = "keyconv"
+		key     = "5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC"
+		prompt  = "Anyone who sees (or photographs) a private key QR code can take its coins. Type yes to draw them: \n"
+	)
+
+	oldArgs := os.Args
+	oldStdin := stdin
+	defer func() {
+		os.Args = oldArgs
+		stdin = oldStdin
+	}()
+
+	tests := []struct {
+		name    string
+		flags   []string
+		answer  string
+		want    int
+		wantOut []string
+		wantQRs int
+	}{
+		{"confirmed", []string{"-qr"}, "yes\n", 0, []string{prompt + "Original WIF: Bitcoin " + key,
+			"\nBitcoin P2PKH:\t\t\t\t5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC\n█████", "\nEthereum:\t\t\t\t0xdc19"}, 16},
+		{"declined", []string{"-qr"}, "no\n", 0, []string{prompt + "Not drawing QR codes\nOriginal WIF: Bitcoin " + key,
+			"\nBitcoin P2PKH:\t\t\t\t5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC\nBitcoin P2PKH (Compressed):"}, 0},
+		{"no answer", []string{"-qr"}, "", 0, []string{prompt + "Not drawing QR codes\n"}, 0},
+		{"bad level", []string{"-qr", "-qr-level", "X"}, "yes\n", 1, []string{"Error: QR level must be L, M, Q or H not 'X'"}, 0},
+	}
+	for _, tt := range tests {
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = append([]string{cliName}, tt.flags...)
+		stdin = strings.NewReader(tt.answer)
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+
+			if got := KeyconvMain(out, key); got != tt.want {
+				t.Errorf("KeyconvMain() = %v, want %v", got, tt.want)
+			}
+
+			gotOut := out.String()
+			for _, want := range tt.wantOut {
+				if !strings.Contains(gotOut, want) {
+					t.Errorf("KeyconvMain() = %q, want %q", gotOut, want)
+				}
+			}
+
+			// Every QR code has an odd height so ends with a line of only the top half of the quiet zone
+			if gotQRs := len(regexp.MustCompile(`(?m)^▀+$`).FindAllString(gotOut, -1)); gotQRs != tt.wantQRs {
+				t.Errorf("KeyconvMain() QR codes = %d, want %d", gotQRs, tt.wantQRs)
+			}
+		})
+	}
+}
+
+func Test_KeyconvMainFormat(t *testing.T) {
+	const (
+		cliName = "keyconv"
+		key     = "dc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3"
+	)
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	tests := []struct {
+		name    string
+		flags   []string
+		key     string
+		want    int
+		wantOut string
+	}{
+		{"csv", []string{"-format", "csv", "-network", "signet"}, key, 0, "kind,key,id,coin,type,label,address,amount,value,currency,spends\n" +
+			"key,93FrGuPAHd4AX1H3gDQqDDwCEyuus5P4Ac2bnSbUTfWc9q6qjVT,BitcoinP2PKH,Bitcoin,P2PKH,Bitcoin P2PKH,,,,,\n" +
+			"key,cUxYVCCozN8y1SDpp5nBnx6CciCYL2LLyPc4XZyACgyg9w3hbwcG,BitcoinP2PKHCompressed,Bitcoin,P2PKH (Compressed),Bitcoin P2PKH (Compressed),,,,,\n" +
+			"key,p2wpkh-p2sh:cUxYVCCozN8y1SDpp5nBnx6CciCYL2LLyPc4XZyACgyg9w3hbwcG,BitcoinP2SHP2WPKH,Bitcoin,P2SH-P2WPKH,Bitcoin P2SH-P2WPKH,,,,,\n" +
+			"key,p2wpkh:cUxYVCCozN8y1SDpp5nBnx6CciCYL2LLyPc4XZyACgyg9w3hbwcG,BitcoinP2WPKH,Bitcoin,P2WPKH,Bitcoin P2WPKH,,,,,\n" +
+			"key,tr(cUxYVCCozN8y1SDpp5nBnx6CciCYL2LLyPc4XZyACgyg9w3hbwcG),BitcoinP2TR,Bitcoin,P2TR,Bitcoin P2TR,,,,,\n" +
+			"key,0xdc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3,Ethereum,Ethereum,,Ethereum,,,,,\n"},
+		{"error json", []string{"-format", "json"}, "Kx1rJ3afrZvj7", 1, "{\n  \"error\": \"Error: WIF malformed/wrong length\"\n}\n"},
+		{"qr json", []string{"-format", "json", "-qr"}, key, 1, "{\n  \"error\": \"Error: QR codes can only be drawn with -format text\"\n}\n"},
+	}
+	for _, tt := range tests {
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = append([]string{cliName}, tt.flags...)
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+
+			if got := KeyconvMain(out, tt.key); got != tt.want {
+				t.Errorf("KeyconvMain() = %v, want %v", got, tt.want)
+			}
+			if gotOut := out.String(); gotOut != tt.wantOut {
+				t.Errorf("KeyconvMain() = %q, want %q", gotOut, tt.wantOut)
+			}
+		})
+	}
+
+	// json with verbose and addresses
+	flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+	os.Args = []string{cliName, "-format", "json", "-v", "-a"}
+
+	out := &bytes.Buffer{}
+	if got := KeyconvMain(out, key); got != 0 {
+		t.Errorf("KeyconvMain() = %v, want 0", got)
+	}
+
+	var result report.Keyconv
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil || result.WIF != "5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC" ||
+		result.SecretExponent != key || len(result.Keys) != 16 || len(result.Addresses) != 16 ||
+		result.Addresses[0].Address != "13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu" {
+		t.Errorf("KeyconvMain() = %s, %v", out.String(), err)
+	}
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/timchurchard/opendime-utils/internal/label"
+	"github.com/timchurchard/opendime-utils/internal/report"
+	"github.com/timchurchard/opendime-utils/pkg"
+	"github.com/timchurchard/opendime-utils/pkg/qr"
+)
+
+// LabelMain entrypoint for the label command
+func LabelMain(out io.Writer) int {
+	const (
+		defaultEmpty   = ""
+		usageVerifyTxt = "Path to OPENDIME/advanced/verify.txt alternative to passing address, signature and message"
+		usageDevice    = "Use verify.txt from a mounted Opendime: auto or device index"
+		usageAddress   = "Bitcoin or Litecoin address. Optional with verify.txt"
+		usageSignature = "Bitcoin or Litecoin signature (required if verify.txt not used)"
+		usageMessage   = "Bitcoin message (required if verify.txt not used)"
+		usageNetwork   = "Network: mainnet, testnet, signet or regtest"
+		usageSVG       = "Write the label as SVG to this file"
+		usagePDF       = "Write the label as a PDF page to this file"
+		usageTitle     = "Title printed at the top of the label (default Opendime and the address)"
+		usageURI       = "Encode BIP21/EIP-681 payment URIs rather than bare addresses"
+		usageAmount    = "Amount for the payment URIs in whole coins eg 0.001"
+		usageLabel     = "Label for the BIP21 payment URIs"
+		usageLevel     = "QR error correction level: L, M, Q or H"
+		usageFormat    = "Output format: text, json, csv or yaml"
+	)
+	var (
+		verifyTxtFn string
+		device      string
+		address     string
+		signature   string
+		message     string
+		networkName string
+		svgFn       string
+		pdfFn       string
+		title       string
+		uri         bool
+		amount      string
+		uriLabel    string
+		levelName   string
+		formatName  string
+	)
+
+	flag.StringVar(&verifyTxtFn, "verifytxt", defaultEmpty, usageVerifyTxt)
+	flag.StringVar(&device, "device", defaultEmpty, usageDevice)
+
+	flag.StringVar(&address, "address", defaultEmpty, usageAddress)
+	flag.StringVar(&address, "a", defaultEmpty, usageAddress+" (shorthand)")
+
+	flag.StringVar(&signature, "signature", defaultEmpty, usageSignature)
+	flag.StringVar(&signature, "s", defaultEmpty, usageSignature+" (shorthand)")
+
+	flag.StringVar(&message, "message", defaultEmpty, usageMessage)
+	flag.StringVar(&message, "m", defaultEmpty, usageMessage+" (shorthand)")
+
+	flag.StringVar(&networkName, "network", string(pkg.MainNet), usageNetwork)
+	flag.StringVar(&networkName, "n", string(pkg.MainNet), usageNetwork+" (shorthand)")
+
+	flag.StringVar(&svgFn, "svg", defaultEmpty, usageSVG)
+	flag.StringVar(&pdfFn, "pdf", defaultEmpty, usagePDF)
+	flag.StringVar(&title, "title", defaultEmpty, usageTitle)
+
+	flag.BoolVar(&uri, "uri", false, usageURI)
+	flag.StringVar(&amount, "amount", defaultEmpty, usageAmount)
+	flag.StringVar(&uriLabel, "label", defaultEmpty, usageLabel)
+	flag.StringVar(&uriLabel, "l", defaultEmpty, usageLabel+" (shorthand)")
+
+	flag.StringVar(&levelName, "level", qr.M.String(), usageLevel)
+
+	flag.StringVar(&formatName, "format", string(report.Text), usageFormat)
+
+	flag.Usage = func() {
+		fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
+
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	format, err := report.ParseFormat(formatName)
+	if err != nil {
+		fmt.Fprintf(out, "Invalid format: %v", err)
+		return 1
+	}
+
+	network, err := pkg.ParseNetwork(networkName)
+	if err != nil {
+		return fail(out, format, "Invalid network: %v", err)
+	}
+
+	level, err := qr.ParseLevel(levelName)
+	if err != nil {
+		return fail(out, format, "Invalid level: %v", err)
+	}
+
+	if device != "" {
+		verifyTxtFn, err = deviceVerifyTxt(device)
+		if err != nil {
+			return fail(out, format, "Unable to find Opendime: %v", err)
+		}
+	}
+
+	if verifyTxtFn != "" {
+		address, signature, message, err = pkg.ParseVerifyTxt(verifyTxtFn)
+		if errors.Is(err, os.ErrNotExist) {
+			return fail(out, format, "File '%s' not found", verifyTxtFn)
+		}
+		if err != nil {
+			return fail(out, format, "Unable to parse verify.txt: %v", err)
+		}
+	} else if address == "" {
+		return usage(out, format)
+	}
+
+	if svgFn == "" && pdfFn == "" {
+		// Nowhere to write the label
+		return usage(out, format)
+	}
+
+	verifiedMessage, err := pkg.VerifyMessage(network, address, signature, message)
+	if err != nil {
+		return fail(out, format, "Unable to verify signature: %v", err)
+	}
+
+	if verifiedMessage.PublicKeyHex == "" {
+		return fail(out, format, "Signature valid for %s but it does not reveal the public key so no addresses can be derived", address)
+	}
+
+	addresses, err := pkg.GetAddresses(verifiedMessage)
+	if err != nil {
+		return fail(out, format, "Failed to make addresses: %v", err)
+	}
+
+	if title == "" {
+		title = "Opendime " + address
+	}
+
+	page, err := label.New(title, addresses, uri, amount, uriLabel, level)
+	if err != nil {
+		return fail(out, format, "Unable to make label: %v", err)
+	}
+
+	result := report.Label{Title: title, Files: []report.LabelFile{}}
+
+	for _, output := range []struct {
+		fn    string
+		write func(io.Writer) error
+	}{
+		{svgFn, page.WriteSVG},
+		{pdfFn, page.WritePDF},
+	} {
+		if output.fn == "" {
+			continue
+		}
+
+		var b bytes.Buffer
+		if err := output.write(&b); err != nil {
+			return fail(out, format, "Unable to make label: %v", err)
+		}
+
+		if err := os.WriteFile(output.fn, b.Bytes(), 0o644); err != nil {
+			return fail(out, format, "Unable to write '%s': %v", output.fn, err)
+		}
+
+		if format == report.Text {
+			fmt.Fprintf(out, "Wrote label with %d QR codes to %s\n", len(page.Items), output.fn)
+		}
+
+		result.Files = append(result.Files, report.LabelFile{File: output.fn, QRCodes: len(page.Items)})
+	}
+
+	if format != report.Text {
+		return writeReport(out, format, result)
+	}
+
+	return 0
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_LabelMain(t *testing.T) {
+	const cliName = "label"
+
+	if _, err := os.Stat("../verify.txt_tips"); err != nil {
+		t.Skip("verify.txt_tips not found")
+	}
+
+	dir := t.TempDir()
+	svgFn, pdfFn := filepath.Join(dir, "label.svg"), filepath.Join(dir, "label.pdf")
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    int
+		wantOut string
+	}{
+		{
+			name:    "svg and pdf",
+			args:    []string{"-verifytxt", "../verify.txt_tips", "-svg", svgFn, "-pdf", pdfFn, "-uri", "-amount", "0.001", "-l", "Tips"},
+			want:    0,
+			wantOut: "Wrote label with 16 QR codes to " + svgFn + "\nWrote label with 16 QR codes to " + pdfFn + "\n",
+		}, {
+			name:    "bad amount",
+			args:    []string{"-verifytxt", "../verify.txt_tips", "-svg", svgFn, "-uri", "-amount", "lots"},
+			want:    1,
+			wantOut: "Unable to make label: Bitcoin P2PKH: amount 'lots' must be a decimal number eg 0.001",
+		}, {
+			name:    "bad level",
+			args:    []string{"-verifytxt", "../verify.txt_tips", "-svg", svgFn, "-level", "X"},
+			want:    1,
+			wantOut: "Invalid level: QR level must be L, M, Q or H not 'X'",
+		}, {
+			name:    "no output",
+			args:    []string{"-verifytxt", "../verify.txt_tips"},
+			want:    1,
+			wantOut: "Usage of label:\n",
+		}, {
+			name:    "svg csv",
+			args:    []string{"-verifytxt", "../verify.txt_tips", "-svg", filepath.Join(dir, "csv.svg"), "-title", "Tips", "-format", "csv"},
+			want:    0,
+			wantOut: "title,file,qr_codes\nTips," + filepath.Join(dir, "csv.svg") + ",16\n",
+		}, {
+			name:    "no output json",
+			args:    []string{"-verifytxt", "../verify.txt_tips", "-format", "json"},
+			want:    1,
+			wantOut: "{\n  \"error\": \"Missing arguments, run with -h for usage\"\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = append([]string{cliName}, tt.args...)
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+
+			if got := LabelMain(out); got != tt.want {
+				t.Errorf("LabelMain() = %v, want %v", got, tt.want)
+			}
+
+			if gotOut := out.String(); !strings.HasPrefix(gotOut, tt.wantOut) {
+				t.Errorf("LabelMain() = %q, want %q", gotOut, tt.wantOut)
+			}
+		})
+	}
+
+	svg, err := os.ReadFile(svgFn)
+	if err != nil || !strings.Contains(string(svg), ">bitcoin:1Mmg2eycKHomhjAikEAVehHpCS</text>") {
+		t.Errorf("LabelMain() svg = %v", err)
+	}
+
+	if pdf, err := os.ReadFile(pdfFn); err != nil || !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) {
+		t.Errorf("LabelMain() pdf = %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/timchurchard/opendime-utils/internal/report"
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// SignMain entrypoint for the sign command
+func SignMain(out io.Writer, key string) int {
+	const (
+		defaultEmpty    = ""
+		usageMessage    = "Message to sign"
+		usageCoin       = "Coin to sign for eg Litecoin (default from the WIF prefix)"
+		usageOutputFile = "Path to output file (default print to stdout)"
+		usageNetwork    = "Network: mainnet, testnet, signet or regtest"
+		usageFormat     = "Output format: text, json, csv or yaml"
+	)
+	var (
+		message     string
+		coinName    string
+		outputFn    string
+		networkName string
+		formatName  string
+	)
+
+	flag.StringVar(&message, "message", defaultEmpty, usageMessage)
+	flag.StringVar(&message, "m", defaultEmpty, usageMessage+" (shorthand)")
+
+	flag.StringVar(&coinName, "coin", defaultEmpty, usageCoin)
+
+	flag.StringVar(&outputFn, "outputfile", defaultEmpty, usageOutputFile)
+
+	flag.StringVar(&networkName, "network", string(pkg.MainNet), usageNetwork)
+	flag.StringVar(&networkName, "n", string(pkg.MainNet), usageNetwork+" (shorthand)")
+
+	flag.StringVar(&formatName, "format", string(report.Text), usageFormat)
+
+	flag.Usage = func() {
+		fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
+
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	format, err := report.ParseFormat(formatName)
+	if err != nil {
+		fmt.Fprintf(out, "Invalid format: %v", err)
+		return 1
+	}
+
+	network, err := pkg.ParseNetwork(networkName)
+	if err != nil {
+		return fail(out, format, "Invalid network: %v", err)
+	}
+
+	if message == "" {
+		return usage(out, format)
+	}
+
+	key = parseKey(network, key)
+
+	if coinName == "" {
+		modes, _, _, err := pkg.ValidateWif(network, key)
+		if err != nil {
+			return fail(out, format, "Error decoding WIF: %v", err)
+		}
+
+		coinName, err = pkg.WifCoin(modes)
+		if err != nil {
+			return fail(out, format, "Error: %v with -coin", err)
+		}
+	}
+
+	address, signature, err := pkg.SignMessage(network, coinName, key, message)
+	if err != nil {
+		return fail(out, format, "Unable to sign message: %v", err)
+	}
+
+	signed := pkg.FormatSignedMessage(coinName, address, signature, message)
+	result := report.Sign{Coin: coinName, Address: address, Message: message, Signature: signature}
+
+	if outputFn == "" {
+		if format == report.Text {
+			fmt.Fprint(out, signed)
+			return 0
+		}
+
+		result.SignedMessage = signed
+	} else {
+		err = os.WriteFile(outputFn, []byte(signed), 0o600)
+		if err != nil {
+			return fail(out, format, "Error writing output file: %s %v", outputFn, err)
+		}
+
+		result.File = outputFn
+
+		if format == report.Text {
+			fmt.Fprintf(out, "Signed message for %s\nWritten to file: %s\n", address, outputFn)
+			return 0
+		}
+	}
+
+	return writeReport(out, format, result)
+}
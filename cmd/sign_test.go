@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+func Test_SignMain(t *testing.T) {
+	const (
+		cliName           = "sign"
+		secretHex         = "dc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3"
+		compressedWif     = "L4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ"
+		bitcoinSignedOut  = "-----BEGIN BITCOIN SIGNED MESSAGE-----\r\nHello World\r\n-----BEGIN SIGNATURE-----\r\n13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu\r\nG6S9vgFjfs8bh3yySu8BNO1kGu3cPmpZmy14TkufGy0fSJLVffwbnZj6Zd0OLNKYanFn4ivn4MYhKUjjo0VHAqc=\r\n-----END BITCOIN SIGNED MESSAGE-----\r\n"
+		litecoinSignedOut = "-----BEGIN LITECOIN SIGNED MESSAGE-----\r\nHello World\r\n-----BEGIN SIGNATURE-----\r\nLUNDmHknEj5ZLNQ8we1CfbnraHJ4iPRtuG\r\nIHNbW/seabjV8JQq7G5yjwoQhRybbgpcUK/ekAbwRTW9H+o2unXs1QK6uILyged600gSpbSgk6Xf1I3zvYpUO+Q=\r\n-----END LITECOIN SIGNED MESSAGE-----\r\n"
+	)
+
+	// We manipulate the Args to set them up for the testcases, after this test we restore the initial args
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type args struct {
+		flags []string
+		key   string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    int
+		wantOut string
+	}{
+		{"bitcoin hex", args{flags: []string{"-m", "Hello World", "-coin", "Bitcoin"}, key: secretHex}, 0, bitcoinSignedOut},
+		{"bitcoin by prefix", args{flags: []string{"-m", "Hello World"}, key: secretHex}, 0, bitcoinSignedOut},
+		{"testnet ambiguous prefix", args{flags: []string{"-network", "testnet", "-m", "Hello World"}, key: "cMqgyhQGdBTb1gPd9xZ8ELuxZGdkRLZ6oNGjt9q7RcN9B672pLMm"}, 1,
+			"Error: WIF prefix is used by more than one coin (Bitcoin, Litecoin, Bitcoin Cash, Dash), choose one with -coin"},
+		{"litecoin by coin", args{flags: []string{"-m", "Hello World", "-coin", "Litecoin"}, key: compressedWif}, 0, litecoinSignedOut},
+		{"unknown coin", args{flags: []string{"-m", "Hello World", "-coin", "Nocoin"}, key: compressedWif}, 1, "Unable to sign message: coin 'Nocoin' does not support signed messages"},
+		{"invalid key", args{flags: []string{"-m", "Hello World"}, key: "L4bZ2HCx"}, 1, "Error decoding WIF: WIF malformed/wrong length"},
+		{"bitcoin csv", args{flags: []string{"-m", "Hello World", "-coin", "Bitcoin", "-format", "csv"}, key: secretHex}, 0,
+			"coin,address,message,signature,signed_message,file\nBitcoin,13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu,Hello World," +
+				"G6S9vgFjfs8bh3yySu8BNO1kGu3cPmpZmy14TkufGy0fSJLVffwbnZj6Zd0OLNKYanFn4ivn4MYhKUjjo0VHAqc=,\"" + bitcoinSignedOut + "\",\n"},
+		{"invalid key json", args{flags: []string{"-m", "Hello World", "-format", "json"}, key: "L4bZ2HCx"}, 1, "{\n  \"error\": \"Error decoding WIF: WIF malformed/wrong length\"\n}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// reset flags else panic
+			flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+			os.Args = append([]string{cliName}, tt.args.flags...)
+
+			out := &bytes.Buffer{}
+			if got := SignMain(out, tt.args.key); got != tt.want {
+				t.Errorf("SignMain() = %v, want %v", got, tt.want)
+			}
+			if gotOut := out.String(); gotOut != tt.wantOut {
+				t.Errorf("SignMain() = %q, want %q", gotOut, tt.wantOut)
+			}
+		})
+	}
+}
+
+func Test_SignMainRoundTrip(t *testing.T) {
+	const message = "Nonce: 1675bf38ec241a2308585ad0  Serial: TEST\nVersion: 2.4.0 coin=BTC"
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	fn := filepath.Join(t.TempDir(), "verify.txt")
+
+	flag.CommandLine = flag.NewFlagSet("sign", flag.ExitOnError)
+	os.Args = []string{"sign", "-m", message, "-coin", "Bitcoin", "-outputfile", fn}
+
+	out := &bytes.Buffer{}
+	if got := SignMain(out, "L4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ"); got != 0 {
+		t.Fatalf("SignMain() = %v, want 0 (%s)", got, out.String())
+	}
+
+	address, signature, parsedMessage, err := pkg.ParseVerifyTxt(fn)
+	if err != nil {
+		t.Fatalf("ParseVerifyTxt() error = %v", err)
+	}
+
+	if _, err := pkg.VerifyMessage(pkg.MainNet, address, signature, parsedMessage); err != nil {
+		t.Errorf("VerifyMessage() error = %v", err)
+	}
+}
@@ -6,38 +6,79 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/timchurchard/opendime-utils/internal"
+	"github.com/timchurchard/opendime-utils/internal/history"
+	"github.com/timchurchard/opendime-utils/internal/report"
 	"github.com/timchurchard/opendime-utils/pkg"
+	"github.com/timchurchard/opendime-utils/pkg/emulator"
+	"github.com/timchurchard/opendime-utils/pkg/qr"
 )
 
 const (
 	defaultCurrency = "usd"
 	defaultSymbol   = "$"
+	// defaultQRQuiet the quiet zone of light modules around terminal QR codes required by the QR spec
+	defaultQRQuiet = 4
+	// stdinFilename the -verifytxt filename that reads a pasted signed message from stdin
+	stdinFilename = "-"
+	// tabWidth terminal tab stops used to line up labels and values
+	tabWidth = 8
 )
 
+// stdin where answers to confirmation prompts and pasted signed messages are read from. A variable so tests can
+// provide them
+var stdin io.Reader = os.Stdin
+
 // SigtoaddrMain entrypoint for the sigtoaddr command
 func SigtoaddrMain(out io.Writer) int {
 	const (
 		defaultEmpty   = ""
 		usageVerbose   = "Verbose mode"
 		usageBalance   = "Check balance"
-		usageVerifyTxt = "Path to OPENDIME/advanced/verify.txt alternative to passing address, signature and message"
-		usageAddress   = "Bitcoin or Litecoin address. Optional with verify.txt"
-		usageSignature = "Bitcoin or Litecoin signature (required if verify.txt not used)"
+		usageVerifyTxt = "Path to OPENDIME/advanced/verify.txt (or a message signed by any wallet, - for stdin) alternative to passing address, signature and message"
+		usageAddress   = "Bitcoin, Litecoin or Ethereum (0x) address. Optional with verify.txt"
+		usageSignature = "Bitcoin or Litecoin signature, legacy/BIP137 or BIP322, or Ethereum personal_sign hex (required if verify.txt not used)"
 		usageMessage   = "Bitcoin message (required if verify.txt not used)"
+		usageNetwork   = "Network: mainnet, testnet, signet or regtest"
+		usageDevice    = "Use verify.txt from a mounted Opendime: auto or device index"
+		usageVerdict   = "Check the statement against the history of nonces and serials and print a PASS/WARN/FAIL verdict"
+		usageHistory   = "Path to the history file (default $OPENDIME_HISTORY or history.json in the user config dir)"
+		usageFirmware  = "Comma separated firmware versions to accept as known in addition to the built in list"
+		usageSpent     = "Check the spend history of every derived address. A sealed Opendime has never sent coins"
+		usageQR        = "Comma separated address types to draw as QR codes eg BitcoinP2WPKH,Ethereum or all"
+		usageQRLevel   = "QR error correction level: L, M, Q or H"
+		usageQRQuiet   = "QR quiet zone in modules"
+		usageQRInvert  = "Draw QR codes for dark text on a light background"
+		usageFormat    = "Output format: text, json, csv or yaml"
+		usageAnyMagic  = "Try the message magic of every coin, for wallets that sign with another chain's magic"
 	)
 	var (
 		err             error
 		verifyTxtFn     string
+		device          string
 		address         string
 		signature       string
 		message         string
+		networkName     string
+		network         pkg.Network
+		historyFn       string
+		knownFirmware   string
+		qrTypes         string
+		qrLevelName     string
+		qrQuiet         int
+		qrInvert        bool
+		formatName      string
+		format          report.Format
 		verbose         bool
 		balance         bool
+		verdict         bool
+		spent           bool
+		anyMagic        bool
+		result          *history.Verdict
 		verifiedMessage pkg.VerifiedMessage
 		addresses       pkg.Addresses
 	)
@@ -48,7 +89,10 @@ func SigtoaddrMain(out io.Writer) int {
 	flag.BoolVar(&balance, "balance", false, usageBalance)
 	flag.BoolVar(&balance, "b", false, usageBalance)
 
+	flag.BoolVar(&spent, "spent", false, usageSpent)
+
 	flag.StringVar(&verifyTxtFn, "verifytxt", defaultEmpty, usageVerifyTxt)
+	flag.StringVar(&device, "device", defaultEmpty, usageDevice)
 
 	flag.StringVar(&address, "address", defaultEmpty, usageAddress)
 	flag.StringVar(&address, "a", defaultEmpty, usageAddress+" (shorthand)")
@@ -59,6 +103,21 @@ func SigtoaddrMain(out io.Writer) int {
 	flag.StringVar(&message, "message", defaultEmpty, usageMessage)
 	flag.StringVar(&message, "m", defaultEmpty, usageMessage+" (shorthand)")
 
+	flag.StringVar(&networkName, "network", string(pkg.MainNet), usageNetwork)
+	flag.StringVar(&networkName, "n", string(pkg.MainNet), usageNetwork+" (shorthand)")
+
+	flag.BoolVar(&verdict, "verdict", false, usageVerdict)
+	flag.StringVar(&historyFn, "history", defaultEmpty, usageHistory)
+	flag.StringVar(&knownFirmware, "firmware", defaultEmpty, usageFirmware)
+	flag.BoolVar(&anyMagic, "any-magic", false, usageAnyMagic)
+
+	flag.StringVar(&qrTypes, "qr", defaultEmpty, usageQR)
+	flag.StringVar(&qrLevelName, "qr-level", qr.M.String(), usageQRLevel)
+	flag.IntVar(&qrQuiet, "qr-quiet", defaultQRQuiet, usageQRQuiet)
+	flag.BoolVar(&qrInvert, "qr-invert", false, usageQRInvert)
+
+	flag.StringVar(&formatName, "format", string(report.Text), usageFormat)
+
 	flag.Usage = func() {
 		fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
 
@@ -73,160 +132,543 @@ func SigtoaddrMain(out io.Writer) int {
 		panic(errors.New("Fatal! sanity tests failed! quitting."))
 	}
 
+	format, err = report.ParseFormat(formatName)
+	if err != nil {
+		fmt.Fprintf(out, "Invalid format: %v", err)
+		return 1
+	}
+
+	network, err = pkg.ParseNetwork(networkName)
+	if err != nil {
+		return fail(out, format, "Invalid network: %v", err)
+	}
+
+	qrLevel, err := parseQROptions(qrLevelName, qrQuiet)
+	if err != nil {
+		return fail(out, format, "Invalid QR options: %v", err)
+	}
+
+	qrIDs, err := parseAddressTypeIDs(qrTypes)
+	if err != nil {
+		return fail(out, format, "Invalid QR options: %v", err)
+	}
+
+	if len(qrIDs) > 0 && format != report.Text {
+		return fail(out, format, "Invalid QR options: QR codes can only be drawn with -format text")
+	}
+
+	if device != "" {
+		verifyTxtFn, err = deviceVerifyTxt(device)
+		if err != nil {
+			return fail(out, format, "Unable to find Opendime: %v", err)
+		}
+	}
+
 	if verifyTxtFn != "" {
-		address, signature, message, err = pkg.ParseVerifyTxt(verifyTxtFn)
+		address, signature, message, err = parseVerifyTxt(verifyTxtFn)
 		if errors.Is(err, os.ErrNotExist) {
-			fmt.Fprintf(out, "File '%s' not found", verifyTxtFn)
-			return 1
+			return fail(out, format, "File '%s' not found", verifyTxtFn)
 		}
 		if err != nil {
-			fmt.Fprintf(out, "Unable to parse verify.txt: %v", err)
-			return 1
+			return fail(out, format, "Unable to parse verify.txt: %v", err)
 		}
 	} else if address == "" {
 		// verify.txt or (address, signature, message) is required so print usage
-		flag.Usage()
-		return 1
+		return usage(out, format)
 	}
 
-	verifiedMessage, err = pkg.VerifyMessage(address, signature, message)
+	if anyMagic {
+		verifiedMessage, err = pkg.VerifyMessageAnyMagic(network, address, signature, message)
+	} else {
+		verifiedMessage, err = pkg.VerifyMessage(network, address, signature, message)
+	}
 	if err != nil {
-		fmt.Fprintf(out, "Unable to verify signature: %v", err)
-		return 1
+		return fail(out, format, "Unable to verify signature: %v", err)
+	}
+
+	if verdict {
+		seen, err := openHistory(historyFn, knownFirmware)
+		if err != nil {
+			return fail(out, format, "Unable to find history: %v", err)
+		}
+
+		checked, err := seen.Check(verifiedMessage, message, time.Now())
+		if err != nil {
+			return fail(out, format, "Unable to check history: %v", err)
+		}
+
+		result = &checked
+	}
+
+	// Addresses and their spend history are needed by every format
+	var spendChecks []internal.SpendCheck
+
+	if verifiedMessage.PublicKeyHex != "" {
+		addresses, err = pkg.GetAddresses(verifiedMessage)
+		if err != nil {
+			return fail(out, format, "Failed to make addresses: %v", err)
+		}
+
+		if spent {
+			spendChecks = internal.CheckAddressesSpent(network, addresses)
+			raiseSpent(result, spendChecks)
+		}
+	}
+
+	if format != report.Text {
+		return writeSigtoaddr(out, format, verifiedMessage, message, addresses, spendChecks, result, balance)
 	}
 
 	if verbose {
 		fmt.Fprintf(out, "Public key hex: %s\n", verifiedMessage.PublicKeyHex)
+		fmt.Fprintf(out, "Signature proves: %s\n", verifiedMessage.AddressType)
 	}
 
-	addresses, err = pkg.GetAddresses(verifiedMessage)
-	if err != nil {
-		fmt.Fprintf(out, "Failed to make addresses: %v", err)
+	// With -any-magic the magic that verified may not be the address's own chain, always show which it was
+	if (verbose || anyMagic) && verifiedMessage.MessageMagic != "" {
+		fmt.Fprintf(out, "Message magic: %s\n", pkg.MagicName(verifiedMessage.MessageMagic))
+	}
+
+	// Messages signed by an Opendime carry a statement (serial, firmware, coin)
+	statement, err := pkg.ParseStatement(message)
+	if err == nil {
+		if verbose {
+			printStatement(out, statement)
+		}
+
+		if err := statement.CoinMismatch(verifiedMessage); err != nil {
+			fmt.Fprintf(out, "Warning: %v\n", err)
+		}
+	}
+
+	if verifiedMessage.PublicKeyHex == "" {
+		// eg BIP322 P2TR key-path proofs do not reveal the internal public key
+		fmt.Fprintf(out, "Signature valid for %s but it does not reveal the public key so no addresses can be derived\n", address)
+		return printVerdict(out, result)
+	}
+
+	prettyPrintAddresses(out, network, addresses, balance)
+
+	for _, derived := range addresses.Derived {
+		if !qrIDs[derived.ID] {
+			continue
+		}
+
+		if err := printQR(out, derived.Label, derived.Address, qrLevel, qrQuiet, qrInvert); err != nil {
+			return fail(out, format, "Unable to make QR code: %v", err)
+		}
+	}
+
+	if spent {
+		printSpent(out, spendChecks)
+	}
+
+	// The verdict is printed last so it is the first thing seen after the addresses
+	return printVerdict(out, result)
+}
+
+// writeSigtoaddr writes the sigtoaddr report in a machine readable format and returns the exit code (1 for a FAIL
+// verdict). spendChecks is nil unless the spend history was checked
+func writeSigtoaddr(out io.Writer, format report.Format, verifiedMessage pkg.VerifiedMessage, message string,
+	addresses pkg.Addresses, spendChecks []internal.SpendCheck, verdict *history.Verdict, balance bool,
+) int {
+	result := report.Sigtoaddr{
+		Verified:  report.NewVerified(verifiedMessage),
+		Opendime:  verifiedMessage.Address,
+		Addresses: []report.Address{},
+	}
+
+	if statement, err := pkg.ParseStatement(message); err == nil {
+		result.Statement = report.NewStatement(statement)
+
+		if err := statement.CoinMismatch(verifiedMessage); err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+		}
+	}
+
+	if verifiedMessage.PublicKeyHex == "" {
+		result.Warnings = append(result.Warnings, "the signature does not reveal the public key so no addresses can be derived")
+	} else {
+		result.PublicKeyCompressed, result.PublicKeyUncompressed = addresses.CompressedHex, addresses.UncompressedHex
+		result.Addresses = report.NewAddresses(addresses)
+
+		if balance {
+			checkBalances(verifiedMessage.Network, result.Addresses)
+		}
+
+		report.AddSpent(result.Addresses, spendChecks)
+	}
+
+	result.Verdict = report.NewVerdict(verdict)
+
+	if err := report.Write(out, format, result); err != nil {
+		return fail(out, format, "Unable to write output: %v", err)
+	}
+
+	if verdict != nil && verdict.Level == history.Fail {
 		return 1
 	}
 
-	prettyPrintAddresses(out, addresses, balance)
+	return 0
+}
+
+// checkBalances adds the balance of every address, skipping any that cannot be checked
+func checkBalances(network pkg.Network, addresses []report.Address) {
+	for idx := range addresses {
+		amount, value, extra, err := internal.CheckBalance(network, addresses[idx].Address, defaultCurrency)
+		if err != nil {
+			continue
+		}
+
+		addresses[idx].Balance = &report.Balance{Amount: amount, Value: value, Currency: defaultCurrency, Extra: extra}
+
+		// Put a terrible sleep here to reduce hammering on public/free APIs
+		time.Sleep(time.Second / 3)
+	}
+}
+
+// fail prints the error message, as an error object in machine readable formats, and returns the exit code 1
+func fail(out io.Writer, format report.Format, message string, a ...any) int {
+	message = fmt.Sprintf(message, a...)
+
+	if format == report.Text {
+		fmt.Fprint(out, message)
+		return 1
+	}
+
+	if err := report.Write(out, format, report.Error{Error: message}); err != nil {
+		fmt.Fprint(out, message)
+	}
+
+	return 1
+}
+
+// writeReport writes the report in a machine readable format and returns the exit code
+func writeReport(out io.Writer, format report.Format, result report.Table) int {
+	if err := report.Write(out, format, result); err != nil {
+		return fail(out, format, "Unable to write output: %v", err)
+	}
+
+	return 0
+}
+
+// usage prints the usage, or an error object in machine readable formats, and returns the exit code 1
+func usage(out io.Writer, format report.Format) int {
+	if format == report.Text {
+		flag.Usage()
+		return 1
+	}
+
+	return fail(out, format, "Missing arguments, run with -h for usage")
+}
+
+// openHistory opens the history at path, or the default path if empty, accepting the comma separated knownFirmware
+// versions as well as the built in list
+func openHistory(path, knownFirmware string) (*history.History, error) {
+	if path == "" {
+		var err error
+
+		path, err = history.DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	seen := history.Open(path)
+	if knownFirmware != "" {
+		seen.KnownFirmware = append(seen.KnownFirmware, strings.Split(knownFirmware, ",")...)
+	}
+
+	return seen, nil
+}
+
+// countSpent returns the number of addresses that have sent coins and the number that could not be checked
+func countSpent(checks []internal.SpendCheck) (int, int) {
+	var spentAddresses, unchecked int
+
+	for _, check := range checks {
+		switch {
+		case check.Err != nil:
+			unchecked++
+		case check.Spends > 0:
+			spentAddresses++
+		}
+	}
+
+	return spentAddresses, unchecked
+}
+
+// raiseSpent raises the verdict (if there is one) to FAIL if any address has sent coins, or WARN if any could not
+// be checked so a fresh key can not be promised
+func raiseSpent(verdict *history.Verdict, checks []internal.SpendCheck) {
+	if verdict == nil {
+		return
+	}
+
+	spentAddresses, unchecked := countSpent(checks)
+
+	if spentAddresses > 0 {
+		verdict.Raise(history.Fail, "%d derived address(es) have sent coins, the private key has been exposed", spentAddresses)
+	}
+
+	if unchecked > 0 {
+		verdict.Raise(history.Warn, "%d derived address(es) could not be checked for spends", unchecked)
+	}
+}
+
+// printSpent prints the spend history of every derived address and a warning if any have sent coins
+func printSpent(out io.Writer, checks []internal.SpendCheck) {
+	fmt.Fprintln(out, "Spend history:")
+
+	for _, check := range checks {
+		fmt.Fprintf(out, "%s %s ", padLabel("- "+check.Derived.Label), check.Derived.Address)
+
+		switch {
+		case check.Err != nil:
+			fmt.Fprintf(out, "not checked: %v\n", check.Err)
+		case check.Spends > 0:
+			fmt.Fprintf(out, "SPENT %d\n", check.Spends)
+		default:
+			fmt.Fprintln(out, "never spent")
+		}
+	}
+
+	spentAddresses, unchecked := countSpent(checks)
+
+	switch {
+	case spentAddresses > 0:
+		fmt.Fprintln(out, "****************************************************************")
+		fmt.Fprintf(out, "WARNING! NOT FRESH! %d address(es) have sent coins so the private key has been exposed.\n", spentAddresses)
+		fmt.Fprintln(out, "Do not accept this Opendime as sealed, anyone with the key can take the coins.")
+		fmt.Fprintln(out, "****************************************************************")
+	case unchecked > 0:
+		fmt.Fprintf(out, "No spends found but %d address(es) could not be checked\n", unchecked)
+	default:
+		fmt.Fprintln(out, "No spends found, the key has never sent coins")
+	}
+}
+
+// printVerdict prints the verdict, if there is one, and returns the exit code (1 for FAIL)
+func printVerdict(out io.Writer, verdict *history.Verdict) int {
+	if verdict == nil {
+		return 0
+	}
+
+	fmt.Fprintf(out, "%s%s\n", padLabel("Verdict:"), verdict.Level)
+
+	for _, reason := range verdict.Reasons {
+		fmt.Fprintf(out, "- %s\n", reason)
+	}
+
+	if verdict.Level == history.Fail {
+		return 1
+	}
 
 	return 0
 }
 
+// verify.txt_tips and litecoin_verify.txt_tips (in the repository root) exported from real Opendimes. The emulated
+// verify.txt are signed and verified by this code, only these catch a bug shared by both
+const (
+	tipsVerifyTxt = "-----BEGIN BITCOIN SIGNED MESSAGE-----\r\n" +
+		"Nonce: 1675bf38ec241a2308585ad0  Serial: DDRRNOCZJRIFCIBAEBJDOJQY74\r\n" +
+		"Version: 2.4.0 time=20190207.130255 git=master@e233940e coin=BTC\r\n" +
+		"-----BEGIN SIGNATURE-----\r\n" +
+		"1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR\r\n" +
+		"G1pnvdb0RfKfv3Jhg4x0XBQqv1KQx3WFRaxTiUVN84fpIzxOBgapJb/Dpy6auJ28xcHaBxl3XHBbJejfokjgtmg=\r\n" +
+		"-----END BITCOIN SIGNED MESSAGE-----\r\n"
+	litecoinTipsVerifyTxt = "-----BEGIN LITECOIN SIGNED MESSAGE-----\r\n" +
+		"UNSEALED -- UNSEALED -- UNSEALED\r\n" +
+		"Nonce: 961f7ecaa917101d4241a43a  Serial: PZZUNUKLGRIFCICKJIYDEEIC74\r\n" +
+		"Version: 2.3.0 time=20171018.143523 git=master@8fb7cfd coin=LTC\r\n" +
+		"-----BEGIN SIGNATURE-----\r\n" +
+		"LhNxvyyxBGv1Z9CKUaYPE5azvFCMnDMbRN\r\n" +
+		"HAVOlsYZ4/sj1lVHlqeYd4jbxRRkD5zqp6MG6mNKPmfEdE8rwByiQ+aFTuEpXswhV4y5S5dxREq3pkdq4CjU3/A=\r\n" +
+		"-----END LITECOIN SIGNED MESSAGE-----\r\n"
+)
+
 func sanityTests() int {
 	var (
 		err             error
-		address         string
-		signature       string
-		message         string
 		verifiedMessage pkg.VerifiedMessage
 		addresses       pkg.Addresses
 	)
 
-	// Use working directory
-	wd, err := os.Getwd()
-	if err != nil {
-		return 1
-	}
-	wdPath := filepath.Dir(wd)
+	// The other verify.txt fixtures are made by emulated Opendimes signing a fixed nonce with this key
+	const (
+		emulatedNonce         = "1675bf38ec241a2308585ad0"
+		emulatedSerial        = "SANITYTESTSANITYTESTSANITY"
+		emulatedBitcoinWif    = "5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC"
+		emulatedLitecoinWif   = "6vnxAJ79bpSu2KfcZhJt82AQYo7fuiHtbLZpR1FzqP6B4iaR7rR"
+		emulatedCompressedHex = "032509ef79a4796f752e024d7b4ba295f84397ba5aba836718b614118f3c46a54e"
+	)
 
-	btcVerifyTxtFn, _ := filepath.Abs(filepath.Join(wdPath, "./verify.txt_tips"))
-	ltcVerifyTxtFn, _ := filepath.Abs(filepath.Join(wdPath, "./litecoin_verify.txt_tips"))
+	emulatedAddresses := map[string]string{
+		"BitcoinP2PKH":               "13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu",
+		"BitcoinP2PKHCompressed":     "1A9GW5SxA4qW5ZhymW1uPaj6N4vnVJVWZW",
+		"BitcoinP2SHP2WPKH":          "3FiBeABktE7t7WKWrUrX4RKNrq3dVbokdR",
+		"BitcoinP2WPKH":              "bc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqzfhzrq",
+		"BitcoinP2TR":                "bc1pswmpmx5nwpenu2fjdd59f8ulrhw27zf7ag8rxjxugx85033zeukqut6xuf",
+		"Ethereum":                   "0xCb19D769c583599DbD7D6D78Eb3279a362672747",
+		"LitecoinP2PKH":              "LMrjSZiwF9FG73FTUP9pv4Tp7vtudfiMG7",
+		"LitecoinP2PKHCompressed":    "LUNDmHknEj5ZLNQ8we1CfbnraHJ4iPRtuG",
+		"LitecoinP2SHP2WPKH":         "MMvKx3biqLyJv1bQxMqrt4ZnBXe5ZETyYb",
+		"LitecoinP2WPKH":             "ltc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqx4dxms",
+		"LitecoinP2TR":               "ltc1pswmpmx5nwpenu2fjdd59f8ulrhw27zf7ag8rxjxugx85033zeukql05kxv",
+		"DogecoinP2PKH":              "D7msicMkTtuVPEju2qA6BoZenrFvrMCm24",
+		"BitcoinCashP2PKH":           "bitcoincash:qqwwfur42pxtqd22xx50fea3x3lsu40jds3xjspyqj",
+		"BitcoinCashP2PKHCompressed": "bitcoincash:qpjyjejvx5q2evqt2nu42jfq8x9vx6edsqjtne9lvq",
+		"DashP2PKH":                  "XdKd1c518CDo1B9tA8UkVa5qk47KZSAgbE",
+		"DashP2PKHCompressed":        "Xjq7LL6r7n46EWJZdPL8F7QtCQWUczLj2P",
+	}
 
 	tests := []struct {
-		verifytxt string
-		address   string
-		signature string
-		message   string
-		addresses pkg.Addresses
+		// verifyTxt of a real Opendime, or emulated coin of an emulated Opendime making the verify.txt signing with key
+		verifyTxt     string
+		emulated      string
+		key           string
+		address       string
+		signature     string
+		message       string
+		compressedHex string
+		addresses     map[string]string
 	}{
 		{
 			address: "1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f", signature: "HwPlEOxTxs62ruMHZvamv0wmUlbbaY/2ZSqw9Hpdw+FWfgXuSxQ9x55ceSiFyvnlpiZjt+KIhSYnhGnCv8iDe5o=", message: "Hello World!",
-			addresses: pkg.Addresses{
-				Original:                "1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f",
-				BitcoinP2PKH:            "1GLfgL9yKVTRRG1D4fdKkEuEQqAE7ob1eB",
-				BitcoinP2PKHCompressed:  "1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f",
-				BitcoinP2WPKH:           "bc1q7qcf63rtp20dsalcwmceucxs0kwn75l95nsxjf",
-				Ethereum:                "0x5D0a9F69035Be4275204f9eBbd5cC049e42429c6",
-				LitecoinP2PKH:           "LaZcwYToQ9hUg4hNEocd2Fxzd3XWEMFnQ5",
-				LitecoinP2PKHCompressed: "Lh7xg2yUmNWq668Fihx3rjLb13XkbHuBMQ",
-				LitecoinP2WPKH:          "ltc1q7qcf63rtp20dsalcwmceucxs0kwn75l9s02z2e",
-				DogecoinP2PKH:           "DLUmDb6ccuMhxGBooFctJ14qHxtXVWYf4P",
-				UncompressedHex:         "0471bb3ef523055565dd5f9864047b9fe93efa10151ff4bb3640f7de6dfdd76cea9d5cb2da17d725a835f25971818e54acc1db69e4866ea23c9dc33f57cb286315",
-				CompressedHex:           "0371bb3ef523055565dd5f9864047b9fe93efa10151ff4bb3640f7de6dfdd76cea",
+			compressedHex: "0371bb3ef523055565dd5f9864047b9fe93efa10151ff4bb3640f7de6dfdd76cea",
+			addresses: map[string]string{
+				"BitcoinP2PKH":               "1GLfgL9yKVTRRG1D4fdKkEuEQqAE7ob1eB",
+				"BitcoinP2PKHCompressed":     "1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f",
+				"BitcoinP2SHP2WPKH":          "394hCajQZnpLCqmwB3pRRGVG7ZGqfDM2VA",
+				"BitcoinP2WPKH":              "bc1q7qcf63rtp20dsalcwmceucxs0kwn75l95nsxjf",
+				"BitcoinP2TR":                "bc1p23xp07c82m2rc9j82k5jl6tz0mvu964699pvyvvk899m5h98cgpszfwt9e",
+				"Ethereum":                   "0x5D0a9F69035Be4275204f9eBbd5cC049e42429c6",
+				"LitecoinP2PKH":              "LaZcwYToQ9hUg4hNEocd2Fxzd3XWEMFnQ5",
+				"LitecoinP2PKHCompressed":    "Lh7xg2yUmNWq668Fihx3rjLb13XkbHuBMQ",
+				"LitecoinP2SHP2WPKH":         "MFGqWU9NWufm1M3qGvomEujfSFsHhWBe95",
+				"LitecoinP2WPKH":             "ltc1q7qcf63rtp20dsalcwmceucxs0kwn75l9s02z2e",
+				"LitecoinP2TR":               "ltc1p23xp07c82m2rc9j82k5jl6tz0mvu964699pvyvvk899m5h98cgpspdqmlu",
+				"DogecoinP2PKH":              "DLUmDb6ccuMhxGBooFctJ14qHxtXVWYf4P",
+				"BitcoinCashP2PKH":           "bitcoincash:qz5yyya0qyswmmjv92px0h9hahugc97adgwdlnuxf9",
+				"BitcoinCashP2PKHCompressed": "bitcoincash:qrcrp82ydv9fakrhlpm0r8nq6p7e606nu5zvxd2yrw",
+				"DashP2PKH":                  "Xr2WWaosHCg1aCbnvYwYbmb2FAjvD6AEz4",
+				"DashP2PKHCompressed":        "XxarF5KYeRVMzE2gQTGySExcdAkAZCCjm8",
 			},
 		}, {
-			address:   "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR",
-			verifytxt: btcVerifyTxtFn,
-			addresses: pkg.Addresses{
-				Original:                "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR",
-				BitcoinP2PKH:            "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR",
-				BitcoinP2PKHCompressed:  "129azYLPaG55Kb7z1TgvBbj6nRjYFcNMqE",
-				BitcoinP2WPKH:           "bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5",
-				Ethereum:                "0x76270d9D9afC0cf4EbfFBafE6401E01cb0F021Ce",
-				LitecoinP2PKH:           "LfzdHsHSPx3pxXrsvN9nviMaQeejdnT81s",
-				LitecoinP2PKHCompressed: "LLNYFkeDevK8aPp9BbgDTcnrze6pQc7D6s",
-				LitecoinP2WPKH:          "ltc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72ymrkmy",
-				DogecoinP2PKH:           "DRumZuvFchi4EjMKUpA4CTTR5a1kpHqQXH",
-				UncompressedHex:         "04f27deec87586e475f828cb3cd34d2a02a674c204875e91b90ce4ce1e8773289587979932eef0c5f76c5d5fc692db94749e4efba67b692f564190c4b36ca8763a",
-				CompressedHex:           "02f27deec87586e475f828cb3cd34d2a02a674c204875e91b90ce4ce1e87732895",
+			address:       "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR",
+			verifyTxt:     tipsVerifyTxt,
+			compressedHex: "02f27deec87586e475f828cb3cd34d2a02a674c204875e91b90ce4ce1e87732895",
+			addresses: map[string]string{
+				"BitcoinP2PKH":               "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR",
+				"BitcoinP2PKHCompressed":     "129azYLPaG55Kb7z1TgvBbj6nRjYFcNMqE",
+				"BitcoinP2SHP2WPKH":          "32cxR6sS9HFeN1EbnesPE1rge4hU9Xh8cu",
+				"BitcoinP2WPKH":              "bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5",
+				"BitcoinP2TR":                "bc1ph58r4m6x3ngsy5fkufpdsu9zjshphwmc05snnyh9rf2pldw2y0qsenskej",
+				"Ethereum":                   "0x76270d9D9afC0cf4EbfFBafE6401E01cb0F021Ce",
+				"LitecoinP2PKH":              "LfzdHsHSPx3pxXrsvN9nviMaQeejdnT81s",
+				"LitecoinP2PKHCompressed":    "LLNYFkeDevK8aPp9BbgDTcnrze6pQc7D6s",
+				"LitecoinP2SHP2WPKH":         "M8q6izHQ6Q75AWWVtXrj3f75xmHv4C2iuj",
+				"LitecoinP2WPKH":             "ltc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72ymrkmy",
+				"LitecoinP2TR":               "ltc1ph58r4m6x3ngsy5fkufpdsu9zjshphwmc05snnyh9rf2pldw2y0qs6h7xrh",
+				"DogecoinP2PKH":              "DRumZuvFchi4EjMKUpA4CTTR5a1kpHqQXH",
+				"BitcoinCashP2PKH":           "bitcoincash:qr3a2ed6zc7xmjrr580esu8a9vdkskml2vu3vxcuuy",
+				"BitcoinCashP2PKHCompressed": "bitcoincash:qqxf04ppx7zw7mq3qht66xtwqe0wjufreg00853ds0",
+				"DashP2PKH":                  "XwTWrudWH12MrfmJc7UiWDyc2ms9YJ1zbg",
+				"DashP2PKHCompressed":        "XbqRpnzHXyHfUXiZsM1938QtcmKENg2k8V",
 			},
 		}, {
-			address:   "LLsXEU59RyoMmjgCkUAghxTLr6FXoRCgQT",
-			signature: "H021r+HxbXZo2Vkuyq0D/pfz8kllqDzmOzczJXBanIytdsbZKPlg3q1NhytyLXp03DQa//0zoOjoJfVUjZORql8=",
-			message:   "Hello World",
-			addresses: pkg.Addresses{
-				Original:                "LLsXEU59RyoMmjgCkUAghxTLr6FXoRCgQT",
-				BitcoinP2PKH:            "1FZ33nWeZFk2qv8PnCL2VR2wA3KnGchNbZ",
-				BitcoinP2PKHCompressed:  "12eZyFmKMKZJWvz3aLBPRwPadstFaFGKAF",
-				BitcoinP2WPKH:           "bc1qzgfsnjuz7972nd9jtqh26qc00ltjns3tjdewkt",
-				Ethereum:                "0x33a5f5ff5d6Aeb3152d223C5407C1e71Bb202C76",
-				LitecoinP2PKH:           "LZmzJzpUduz66ipYxLKKmS6hNFh4MgNPKy",
-				LitecoinP2PKHCompressed: "LLsXEU59RyoMmjgCkUAghxTLr6FXoRCgQT",
-				LitecoinP2WPKH:          "ltc1qzgfsnjuz7972nd9jtqh26qc00ltjns3tk3r2wm",
-				DogecoinP2PKH:           "DKh8b3THrfeKNvJzWnKb3BCY3B45ZzCzeH",
-				UncompressedHex:         "04a2e8f5aa9c46242cdc6463adac2ef8e6bb8b17202c06d17c647066ed143535ac1f93e66cc499170185ec79b2ef5c04119282544fea4c8072ff87711e13597bcf",
-				CompressedHex:           "03a2e8f5aa9c46242cdc6463adac2ef8e6bb8b17202c06d17c647066ed143535ac",
+			address:       "13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu",
+			emulated:      pkg.Bitcoin,
+			key:           emulatedBitcoinWif,
+			compressedHex: emulatedCompressedHex,
+			addresses:     emulatedAddresses,
+		}, {
+			address:       "LLsXEU59RyoMmjgCkUAghxTLr6FXoRCgQT",
+			signature:     "H021r+HxbXZo2Vkuyq0D/pfz8kllqDzmOzczJXBanIytdsbZKPlg3q1NhytyLXp03DQa//0zoOjoJfVUjZORql8=",
+			message:       "Hello World",
+			compressedHex: "03a2e8f5aa9c46242cdc6463adac2ef8e6bb8b17202c06d17c647066ed143535ac",
+			addresses: map[string]string{
+				"BitcoinP2PKH":               "1FZ33nWeZFk2qv8PnCL2VR2wA3KnGchNbZ",
+				"BitcoinP2PKHCompressed":     "12eZyFmKMKZJWvz3aLBPRwPadstFaFGKAF",
+				"BitcoinP2SHP2WPKH":          "37Tzqb3jaqiaQixthBSawzs3J2s2QQBn1X",
+				"BitcoinP2WPKH":              "bc1qzgfsnjuz7972nd9jtqh26qc00ltjns3tjdewkt",
+				"BitcoinP2TR":                "bc1pp28lnuk64984200ejjzae6hz6sh4n6rle6fffcz5pq7jhgu55r2sn3l3y8",
+				"Ethereum":                   "0x33a5f5ff5d6Aeb3152d223C5407C1e71Bb202C76",
+				"LitecoinP2PKH":              "LZmzJzpUduz66ipYxLKKmS6hNFh4MgNPKy",
+				"LitecoinP2PKHCompressed":    "LLsXEU59RyoMmjgCkUAghxTLr6FXoRCgQT",
+				"LitecoinP2SHP2WPKH":         "MDg99UThXxa1DEEno4Rvme7ScjTURJqcBg",
+				"LitecoinP2WPKH":             "ltc1qzgfsnjuz7972nd9jtqh26qc00ltjns3tk3r2wm",
+				"LitecoinP2TR":               "ltc1pp28lnuk64984200ejjzae6hz6sh4n6rle6fffcz5pq7jhgu55r2ss43p7z",
+				"DogecoinP2PKH":              "DKh8b3THrfeKNvJzWnKb3BCY3B45ZzCzeH",
+				"BitcoinCashP2PKH":           "bitcoincash:qz06pjqx9002mufpmelpxj0lj9psnmavgs4994kzh3",
+				"BitcoinCashP2PKHCompressed": "bitcoincash:qqfpxzwtstche2d5kfvzatgrpalaw2wz9vqy33gg2h",
+				"DashP2PKH":                  "XqEst3AYWxxczriye5eFLwiizNuUGp8jyb",
+				"DashP2PKHCompressed":        "XcLQoWRDK2mtfsadSDVcHU5NUDTwdFd3jh",
 			},
 		}, {
-			address:   "LhNxvyyxBGv1Z9CKUaYPE5azvFCMnDMbRN",
-			verifytxt: ltcVerifyTxtFn,
-			addresses: pkg.Addresses{
-				Original:                "LhNxvyyxBGv1Z9CKUaYPE5azvFCMnDMbRN",
-				BitcoinP2PKH:            "1PA1fmg86cfxJLWAJSZ5x4XEi2q5kDxpBk",
-				BitcoinP2PKHCompressed:  "17tcs8A77LNzH3QqwdGjdKcVPiB1Ka3c2j",
-				BitcoinP2WPKH:           "bc1qfwf7s8qrlcjfulqymrrw3mejnwwas9y5wz5v8r",
-				Ethereum:                "0xDdb5Fc6f27921669FCd177f6877A69356dAe889C",
-				LitecoinP2PKH:           "LhNxvyyxBGv1Z9CKUaYPE5azvFCMnDMbRN",
-				LitecoinP2PKHCompressed: "LS7a8LTwBzd3Xr717mG2uLgFbvYHQbbJ64",
-				LitecoinP2WPKH:          "ltc1qfwf7s8qrlcjfulqymrrw3mejnwwas9y527wgln",
-				DogecoinP2PKH:           "DTJ7D2cmQ2aEqLgm32YeVpgqbAZP2QHE5i",
-				UncompressedHex:         "04db8b0bc1bf85c9727d31b97fc7483b2d9bbc85d57f7e2ed8f617c98a96966271a41db637664355f9c490abd73b8e68a62afb1d40913fc1384f9edb2475009b89",
-				CompressedHex:           "03db8b0bc1bf85c9727d31b97fc7483b2d9bbc85d57f7e2ed8f617c98a96966271",
+			address:       "LMrjSZiwF9FG73FTUP9pv4Tp7vtudfiMG7",
+			emulated:      pkg.Litecoin,
+			key:           emulatedLitecoinWif,
+			compressedHex: emulatedCompressedHex,
+			addresses:     emulatedAddresses,
+		}, {
+			address:       "LhNxvyyxBGv1Z9CKUaYPE5azvFCMnDMbRN",
+			verifyTxt:     litecoinTipsVerifyTxt,
+			compressedHex: "03db8b0bc1bf85c9727d31b97fc7483b2d9bbc85d57f7e2ed8f617c98a96966271",
+			addresses: map[string]string{
+				"BitcoinP2PKH":               "1PA1fmg86cfxJLWAJSZ5x4XEi2q5kDxpBk",
+				"BitcoinP2PKHCompressed":     "17tcs8A77LNzH3QqwdGjdKcVPiB1Ka3c2j",
+				"BitcoinP2SHP2WPKH":          "3C1hmUAeK3hjSXm4LhtEBFZMpxcGUS6V83",
+				"BitcoinP2WPKH":              "bc1qfwf7s8qrlcjfulqymrrw3mejnwwas9y5wz5v8r",
+				"BitcoinP2TR":                "bc1p2uxgv56u5hdhc7ftwj7j73d0emt8f4lafptpuumk4jvlaa3nndlq4m9c3g",
+				"Ethereum":                   "0xDdb5Fc6f27921669FCd177f6877A69356dAe889C",
+				"LitecoinP2PKH":              "LhNxvyyxBGv1Z9CKUaYPE5azvFCMnDMbRN",
+				"LitecoinP2PKHCompressed":    "LS7a8LTwBzd3Xr717mG2uLgFbvYHQbbJ64",
+				"LitecoinP2SHP2WPKH":         "MJDr5MacGAZAF32xSasZztom9fCiXyGN6Q",
+				"LitecoinP2WPKH":             "ltc1qfwf7s8qrlcjfulqymrrw3mejnwwas9y527wgln",
+				"LitecoinP2TR":               "ltc1p2uxgv56u5hdhc7ftwj7j73d0emt8f4lafptpuumk4jvlaa3nndlqkltgtd",
+				"DogecoinP2PKH":              "DTJ7D2cmQ2aEqLgm32YeVpgqbAZP2QHE5i",
+				"BitcoinCashP2PKH":           "bitcoincash:qreswydrrlkp3ewa2evuqje7n8kklxscdgsmq9vs2d",
+				"BitcoinCashP2PKHCompressed": "bitcoincash:qp9e86quq0lzf8nuqnvvd680x2demkq5jsfprnerye",
+				"DashP2PKH":                  "XxqrW2L24KtYTH6kAKsJobD2YNQmiZxobr",
+				"DashP2PKHCompressed":        "XhaThNp153baRz1RoWaxUrJHE3khPnen7T",
 			},
 		},
 	}
 	for _, tt := range tests {
-		if tt.verifytxt != "" {
-			address, signature, message, err = pkg.ParseVerifyTxt(tt.verifytxt)
+		verifyTxt := tt.verifyTxt
+
+		if tt.emulated != "" {
+			device, err := emulator.New(emulator.Options{Coin: tt.emulated, Key: tt.key, Serial: emulatedSerial})
+			if err != nil {
+				return 1
+			}
+
+			verifyTxt, err = device.SignedMessage(emulatedNonce)
 			if err != nil {
-				if errors.Is(err, os.ErrNotExist) {
-					// Skip this test since can't find verify.txt file
-					continue
-				}
 				return 1
 			}
+		}
 
-			verifiedMessage, err = pkg.VerifyMessage(address, signature, message)
+		if verifyTxt != "" {
+			messages, err := pkg.ParseSignedMessages(strings.NewReader(verifyTxt))
+			if err != nil || len(messages) != 1 || messages[0].Address != tt.address {
+				return 1
+			}
+
+			verifiedMessage, err = pkg.VerifyMessage(pkg.MainNet, messages[0].Address, messages[0].Signature, messages[0].Message)
 			if err != nil {
 				return 1
 			}
 		} else {
-			verifiedMessage, err = pkg.VerifyMessage(tt.address, tt.signature, tt.message)
+			verifiedMessage, err = pkg.VerifyMessage(pkg.MainNet, tt.address, tt.signature, tt.message)
 			if err != nil {
 				return 1
 			}
 		}
 
 		addresses, err = pkg.GetAddresses(verifiedMessage)
-		if err != nil || addresses != tt.addresses {
+		if err != nil || addresses.CompressedHex != tt.compressedHex || !reflect.DeepEqual(addresses.Map(), tt.addresses) {
 			return 1
 		}
 	}
@@ -234,35 +676,14 @@ func sanityTests() int {
 	return 0
 }
 
-func prettyPrintAddresses(out io.Writer, addresses pkg.Addresses, balance bool) {
-	prefixes := map[string]string{
-		"BitcoinP2PKH":            "Bitcoin P2PKH\t\t\t",
-		"BitcoinP2PKHCompressed":  "Bitcoin P2PKH (Compressed)\t",
-		"BitcoinP2WPKH":           "Bitcoin P2WPKH\t\t",
-		"Ethereum":                "Ethereum\t\t\t",
-		"LitecoinP2PKH":           "Litecoin P2PKH\t\t",
-		"LitecoinP2PKHCompressed": "Litecoin P2PKH (Compressed)\t",
-		"LitecoinP2WPKH":          "Litecoin P2WPKH\t\t",
-		"DogecoinP2PKH":           "Dogecoin P2PKH\t\t",
-	}
-
+func prettyPrintAddresses(out io.Writer, network pkg.Network, addresses pkg.Addresses, balance bool) {
 	fmt.Fprintf(out, "Addresses for Opendime:\t%s\n", addresses.Original)
 
-	v := reflect.ValueOf(addresses)
-	typeOfS := v.Type()
-
-	for i := 0; i < v.NumField(); i++ {
-		fieldName := typeOfS.Field(i).Name
-		if _, ok := prefixes[fieldName]; !ok {
-			continue
-		}
-
-		address := fmt.Sprintf("%s", v.Field(i).Interface())
-
-		fmt.Fprintf(out, "- %s %s ", prefixes[fieldName], address)
+	for _, derived := range addresses.Derived {
+		fmt.Fprintf(out, "%s %s ", padLabel("- "+derived.Label), derived.Address)
 
 		if balance {
-			amount, value, extra, err := internal.CheckBalance(address, defaultCurrency)
+			amount, value, extra, err := internal.CheckBalance(network, derived.Address, defaultCurrency)
 			if err != nil {
 				// skip price/value print
 			} else {
@@ -275,3 +696,131 @@ func prettyPrintAddresses(out io.Writer, addresses pkg.Addresses, balance bool)
 		fmt.Fprint(out, "\n")
 	}
 }
+
+// parseAddressTypeIDs returns the set of comma separated address type IDs, all for every address type
+func parseAddressTypeIDs(ids string) (map[string]bool, error) {
+	result := map[string]bool{}
+	if ids == "" {
+		return result, nil
+	}
+
+	known := map[string]bool{}
+	for _, addressType := range pkg.AddressTypes() {
+		known[addressType.ID] = true
+	}
+
+	for _, id := range strings.Split(ids, ",") {
+		id = strings.TrimSpace(id)
+
+		switch {
+		case id == "all":
+			return known, nil
+		case !known[id]:
+			return nil, fmt.Errorf("unknown address type '%s'", id)
+		}
+
+		result[id] = true
+	}
+
+	return result, nil
+}
+
+// parseQROptions returns the QR level and checks the quiet zone
+func parseQROptions(levelName string, quietZone int) (qr.Level, error) {
+	if quietZone < 0 {
+		return qr.L, fmt.Errorf("quiet zone %d must not be negative", quietZone)
+	}
+
+	return qr.ParseLevel(levelName)
+}
+
+// printQR prints the caption and text followed by the text drawn as a QR code with half blocks
+func printQR(out io.Writer, caption, text string, level qr.Level, quietZone int, invert bool) error {
+	code, err := qr.Encode(text, level)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "%s%s\n", padLabel(caption+":"), text)
+	fmt.Fprint(out, code.HalfBlocks(quietZone, invert))
+
+	return nil
+}
+
+func printStatement(out io.Writer, statement pkg.OpendimeStatement) {
+	buildTime := ""
+	if !statement.BuildTime.IsZero() {
+		buildTime = statement.BuildTime.Format(time.DateTime)
+	}
+
+	fmt.Fprintf(out, "Nonce: %s\n", statement.Nonce)
+	fmt.Fprintf(out, "Serial: %s\n", statement.Serial)
+	fmt.Fprintf(out, "Firmware: %s\n", statement.Firmware)
+	fmt.Fprintf(out, "Build time: %s\n", buildTime)
+	fmt.Fprintf(out, "Git ref: %s\n", statement.GitRef)
+	fmt.Fprintf(out, "Coin: %s\n", statement.Coin)
+	fmt.Fprintf(out, "Unsealed: %v\n", statement.Unsealed)
+}
+
+// discoverDevices finds mounted Opendimes. A variable so tests can use a fake mount table and directory tree
+var discoverDevices = func() ([]internal.Device, error) {
+	return internal.NewDiscoverer().Discover()
+}
+
+// deviceVerifyTxt returns the path to verify.txt on the Opendime chosen by selector (auto or an index)
+func deviceVerifyTxt(selector string) (string, error) {
+	devices, err := discoverDevices()
+	if err != nil {
+		return "", err
+	}
+
+	device, err := internal.SelectDevice(devices, selector)
+	if err != nil {
+		return "", err
+	}
+
+	return device.VerifyTxt, nil
+}
+
+// parseVerifyTxt returns the address, signature and message of the first signed message in the verify.txt, or read
+// from stdin if fn is -. Messages signed by other wallets are accepted (see pkg.Dialect)
+func parseVerifyTxt(fn string) (string, string, string, error) {
+	if fn != stdinFilename {
+		return pkg.ParseVerifyTxt(fn)
+	}
+
+	message, err := pkg.ParseSignedMessage(stdin)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return message.Address, message.Signature, message.Message, nil
+}
+
+// padLabel pads the label with tabs so the value that follows lines up at labelColumn. Labels longer than
+// labelColumn get a single tab
+func padLabel(label string) string {
+	column := labelColumn()
+
+	for width := len(label); ; {
+		label += "\t"
+		width = (width/tabWidth + 1) * tabWidth
+
+		if width >= column {
+			return label
+		}
+	}
+}
+
+// labelColumn the first tab stop after the longest registered address type label as listed eg
+// "- Bitcoin Cash P2PKH (Compressed)", so every value lines up
+func labelColumn() int {
+	const minColumn = 32
+
+	longest := 0
+	for _, addressType := range pkg.AddressTypes() {
+		longest = max(longest, len("- "+addressType.Label()))
+	}
+
+	return max(minColumn, (longest/tabWidth+1)*tabWidth)
+}
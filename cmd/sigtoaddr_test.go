@@ -2,9 +2,20 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"flag"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
+
+	"github.com/jarcoal/httpmock"
+
+	"github.com/timchurchard/opendime-utils/internal"
+	"github.com/timchurchard/opendime-utils/internal/report"
+	"github.com/timchurchard/opendime-utils/pkg"
+	"github.com/timchurchard/opendime-utils/pkg/emulator"
 )
 
 func Test_sanityTest(t *testing.T) {
@@ -13,10 +24,27 @@ func Test_sanityTest(t *testing.T) {
 	}
 }
 
+func Test_sanityTestDeviceVectors(t *testing.T) {
+	// The fixed vectors are the real Opendime exports (padded with blank lines after the armor)
+	for fn, want := range map[string]string{"../verify.txt_tips": tipsVerifyTxt, "../litecoin_verify.txt_tips": litecoinTipsVerifyTxt} {
+		contents, err := os.ReadFile(fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasPrefix(string(contents), want) {
+			t.Errorf("%s does not start with the sanity test vector", fn)
+		}
+	}
+}
+
 func Test_SigtoaddrMain(t *testing.T) {
 	const (
-		cliName                 = "sigtoaddr"
-		bitcoinValidExpectedout = "Addresses for Opendime:\t1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f\n- Bitcoin P2PKH\t\t\t 1GLfgL9yKVTRRG1D4fdKkEuEQqAE7ob1eB \n- Bitcoin P2PKH (Compressed)\t 1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f \n- Bitcoin P2WPKH\t\t bc1q7qcf63rtp20dsalcwmceucxs0kwn75l95nsxjf \n- Ethereum\t\t\t 0x5D0a9F69035Be4275204f9eBbd5cC049e42429c6 \n- Litecoin P2PKH\t\t LaZcwYToQ9hUg4hNEocd2Fxzd3XWEMFnQ5 \n- Litecoin P2PKH (Compressed)\t Lh7xg2yUmNWq668Fihx3rjLb13XkbHuBMQ \n- Litecoin P2WPKH\t\t ltc1q7qcf63rtp20dsalcwmceucxs0kwn75l9s02z2e \n- Dogecoin P2PKH\t\t DLUmDb6ccuMhxGBooFctJ14qHxtXVWYf4P \n"
+		cliName                  = "sigtoaddr"
+		testnetValidExpectedout  = "Addresses for Opendime:\tmhZoPvHi9rXdNsuxTXtMe97mWoDeZgGkBg\n- Bitcoin P2PKH\t\t\t\t moshYqftyWxQ9rRVmcYQhRnBMfjy1SoVLm \n- Bitcoin P2PKH (Compressed)\t\t mhZoPvHi9rXdNsuxTXtMe97mWoDeZgGkBg \n- Bitcoin P2SH-P2WPKH\t\t\t 2NCsxS1jA6GVvEi9G8GYbCeqF7K1M8EFU6B \n- Bitcoin P2WPKH\t\t\t tb1qzeapyvz7kl7v5vj865rahts2jjcdz0ssw72ay5 \n- Bitcoin P2TR\t\t\t\t tb1plw9wrc6uxczx80hf878gtxzhcxzyx7dpx7qs5576egra9d5gcd0shsjuam \n- Ethereum\t\t\t\t 0x148582B4F60139ce2Bc7E25e7551F31c1122B6f4 \n- Litecoin P2PKH\t\t\t moshYqftyWxQ9rRVmcYQhRnBMfjy1SoVLm \n- Litecoin P2PKH (Compressed)\t\t mhZoPvHi9rXdNsuxTXtMe97mWoDeZgGkBg \n- Litecoin P2SH-P2WPKH\t\t\t QgEiZ2bQ7NZ1NtuJkNacHMGgFhTB1svNX2 \n- Litecoin P2WPKH\t\t\t tltc1qzeapyvz7kl7v5vj865rahts2jjcdz0sshkgr5a \n- Litecoin P2TR\t\t\t\t tltc1plw9wrc6uxczx80hf878gtxzhcxzyx7dpx7qs5576egra9d5gcd0sgnwazy \n- Dogecoin P2PKH\t\t\t ncYuX4GUPst9nihfpTD3fgKkcgFrRZih1v \n- Bitcoin Cash P2PKH\t\t\t bchtest:qpd66mkjhlrgtm3pq6mz4z0cfjfw753dkq07x4m5rm \n- Bitcoin Cash P2PKH (Compressed)\t bchtest:qqt85y3st6mlej3jgl2s0kawp22tp5f7zq3sk6ueym \n- Dash P2PKH\t\t\t\t yUgC6zKFZkPosRU1UnCem4fzcJDKawWPnT \n- Dash P2PKH (Compressed)\t\t yNNHx4w4k5y36SxUAhYbhn1amRh1Byv3YP \n"
+		bip322ExpectedOut        = "Addresses for Opendime:\tbc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l\n- Bitcoin P2PKH\t\t\t\t 169ojqRJ3d4f7aNMu86nAAwGJyeykmByFU \n- Bitcoin P2PKH (Compressed)\t\t 14vV3aCHBeStb5bkenkNHbe2YAFinYdXgc \n- Bitcoin P2SH-P2WPKH\t\t\t 37qyp7jQAzqb2rCBpMvVtLDuuzKAUCVnJb \n- Bitcoin P2WPKH\t\t\t bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l \n- Bitcoin P2TR\t\t\t\t bc1ppv609nr0vr25u07u95waq5lucwfm6tde4nydujnu8npg4q75mr5sxq8lt3 \n- Ethereum\t\t\t\t 0x342d64F90864659ecD5DBDb5260534bF0f0477A3 \n- Litecoin P2PKH\t\t\t LQNm13j88HJiNP4X5G65SC12XC2Fs9hz46 \n- Litecoin P2PKH (Compressed)\t\t LP9SJnW7GJgwqtHupvjfZchnkNczsLk1nm \n- Litecoin P2SH-P2WPKH\t\t\t ME48819N87h1qMU5vEuqhyUKEgucUsEvZT \n- Litecoin P2WPKH\t\t\t ltc1q9vza2e8x573nczrlzms0wvx3gsqjx7vag5vzh0 \n- Litecoin P2TR\t\t\t\t ltc1ppv609nr0vr25u07u95waq5lucwfm6tde4nydujnu8npg4q75mr5s9yf035 \n- Dogecoin P2PKH\t\t\t DAHuH6MwM2xweaYxdi6Lhw6sC7PH997sYS \n- Bitcoin Cash P2PKH\t\t\t bitcoincash:qqug97xnfkqcp6vs5q27cfnwjxl2ajcvhy7mqkvjm7 \n- Bitcoin Cash P2PKH (Compressed)\t bitcoincash:qq4st4tyu6n6x0qg0utwpaes69zqzgmen5ga6t0vze \n- Dash P2PKH\t\t\t\t Xfqea65C1LHFGWxwm1R11hd49KEfmkyeoq \n- Dash P2PKH (Compressed)\t\t XecKsprB9MfUk2CLWg4b98KpNVqQoczrd2 \n"
+		segwitVerboseExpectedout = "Public key hex: 042509ef79a4796f752e024d7b4ba295f84397ba5aba836718b614118f3c46a54e2233647ac3d78fdd15756e282c2a845b3ef8f64ecc374cb3587aad40a038a2cf\nSignature proves: BitcoinP2WPKH\nMessage magic: Bitcoin Signed Message\nAddresses for Opendime:\tbc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqzfhzrq\n- Bitcoin P2PKH\t\t\t\t 13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu \n- Bitcoin P2PKH (Compressed)\t\t 1A9GW5SxA4qW5ZhymW1uPaj6N4vnVJVWZW \n- Bitcoin P2SH-P2WPKH\t\t\t 3FiBeABktE7t7WKWrUrX4RKNrq3dVbokdR \n- Bitcoin P2WPKH\t\t\t bc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqzfhzrq \n- Bitcoin P2TR\t\t\t\t bc1pswmpmx5nwpenu2fjdd59f8ulrhw27zf7ag8rxjxugx85033zeukqut6xuf \n- Ethereum\t\t\t\t 0xCb19D769c583599DbD7D6D78Eb3279a362672747 \n- Litecoin P2PKH\t\t\t LMrjSZiwF9FG73FTUP9pv4Tp7vtudfiMG7 \n- Litecoin P2PKH (Compressed)\t\t LUNDmHknEj5ZLNQ8we1CfbnraHJ4iPRtuG \n- Litecoin P2SH-P2WPKH\t\t\t MMvKx3biqLyJv1bQxMqrt4ZnBXe5ZETyYb \n- Litecoin P2WPKH\t\t\t ltc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqx4dxms \n- Litecoin P2TR\t\t\t\t ltc1pswmpmx5nwpenu2fjdd59f8ulrhw27zf7ag8rxjxugx85033zeukql05kxv \n- Dogecoin P2PKH\t\t\t D7msicMkTtuVPEju2qA6BoZenrFvrMCm24 \n- Bitcoin Cash P2PKH\t\t\t bitcoincash:qqwwfur42pxtqd22xx50fea3x3lsu40jds3xjspyqj \n- Bitcoin Cash P2PKH (Compressed)\t bitcoincash:qpjyjejvx5q2evqt2nu42jfq8x9vx6edsqjtne9lvq \n- Dash P2PKH\t\t\t\t XdKd1c518CDo1B9tA8UkVa5qk47KZSAgbE \n- Dash P2PKH (Compressed)\t\t Xjq7LL6r7n46EWJZdPL8F7QtCQWUczLj2P \n"
+		bitcoinValidExpectedout  = "Addresses for Opendime:\t1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f\n- Bitcoin P2PKH\t\t\t\t 1GLfgL9yKVTRRG1D4fdKkEuEQqAE7ob1eB \n- Bitcoin P2PKH (Compressed)\t\t 1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f \n- Bitcoin P2SH-P2WPKH\t\t\t 394hCajQZnpLCqmwB3pRRGVG7ZGqfDM2VA \n- Bitcoin P2WPKH\t\t\t bc1q7qcf63rtp20dsalcwmceucxs0kwn75l95nsxjf \n- Bitcoin P2TR\t\t\t\t bc1p23xp07c82m2rc9j82k5jl6tz0mvu964699pvyvvk899m5h98cgpszfwt9e \n- Ethereum\t\t\t\t 0x5D0a9F69035Be4275204f9eBbd5cC049e42429c6 \n- Litecoin P2PKH\t\t\t LaZcwYToQ9hUg4hNEocd2Fxzd3XWEMFnQ5 \n- Litecoin P2PKH (Compressed)\t\t Lh7xg2yUmNWq668Fihx3rjLb13XkbHuBMQ \n- Litecoin P2SH-P2WPKH\t\t\t MFGqWU9NWufm1M3qGvomEujfSFsHhWBe95 \n- Litecoin P2WPKH\t\t\t ltc1q7qcf63rtp20dsalcwmceucxs0kwn75l9s02z2e \n- Litecoin P2TR\t\t\t\t ltc1p23xp07c82m2rc9j82k5jl6tz0mvu964699pvyvvk899m5h98cgpspdqmlu \n- Dogecoin P2PKH\t\t\t DLUmDb6ccuMhxGBooFctJ14qHxtXVWYf4P \n- Bitcoin Cash P2PKH\t\t\t bitcoincash:qz5yyya0qyswmmjv92px0h9hahugc97adgwdlnuxf9 \n- Bitcoin Cash P2PKH (Compressed)\t bitcoincash:qrcrp82ydv9fakrhlpm0r8nq6p7e606nu5zvxd2yrw \n- Dash P2PKH\t\t\t\t Xr2WWaosHCg1aCbnvYwYbmb2FAjvD6AEz4 \n- Dash P2PKH (Compressed)\t\t XxarF5KYeRVMzE2gQTGySExcdAkAZCCjm8 \n"
 	)
 
 	// We manipulate the Args to set them up for the testcases, after this test we restore the initial args
@@ -53,6 +81,67 @@ func Test_SigtoaddrMain(t *testing.T) {
 			},
 			want:    1,
 			wantOut: "Unable to verify signature: Invalid signature address not match",
+		}, {
+			name: "valid bitcoin testnet",
+			args: []string{
+				"--network",
+				"testnet",
+				"--address",
+				"mhZoPvHi9rXdNsuxTXtMe97mWoDeZgGkBg",
+				"--signature",
+				"Hz0pDxS09fEdjKrSJenLYsz05gakT6eW9GdzKDopnlwUL3bf3mQeJcS+takCIMuavK/NLOorXWXZCqV0KBMlwgU=",
+				"--message",
+				"Hello World",
+			},
+			want:    0,
+			wantOut: testnetValidExpectedout,
+		}, {
+			name: "valid bitcoin p2wpkh verbose",
+			args: []string{
+				"-v",
+				"--address",
+				"bc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqzfhzrq",
+				"--signature",
+				"J6S9vgFjfs8bh3yySu8BNO1kGu3cPmpZmy14TkufGy0fSJLVffwbnZj6Zd0OLNKYanFn4ivn4MYhKUjjo0VHAqc=",
+				"--message",
+				"Hello World",
+			},
+			want:    0,
+			wantOut: segwitVerboseExpectedout,
+		}, {
+			name: "valid bip322 p2wpkh",
+			args: []string{
+				"--address",
+				"bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l",
+				"--signature",
+				"AkcwRAIgZRfIY3p7/DoVTty6YZbWS71bc5Vct9p9Fia83eRmw2QCICK/ENGfwLtptFluMGs2KsqoNSk89pO7F29zJLUx9a/sASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+				"--message",
+				"Hello World",
+			},
+			want:    0,
+			wantOut: bip322ExpectedOut,
+		}, {
+			name: "valid bip322 p2tr",
+			args: []string{
+				"--address",
+				"bc1ppv609nr0vr25u07u95waq5lucwfm6tde4nydujnu8npg4q75mr5sxq8lt3",
+				"--signature",
+				"AUHd69PrJQEv+oKTfZ8l+WROBHuy9HKrbFCJu7U1iK2iiEy1vMU5EfMtjc+VSHM7aU0SDbak5IUZRVno2P5mjSafAQ==",
+				"--message",
+				"Hello World",
+			},
+			want:    0,
+			wantOut: "Signature valid for bc1ppv609nr0vr25u07u95waq5lucwfm6tde4nydujnu8npg4q75mr5sxq8lt3 but it does not reveal the public key so no addresses can be derived\n",
+		}, {
+			name: "invalid network",
+			args: []string{
+				"--network",
+				"simnet",
+				"--address",
+				"mhZoPvHi9rXdNsuxTXtMe97mWoDeZgGkBg",
+			},
+			want:    1,
+			wantOut: "Invalid network: unknown network 'simnet' must be one of mainnet, testnet, signet or regtest",
 		},
 	}
 	for _, tt := range tests {
@@ -71,3 +160,443 @@ func Test_SigtoaddrMain(t *testing.T) {
 		})
 	}
 }
+
+func Test_SigtoaddrMainDevice(t *testing.T) {
+	const cliName = "sigtoaddr"
+
+	verifyTxt, err := os.ReadFile("../verify.txt_tips")
+	if err != nil {
+		t.Skip("verify.txt_tips not found")
+	}
+
+	media := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(media, "OPENDIME", "advanced"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(media, "OPENDIME", pkg.VerifyTxtPath), verifyTxt, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	oldArgs, oldDiscover := os.Args, discoverDevices
+	defer func() { os.Args, discoverDevices = oldArgs, oldDiscover }()
+
+	discoverDevices = internal.Discoverer{Roots: []string{media}}.Discover
+
+	tests := []struct {
+		name       string
+		device     string
+		wantPrefix string
+	}{
+		{"auto", "auto", "Addresses for Opendime:\t1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR\n"},
+		{"index", "0", "Addresses for Opendime:\t1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR\n"},
+		{"out of range", "1", "Unable to find Opendime: device index 1 out of range, found 1 Opendimes"},
+	}
+	for _, tt := range tests {
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = []string{cliName, "-device", tt.device}
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			SigtoaddrMain(out)
+
+			if gotOut := out.String(); !strings.HasPrefix(gotOut, tt.wantPrefix) {
+				t.Errorf("SigtoaddrMain() = %v, want prefix %v", gotOut, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func Test_SigtoaddrMainStatement(t *testing.T) {
+	const (
+		cliName          = "sigtoaddr"
+		mismatchMessage  = "Nonce: 1675bf38ec241a2308585ad0  Serial: TESTSERIAL\r\nVersion: 2.4.0 time=20190207.130255 git=master@e233940e coin=LTC"
+		tipsVerboseStart = "Public key hex: 04f27deec87586e475f828cb3cd34d2a02a674c204875e91b90ce4ce1e8773289587979932eef0c5f76c5d5fc692db94749e4efba67b692f564190c4b36ca8763a\nSignature proves: BitcoinP2PKH\nMessage magic: Bitcoin Signed Message\nNonce: 1675bf38ec241a2308585ad0\nSerial: DDRRNOCZJRIFCIBAEBJDOJQY74\nFirmware: 2.4.0\nBuild time: 2019-02-07 13:02:55\nGit ref: master@e233940e\nCoin: BTC\nUnsealed: false\nAddresses for Opendime:\t1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR\n"
+		mismatchStart    = "Warning: statement says coin=LTC but 1A9GW5SxA4qW5ZhymW1uPaj6N4vnVJVWZW is a Bitcoin mainnet address\nAddresses for Opendime:\t1A9GW5SxA4qW5ZhymW1uPaj6N4vnVJVWZW\n"
+	)
+
+	address, signature, err := pkg.SignMessage(pkg.MainNet, pkg.Bitcoin, "L4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ", mismatchMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantPrefix string
+	}{
+		{"verbose statement", []string{"-verifytxt", "../verify.txt_tips", "-v"}, tipsVerboseStart},
+		{"coin mismatch", []string{"-a", address, "-s", signature, "-m", mismatchMessage}, mismatchStart},
+	}
+	for _, tt := range tests {
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = append([]string{cliName}, tt.args...)
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			SigtoaddrMain(out)
+
+			if gotOut := out.String(); !strings.HasPrefix(gotOut, tt.wantPrefix) {
+				t.Errorf("SigtoaddrMain() = %q, want prefix %q", gotOut, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func Test_SigtoaddrMainVerdict(t *testing.T) {
+	const cliName = "sigtoaddr"
+
+	dir, clone := t.TempDir(), t.TempDir()
+	if _, err := emulator.Create(dir, emulator.Options{Serial: "TESTSERIAL"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := emulator.Create(clone, emulator.Options{Serial: "TESTSERIAL"}); err != nil {
+		t.Fatal(err)
+	}
+
+	historyFn := filepath.Join(t.TempDir(), "history.json")
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	tests := []struct {
+		name        string
+		verifyTxt   string
+		wantVerdict string
+		wantExit    int
+	}{
+		{"pass", dir, "Verdict:\t\t\t\tPASS\n", 0},
+		{"replay", dir, "Verdict:\t\t\t\tWARN\n- nonce ", 0},
+		{"clone", clone, "Verdict:\t\t\t\tFAIL\n- serial TESTSERIAL was seen with a different key ", 1},
+	}
+	for _, tt := range tests {
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = []string{cliName, "-verifytxt", filepath.Join(tt.verifyTxt, pkg.VerifyTxtPath), "-verdict", "-history", historyFn}
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			exit := SigtoaddrMain(out)
+
+			gotOut := out.String()
+			if _, verdict, _ := strings.Cut(gotOut, "\nVerdict:"); !strings.HasPrefix("Verdict:"+verdict, tt.wantVerdict) || exit != tt.wantExit {
+				t.Errorf("SigtoaddrMain() = %d %q, want %d verdict %q", exit, gotOut, tt.wantExit, tt.wantVerdict)
+			}
+		})
+	}
+}
+
+func Test_SigtoaddrMainSpent(t *testing.T) {
+	const (
+		cliName          = "sigtoaddr"
+		mempoolFreshResp = `{"address":"1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR","chain_stats":{"funded_txo_count":1,"funded_txo_sum":100000,"spent_txo_count":0,"spent_txo_sum":0,"tx_count":1},"mempool_stats":{"funded_txo_count":0,"funded_txo_sum":0,"spent_txo_count":0,"spent_txo_sum":0,"tx_count":0}}`
+		mempoolSpentResp = `{"address":"bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5","chain_stats":{"funded_txo_count":1,"funded_txo_sum":100000,"spent_txo_count":1,"spent_txo_sum":100000,"tx_count":2},"mempool_stats":{"funded_txo_count":0,"funded_txo_sum":0,"spent_txo_count":0,"spent_txo_sum":0,"tx_count":0}}`
+	)
+
+	if _, err := os.Stat("../verify.txt_tips"); err != nil {
+		t.Skip("verify.txt_tips not found")
+	}
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "https://mempool.space/api/address/1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR",
+		httpmock.NewStringResponder(200, mempoolFreshResp))
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	historyFn := filepath.Join(t.TempDir(), "history.json")
+
+	tests := []struct {
+		name        string
+		format      string
+		p2wpkhSpent bool
+		want        []string
+		wantExit    int
+	}{
+		{"fresh", "text", false, []string{"Spend history:\n", "- Bitcoin P2PKH\t\t\t\t 1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR never spent\n",
+			"No spends found but 15 address(es) could not be checked\n", "Verdict:\t\t\t\tWARN\n",
+			"- 15 derived address(es) could not be checked for spends\n"}, 0},
+		{"fresh json", "json", false, []string{`"spends": 0`, `"level": "WARN"`,
+			`"15 derived address(es) could not be checked for spends"`}, 0},
+		{"spent", "text", true, []string{"- Bitcoin P2WPKH\t\t\t bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5 SPENT 1\n",
+			"WARNING! NOT FRESH! 1 address(es) have sent coins", "Verdict:\t\t\t\tFAIL\n- nonce ",
+			"- 1 derived address(es) have sent coins, the private key has been exposed\n"}, 1},
+	}
+	for _, tt := range tests {
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = []string{cliName, "-verifytxt", "../verify.txt_tips", "-spent", "-verdict", "-history", historyFn, "-format", tt.format}
+
+		if tt.p2wpkhSpent {
+			httpmock.RegisterResponder("GET", "https://mempool.space/api/address/bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5",
+				httpmock.NewStringResponder(200, mempoolSpentResp))
+		}
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			exit := SigtoaddrMain(out)
+
+			gotOut := out.String()
+			for _, want := range tt.want {
+				if !strings.Contains(gotOut, want) {
+					t.Errorf("SigtoaddrMain() = %q, want %q", gotOut, want)
+				}
+			}
+
+			if exit != tt.wantExit {
+				t.Errorf("SigtoaddrMain() exit = %d, want %d", exit, tt.wantExit)
+			}
+		})
+	}
+}
+
+func Test_SigtoaddrMainQR(t *testing.T) {
+	const cliName = "sigtoaddr"
+
+	if _, err := os.Stat("../verify.txt_tips"); err != nil {
+		t.Skip("verify.txt_tips not found")
+	}
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	tests := []struct {
+		name     string
+		args     []string
+		want     []string
+		wantQRs  int
+		wantExit int
+	}{
+		{"one", []string{"-qr", "BitcoinP2WPKH"}, []string{"Bitcoin P2WPKH:\t\t\t\tbc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5\n" + strings.Repeat("█", 37) + "\n"}, 1, 0},
+		{"two inverted", []string{"-qr", "BitcoinP2PKH,Ethereum", "-qr-quiet", "1", "-qr-invert"},
+			[]string{"Bitcoin P2PKH:\t\t\t\t1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR\n ▄▄▄▄▄▄▄ ", "Ethereum:\t\t\t\t0x76270d9D9afC0cf4EbfFBafE6401E01cb0F021Ce\n"}, 2, 0},
+		{"all", []string{"-qr", "all"}, []string{"Dash P2PKH (Compressed):\t\tX"}, 16, 0},
+		{"unknown type", []string{"-qr", "BitcoinP2WPKH,Foo"}, []string{"Invalid QR options: unknown address type 'Foo'"}, 0, 1},
+		{"bad level", []string{"-qr", "all", "-qr-level", "Z"}, []string{"Invalid QR options: QR level must be L, M, Q or H not 'Z'"}, 0, 1},
+		{"bad quiet zone", []string{"-qr", "all", "-qr-quiet", "-1"}, []string{"Invalid QR options: quiet zone -1 must not be negative"}, 0, 1},
+	}
+	for _, tt := range tests {
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = append([]string{cliName, "-verifytxt", "../verify.txt_tips"}, tt.args...)
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			exit := SigtoaddrMain(out)
+
+			gotOut := out.String()
+			for _, want := range tt.want {
+				if !strings.Contains(gotOut, want) {
+					t.Errorf("SigtoaddrMain() = %q, want %q", gotOut, want)
+				}
+			}
+
+			// Every QR code has an odd height so ends with a line of only the top half of the quiet zone
+			if gotQRs := len(regexp.MustCompile(`(?m)^(▀+| +)$`).FindAllString(gotOut, -1)); gotQRs != tt.wantQRs {
+				t.Errorf("SigtoaddrMain() QR codes = %d, want %d", gotQRs, tt.wantQRs)
+			}
+
+			if exit != tt.wantExit {
+				t.Errorf("SigtoaddrMain() exit = %d, want %d", exit, tt.wantExit)
+			}
+		})
+	}
+}
+
+func Test_SigtoaddrMainFormat(t *testing.T) {
+	const cliName = "sigtoaddr"
+
+	if _, err := os.Stat("../verify.txt_tips"); err != nil {
+		t.Skip("verify.txt_tips not found")
+	}
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	historyFn := filepath.Join(t.TempDir(), "history.json")
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantExit int
+		wantOut  string
+	}{
+		{"csv", []string{"-verifytxt", "../verify.txt_tips", "-format", "csv"}, 0,
+			"opendime,id,coin,type,label,address,amount,value,currency,spends,verdict\n" +
+				"1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR,BitcoinP2PKH,Bitcoin,P2PKH,Bitcoin P2PKH,1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR,,,,,\n"},
+		{"not found json", []string{"-verifytxt", "../nope", "-format", "json"}, 1, "{\n  \"error\": \"File '../nope' not found\"\n}\n"},
+		{"usage csv", []string{"-format", "csv"}, 1, "error\n\"Missing arguments, run with -h for usage\"\n"},
+		{"qr yaml", []string{"-verifytxt", "../verify.txt_tips", "-qr", "all", "-format", "yaml"}, 1,
+			"error: \"Invalid QR options: QR codes can only be drawn with -format text\"\n"},
+		{"unknown format", []string{"-verifytxt", "../verify.txt_tips", "-format", "xml"}, 1,
+			"Invalid format: unknown format 'xml' must be text, json, csv or yaml"},
+	}
+	for _, tt := range tests {
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = append([]string{cliName}, tt.args...)
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+
+			if exit := SigtoaddrMain(out); exit != tt.wantExit {
+				t.Errorf("SigtoaddrMain() exit = %d, want %d", exit, tt.wantExit)
+			}
+
+			if gotOut := out.String(); !strings.HasPrefix(gotOut, tt.wantOut) {
+				t.Errorf("SigtoaddrMain() = %q, want %q", gotOut, tt.wantOut)
+			}
+		})
+	}
+
+	// json with the statement and verdict
+	flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+	os.Args = []string{cliName, "-verifytxt", "../verify.txt_tips", "-format", "json", "-verdict", "-history", historyFn}
+
+	out := &bytes.Buffer{}
+	if exit := SigtoaddrMain(out); exit != 0 {
+		t.Errorf("SigtoaddrMain() exit = %d, want 0", exit)
+	}
+
+	var result report.Sigtoaddr
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("SigtoaddrMain() = %s, %v", out.String(), err)
+	}
+
+	if result.Verified.AddressType != "BitcoinP2PKH" || result.Statement == nil || result.Statement.Serial != "DDRRNOCZJRIFCIBAEBJDOJQY74" ||
+		result.PublicKeyCompressed != "02f27deec87586e475f828cb3cd34d2a02a674c204875e91b90ce4ce1e87732895" || len(result.Addresses) != 16 ||
+		result.Addresses[3].Address != "bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5" || result.Verdict == nil || result.Verdict.Level != "PASS" {
+		t.Errorf("SigtoaddrMain() = %+v", result)
+	}
+}
+
+func Test_SigtoaddrMainStdin(t *testing.T) {
+	const cliName = "sigtoaddr"
+
+	oldArgs, oldStdin := os.Args, stdin
+	defer func() { os.Args, stdin = oldArgs, oldStdin }()
+
+	// Pasted from Sparrow, the labelled dialect signed with LF line endings
+	stdin = strings.NewReader("Address: 133r6sCjLq6NbmSLjxuypDuSeocwenu1Qg\nMessage: Sparrow signed\nthis message\n" +
+		"Signature: HwqRBmbxnn/Kdc9Y1qxX1PWTPEGi1fV7hRtJgG6IvPtWGQ1KvbkgxHAq5li39y9tXBqLUcvRhqvH7DgQ/BnDWq8=\n")
+
+	flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+	os.Args = []string{cliName, "-verifytxt", "-"}
+
+	out := &bytes.Buffer{}
+	if exit := SigtoaddrMain(out); exit != 0 {
+		t.Fatalf("SigtoaddrMain() exit = %d, out %s", exit, out.String())
+	}
+
+	if !strings.Contains(out.String(), "bc1qzeapyvz7kl7v5vj865rahts2jjcdz0ssyc3wl8") {
+		t.Errorf("SigtoaddrMain() = %s", out.String())
+	}
+
+	// Two pasted signed messages are refused rather than only the first being verified
+	verifyTxt, err := os.ReadFile("../verify.txt_tips")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdin = strings.NewReader(string(verifyTxt) + string(verifyTxt))
+
+	flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+	os.Args = []string{cliName, "-verifytxt", "-"}
+
+	out = &bytes.Buffer{}
+	if exit := SigtoaddrMain(out); exit != 1 || !strings.HasSuffix(out.String(), "more than one signed message, expected one") {
+		t.Errorf("SigtoaddrMain() two messages = %d %s", exit, out.String())
+	}
+}
+
+func Test_SigtoaddrMainAnyMagic(t *testing.T) {
+	const (
+		cliName = "sigtoaddr"
+		message = "Signed by a Dogecoin wallet"
+	)
+
+	// A Bitcoin address signed with the Dogecoin magic only verifies with -any-magic
+	address := "13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu"
+	_, signature, err := pkg.SignMessage(pkg.MainNet, pkg.Dogecoin, "5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC", message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantExit int
+		wantOut  string
+	}{
+		{"chain magic", []string{"-a", address, "-s", signature, "-m", message}, 1, "Unable to verify signature: Invalid signature address not match"},
+		{"any magic", []string{"-v", "-any-magic", "-a", address, "-s", signature, "-m", message}, 0,
+			"Signature proves: BitcoinP2PKH\nMessage magic: Dogecoin Signed Message\n"},
+		{"any magic without verbose", []string{"-any-magic", "-a", address, "-s", signature, "-m", message}, 0,
+			"Message magic: Dogecoin Signed Message\n"},
+	}
+	for _, tt := range tests {
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = append([]string{cliName}, tt.args...)
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+
+			if exit := SigtoaddrMain(out); exit != tt.wantExit {
+				t.Errorf("SigtoaddrMain() exit = %d, want %d", exit, tt.wantExit)
+			}
+
+			if gotOut := out.String(); !strings.Contains(gotOut, tt.wantOut) {
+				t.Errorf("SigtoaddrMain() = %q, want %q", gotOut, tt.wantOut)
+			}
+		})
+	}
+}
+
+func Test_SigtoaddrMainEthereum(t *testing.T) {
+	const (
+		cliName   = "sigtoaddr"
+		address   = "0xCb19D769c583599DbD7D6D78Eb3279a362672747"
+		signature = "0x4dcbefe707f377a2ae1463dbbd02637c706f2a7d080bd8e8d023d51c1a95a47d762f227bdcda89a3e6090073437e8f4beeb74781eb0605fb6ae9bf53378de8701b"
+	)
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantExit int
+		wantOut  []string
+	}{
+		{"personal_sign", []string{"-v", "-a", address, "-s", signature, "-m", "Hello World"}, 0, []string{
+			"Signature proves: Ethereum\nMessage magic: Ethereum Signed Message\n",
+			"Addresses for Opendime:\t" + address + "\n- Bitcoin P2PKH\t\t\t\t 13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu \n",
+			"- Litecoin P2WPKH\t\t\t ltc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqx4dxms \n",
+			"- Dogecoin P2PKH\t\t\t D7msicMkTtuVPEju2qA6BoZenrFvrMCm24 \n",
+		}},
+		{"wrong message", []string{"-a", address, "-s", signature, "-m", "Hello World!"}, 1, []string{
+			"Unable to verify signature: Invalid signature address not match",
+		}},
+	}
+	for _, tt := range tests {
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = append([]string{cliName}, tt.args...)
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+
+			if exit := SigtoaddrMain(out); exit != tt.wantExit {
+				t.Errorf("SigtoaddrMain() exit = %d, want %d", exit, tt.wantExit)
+			}
+
+			for _, want := range tt.wantOut {
+				if gotOut := out.String(); !strings.Contains(gotOut, want) {
+					t.Errorf("SigtoaddrMain() = %q, want %q", gotOut, want)
+				}
+			}
+		})
+	}
+}
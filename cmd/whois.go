@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/timchurchard/opendime-utils/internal/inventory"
+	"github.com/timchurchard/opendime-utils/internal/report"
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// stringsFlag a flag that can be given more than once
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// WhoisMain entrypoint for the whois command
+func WhoisMain(out io.Writer) int {
+	const (
+		defaultEmpty   = ""
+		usageStore     = "Path to the inventory file (default $OPENDIME_INVENTORY or inventory.json in the user config dir)"
+		usageVerifyTxt = "Path to a verify.txt to search as well as the inventory, can be given more than once"
+		usageNetwork   = "Network of the verify.txt files: mainnet, testnet, signet or regtest"
+		usageFormat    = "Output format: text, json, csv or yaml"
+	)
+	var (
+		storeFn      string
+		verifyTxtFns stringsFlag
+		networkName  string
+		formatName   string
+	)
+
+	flag.StringVar(&storeFn, "store", defaultEmpty, usageStore)
+
+	flag.Var(&verifyTxtFns, "verifytxt", usageVerifyTxt)
+
+	flag.StringVar(&networkName, "network", string(pkg.MainNet), usageNetwork)
+	flag.StringVar(&networkName, "n", string(pkg.MainNet), usageNetwork+" (shorthand)")
+
+	flag.StringVar(&formatName, "format", string(report.Text), usageFormat)
+
+	flag.Usage = func() {
+		fmt.Fprintf(out, "Usage of %s: whois [options] ADDRESS\n", os.Args[0])
+
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	format, err := report.ParseFormat(formatName)
+	if err != nil {
+		fmt.Fprintf(out, "Invalid format: %v", err)
+		return 1
+	}
+
+	network, err := pkg.ParseNetwork(networkName)
+	if err != nil {
+		return fail(out, format, "Invalid network: %v", err)
+	}
+
+	address := flag.Arg(0)
+	if address == "" {
+		return usage(out, format)
+	}
+
+	if storeFn == "" {
+		storeFn, err = inventory.DefaultPath()
+		if err != nil {
+			return fail(out, format, "Unable to find inventory: %v", err)
+		}
+	}
+
+	index := inventory.NewIndex()
+
+	records, err := inventory.Open(storeFn).List()
+	if err != nil {
+		return fail(out, format, "Unable to read inventory: %v", err)
+	}
+
+	for _, record := range records {
+		index.Add("inventory", record)
+	}
+
+	for _, verifyTxtFn := range verifyTxtFns {
+		verifyAddress, signature, message, err := pkg.ParseVerifyTxt(verifyTxtFn)
+		if errors.Is(err, os.ErrNotExist) {
+			return fail(out, format, "File '%s' not found", verifyTxtFn)
+		}
+		if err != nil {
+			return fail(out, format, "Unable to parse %s: %v", verifyTxtFn, err)
+		}
+
+		record, err := inventory.NewRecord(network, verifyAddress, signature, message, "", inventoryNow())
+		if err != nil {
+			return fail(out, format, "Unable to index %s: %v", verifyTxtFn, err)
+		}
+
+		index.Add(verifyTxtFn, record)
+	}
+
+	matches := index.Lookup(address)
+	if len(matches) == 0 {
+		return fail(out, format, "No Opendime found for %s", address)
+	}
+
+	result := report.NewWhois(address, matches)
+
+	if format != report.Text {
+		return writeReport(out, format, result)
+	}
+
+	for n, match := range result.Matches {
+		if n > 0 {
+			fmt.Fprintln(out, "")
+		}
+
+		fmt.Fprintf(out, "%s%s\n", padLabel("Address:"), match.Address)
+		fmt.Fprintf(out, "%s%s\n", padLabel("Opendime:"), match.Opendime)
+		fmt.Fprintf(out, "%s%s\n", padLabel("Label:"), match.Label)
+		fmt.Fprintf(out, "%s%s\n", padLabel("Coin:"), match.Coin)
+		fmt.Fprintf(out, "%s%s\n", padLabel("Type:"), match.Type)
+		fmt.Fprintf(out, "%s%s\n", padLabel("Source:"), match.Source)
+	}
+
+	return 0
+}
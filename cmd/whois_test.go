@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_WhoisMain(t *testing.T) {
+	const (
+		cliName       = "whois"
+		tipsP2WPKHOut = "Address:\t\t\t\tltc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72ymrkmy\nOpendime:\t\t\t\tDDRRNOCZJRIFCIBAEBJDOJQY74\nLabel:\t\t\t\t\t\nCoin:\t\t\t\t\tLitecoin\nType:\t\t\t\t\tLitecoin P2WPKH\nSource:\t\t\t\t\t../verify.txt_tips\n"
+	)
+
+	if _, err := os.Stat("../verify.txt_tips"); err != nil {
+		t.Skip("verify.txt_tips not found")
+	}
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	storeFn := filepath.Join(t.TempDir(), "inventory.json")
+	emptyStoreFn := filepath.Join(t.TempDir(), "empty.json")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    int
+		wantOut string
+	}{
+		{
+			name:    "verify.txt files",
+			args:    []string{"-store", emptyStoreFn, "-verifytxt", "../litecoin_verify.txt_tips", "-verifytxt", "../verify.txt_tips", "LTC1QPJTAGGFHSNHKCYG967K3JMSXTM5HZG72YMRKMY"},
+			want:    0,
+			wantOut: tipsP2WPKHOut,
+		}, {
+			name:    "inventory and verify.txt report once",
+			args:    []string{"-verifytxt", "../verify.txt_tips", "bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5"},
+			want:    0,
+			wantOut: "Address:\t\t\t\tbc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5\nOpendime:\t\t\t\tDDRRNOCZJRIFCIBAEBJDOJQY74\nLabel:\t\t\t\t\ttips\nCoin:\t\t\t\t\tBitcoin\nType:\t\t\t\t\tBitcoin P2WPKH\nSource:\t\t\t\t\tinventory\n",
+		}, {
+			name:    "inventory",
+			args:    []string{"DRumZuvFchi4EjMKUpA4CTTR5a1kpHqQXH"},
+			want:    0,
+			wantOut: "Address:\t\t\t\tDRumZuvFchi4EjMKUpA4CTTR5a1kpHqQXH\nOpendime:\t\t\t\tDDRRNOCZJRIFCIBAEBJDOJQY74\nLabel:\t\t\t\t\ttips\nCoin:\t\t\t\t\tDogecoin\nType:\t\t\t\t\tDogecoin P2PKH\nSource:\t\t\t\t\tinventory\n",
+		}, {
+			name:    "not found",
+			args:    []string{"-verifytxt", "../litecoin_verify.txt_tips", "1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f"},
+			want:    1,
+			wantOut: "No Opendime found for 1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f",
+		}, {
+			name:    "missing verify.txt",
+			args:    []string{"-verifytxt", "../missing.txt", "1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f"},
+			want:    1,
+			wantOut: "File '../missing.txt' not found",
+		}, {
+			name:    "inventory csv",
+			args:    []string{"-format", "csv", "DRumZuvFchi4EjMKUpA4CTTR5a1kpHqQXH"},
+			want:    0,
+			wantOut: "address,opendime,label,coin,type,source\nDRumZuvFchi4EjMKUpA4CTTR5a1kpHqQXH,DDRRNOCZJRIFCIBAEBJDOJQY74,tips,Dogecoin,Dogecoin P2PKH,inventory\n",
+		}, {
+			name:    "not found json",
+			args:    []string{"-format", "json", "1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f"},
+			want:    1,
+			wantOut: "{\n  \"error\": \"No Opendime found for 1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f\"\n}\n",
+		},
+	}
+
+	// The inventory case needs the tips Opendime added first
+	flag.CommandLine = flag.NewFlagSet("inventory", flag.ExitOnError)
+	os.Args = []string{"inventory", "add", "-store", storeFn, "-verifytxt", "../verify.txt_tips", "-l", "tips"}
+	if got := InventoryMain(&bytes.Buffer{}); got != 0 {
+		t.Fatalf("InventoryMain() = %v", got)
+	}
+
+	for _, tt := range tests {
+		// reset flags else panic
+		flag.CommandLine = flag.NewFlagSet(cliName, flag.ExitOnError)
+		os.Args = append([]string{cliName, "-store", storeFn}, tt.args...)
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if got := WhoisMain(out); got != tt.want {
+				t.Errorf("WhoisMain() = %v, want %v", got, tt.want)
+			}
+
+			if gotOut := out.String(); gotOut != tt.wantOut {
+				t.Errorf("WhoisMain() = %q, want %q", gotOut, tt.wantOut)
+			}
+		})
+	}
+}
@@ -10,11 +10,13 @@ require (
 	github.com/ecies/go/v2 v2.0.11
 	github.com/ethereum/go-ethereum v1.16.5
 	github.com/jarcoal/httpmock v1.4.1
+	golang.org/x/sys v0.36.0
 )
 
 require (
+	github.com/btcsuite/btclog v0.0.0-20170628155309-84c8d2346e9f // indirect
+	github.com/decred/dcrd/crypto/blake256 v1.1.0 // indirect
 	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.0 // indirect
 	github.com/holiman/uint256 v1.3.2 // indirect
 	golang.org/x/crypto v0.37.0 // indirect
-	golang.org/x/sys v0.36.0 // indirect
 )
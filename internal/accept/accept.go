@@ -0,0 +1,228 @@
+// Package accept checks a batch of Opendimes offered as payment: every signature is verified, the statement is
+// checked against the history for replays and clones, the spend history is checked and the balances are summed
+package accept
+
+import (
+	"sync"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/internal"
+	"github.com/timchurchard/opendime-utils/internal/history"
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// DefaultJobs Opendimes checked at once. Kept low as the balance APIs are free and rate limited
+const DefaultJobs = 2
+
+// throttle pause after each balance or spend request. A variable so tests can skip it
+var throttle = time.Second / 3
+
+// Options for Check
+type Options struct {
+	Network pkg.Network
+	// Fiat currency for the value eg usd
+	Fiat    string
+	History *history.History
+	// Spent also checks the spend history of every derived address
+	Spent bool
+	// Jobs Opendimes checked at once, DefaultJobs if zero
+	Jobs int
+	Now  time.Time
+}
+
+// CoinBalance the balance of every derived address of one coin
+type CoinBalance struct {
+	Coin   string
+	Amount float64
+	Value  float64
+}
+
+// Result of checking one Opendime
+type Result struct {
+	VerifyTxt string
+	Address   string
+	Serial    string
+	Verdict   history.Verdict
+	// Balances by coin in registry order, coins with no balance are left out
+	Balances []CoinBalance
+	Value    float64
+	// Unchecked derived addresses whose balance could not be checked eg no public API for the coin
+	Unchecked int
+	// SpendUnchecked derived addresses whose spend history could not be checked (only with Options.Spent)
+	SpendUnchecked int
+}
+
+// Accepted the Opendime did not fail any check
+func (r Result) Accepted() bool {
+	return r.Verdict.Level != history.Fail
+}
+
+// Receipt the results in the order the verify.txt files were given
+type Receipt struct {
+	Results []Result
+	// Value of the accepted Opendimes
+	Value  float64
+	Failed int
+}
+
+// Check checks every verify.txt. Parsing, verifying and the history check run one at a time in the order given, so
+// the first Opendime seen with a serial owns it and a later one with another key is the clone. Only the balance and
+// spend lookups run options.Jobs at a time
+func Check(verifyTxts []string, options Options) Receipt {
+	jobs := options.Jobs
+	if jobs < 1 {
+		jobs = DefaultJobs
+	}
+
+	receipt := Receipt{Results: make([]Result, len(verifyTxts))}
+
+	var (
+		wg        sync.WaitGroup
+		semaphore = make(chan struct{}, jobs)
+	)
+
+	for idx, verifyTxt := range verifyTxts {
+		result, addresses, ok := verify(verifyTxt, options)
+		receipt.Results[idx] = result
+
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			receipt.Results[idx].lookup(addresses, options)
+		}()
+	}
+
+	wg.Wait()
+
+	for _, result := range receipt.Results {
+		if !result.Accepted() {
+			receipt.Failed++
+			continue
+		}
+
+		receipt.Value += result.Value
+	}
+
+	return receipt
+}
+
+// verify parses and verifies the verify.txt and checks it against the history. Returns false if there are no
+// addresses to look up
+func verify(verifyTxt string, options Options) (Result, pkg.Addresses, bool) {
+	result := Result{VerifyTxt: verifyTxt}
+
+	address, signature, message, err := pkg.ParseVerifyTxt(verifyTxt)
+	if err != nil {
+		result.Verdict.Raise(history.Fail, "unable to parse verify.txt: %v", err)
+		return result, pkg.Addresses{}, false
+	}
+
+	result.Address = address
+
+	verifiedMessage, err := pkg.VerifyMessage(options.Network, address, signature, message)
+	if err != nil {
+		result.Verdict.Raise(history.Fail, "unable to verify signature: %v", err)
+		return result, pkg.Addresses{}, false
+	}
+
+	statement, err := pkg.ParseStatement(message)
+	if err == nil {
+		result.Serial = statement.Serial
+
+		if err := statement.CoinMismatch(verifiedMessage); err != nil {
+			result.Verdict.Raise(history.Warn, "%v", err)
+		}
+	}
+
+	verdict, err := options.History.Check(verifiedMessage, message, options.Now)
+	if err != nil {
+		result.Verdict.Raise(history.Fail, "unable to check history: %v", err)
+		return result, pkg.Addresses{}, false
+	}
+
+	result.Verdict.Merge(verdict)
+
+	if verifiedMessage.PublicKeyHex == "" {
+		return result, pkg.Addresses{}, false
+	}
+
+	addresses, err := pkg.GetAddresses(verifiedMessage)
+	if err != nil {
+		result.Verdict.Raise(history.Fail, "unable to make addresses: %v", err)
+		return result, pkg.Addresses{}, false
+	}
+
+	return result, addresses, true
+}
+
+// lookup adds the balance and (with options.Spent) the spend history of every derived address. Addresses that could
+// not be checked raise the verdict to WARN, the Opendime may hold more (or have sent coins) than the receipt shows
+func (r *Result) lookup(addresses pkg.Addresses, options Options) {
+	spentAddresses := 0
+
+	for _, derived := range addresses.Derived {
+		amount, value, _, err := internal.CheckBalance(options.Network, derived.Address, options.Fiat)
+		if err != nil {
+			r.Unchecked++
+		} else {
+			r.addBalance(derived.Coin, amount, value)
+			time.Sleep(throttle)
+		}
+
+		if !options.Spent {
+			continue
+		}
+
+		spends, err := internal.CheckSpent(options.Network, derived.Address)
+		if err != nil {
+			r.SpendUnchecked++
+			continue
+		}
+
+		if spends > 0 {
+			spentAddresses++
+		}
+
+		time.Sleep(throttle)
+	}
+
+	if spentAddresses > 0 {
+		r.Verdict.Raise(history.Fail, "%d derived address(es) have sent coins, the private key has been exposed", spentAddresses)
+	}
+
+	if r.SpendUnchecked > 0 {
+		r.Verdict.Raise(history.Warn, "%d derived address(es) could not be checked for spends", r.SpendUnchecked)
+	}
+
+	if r.Unchecked > 0 {
+		r.Verdict.Raise(history.Warn, "%d derived address(es) balance could not be checked, the value may be higher", r.Unchecked)
+	}
+}
+
+func (r *Result) addBalance(coin string, amount, value float64) {
+	r.Value += value
+
+	if amount == 0 {
+		return
+	}
+
+	for idx := range r.Balances {
+		if r.Balances[idx].Coin == coin {
+			r.Balances[idx].Amount += amount
+			r.Balances[idx].Value += value
+
+			return
+		}
+	}
+
+	r.Balances = append(r.Balances, CoinBalance{Coin: coin, Amount: amount, Value: value})
+}
@@ -0,0 +1,103 @@
+package accept
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+
+	"github.com/timchurchard/opendime-utils/internal/emulatortest"
+	"github.com/timchurchard/opendime-utils/internal/history"
+	"github.com/timchurchard/opendime-utils/pkg"
+	"github.com/timchurchard/opendime-utils/pkg/emulator"
+)
+
+const (
+	mempoolResp  = `{"chain_stats":{"funded_txo_count":1,"funded_txo_sum":100000,"spent_txo_count":%d,"spent_txo_sum":0,"tx_count":1},"mempool_stats":{}}`
+	coindeskResp = `{"bpi":{"USD":{"code":"USD","rate_float":20000}}}`
+)
+
+// activateMocks every Bitcoin address holds 0.001 BTC at $20000, other coins have no balance API. Addresses in
+// spent have sent coins
+func activateMocks(spent ...string) {
+	httpmock.Activate()
+
+	httpmock.RegisterRegexpResponder("GET", regexp.MustCompile(`^https://mempool\.space/api/address/`),
+		httpmock.NewStringResponder(200, fmt.Sprintf(mempoolResp, 0)))
+	httpmock.RegisterResponder("GET", "https://api.coindesk.com/v1/bpi/currentprice.json",
+		httpmock.NewStringResponder(200, coindeskResp))
+
+	for _, address := range spent {
+		httpmock.RegisterResponder("GET", "https://mempool.space/api/address/"+address,
+			httpmock.NewStringResponder(200, fmt.Sprintf(mempoolResp, 1)))
+	}
+}
+
+func TestCheck(t *testing.T) {
+	oldThrottle := throttle
+	defer func() { throttle = oldThrottle }()
+	throttle = 0
+
+	genuine := emulatortest.NewVerified(t, emulator.Options{Key: emulatortest.TestWif, Serial: "TESTSERIAL"})
+	clone := emulatortest.NewVerified(t, emulator.Options{Serial: "TESTSERIAL"})
+	unknown := emulatortest.NewVerified(t, emulator.Options{Firmware: "9.9.9"})
+	spent := emulatortest.NewVerified(t, emulator.Options{})
+
+	activateMocks(spent.Address)
+	defer httpmock.DeactivateAndReset()
+
+	verifyTxts := []string{genuine.VerifyTxt, clone.VerifyTxt, unknown.VerifyTxt, spent.VerifyTxt, filepath.Join(t.TempDir(), "missing.txt")}
+
+	receipt := Check(verifyTxts, Options{
+		Network: pkg.MainNet,
+		Fiat:    "usd",
+		History: history.Open(filepath.Join(t.TempDir(), "history.json")),
+		Spent:   true,
+		Jobs:    len(verifyTxts), // the clone is still the second Opendime seen however the lookups are scheduled
+		Now:     time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	})
+
+	tests := []struct {
+		name       string
+		want       history.Level
+		wantReason string
+		wantValue  float64
+	}{
+		{"genuine", history.Warn, "11 derived address(es) could not be checked for spends", 100},
+		{"clone", history.Fail, "serial TESTSERIAL was seen with a different key", 100},
+		{"unknown firmware", history.Warn, "firmware version '9.9.9' is not a known Opendime version", 100},
+		{"spent", history.Fail, "1 derived address(es) have sent coins", 100},
+		{"missing", history.Fail, "unable to parse verify.txt", 0},
+	}
+	for idx, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := receipt.Results[idx]
+
+			if result.VerifyTxt != verifyTxts[idx] || result.Verdict.Level != tt.want {
+				t.Errorf("Check() = %s %s %v, want %s", result.VerifyTxt, result.Verdict.Level, result.Verdict.Reasons, tt.want)
+			}
+
+			if tt.wantReason != "" && !strings.HasPrefix(strings.Join(result.Verdict.Reasons, "\n"), tt.wantReason) {
+				t.Errorf("Check() reasons = %v, want %s", result.Verdict.Reasons, tt.wantReason)
+			}
+
+			if fmt.Sprintf("%.02f", result.Value) != fmt.Sprintf("%.02f", tt.wantValue) {
+				t.Errorf("Check() value = %f, want %f", result.Value, tt.wantValue)
+			}
+		})
+	}
+
+	if receipt.Results[0].Address != emulatortest.TestAddress || len(receipt.Results[0].Balances) != 1 || receipt.Results[0].Balances[0].Coin != "Bitcoin" ||
+		fmt.Sprintf("%.08f", receipt.Results[0].Balances[0].Amount) != "0.00500000" || receipt.Results[0].Unchecked != 11 ||
+		receipt.Results[0].SpendUnchecked != 11 {
+		t.Errorf("Check() genuine = %+v", receipt.Results[0])
+	}
+
+	if receipt.Failed != 3 || fmt.Sprintf("%.02f", receipt.Value) != "200.00" {
+		t.Errorf("Check() failed = %d value = %f", receipt.Failed, receipt.Value)
+	}
+}
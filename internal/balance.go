@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,46 +11,38 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/timchurchard/opendime-utils/pkg"
 )
 
 // CheckBalance Basic usage of bitlaps API
 // Using bitlaps https://developer.bitaps.com/blockchain which is free for 15 reqs in 5s currently
-func CheckBalance(address string, fiat string) (float64, float64, string, error) {
+// Test network coins have no value so only the balance is returned for them
+func CheckBalance(network pkg.Network, address string, fiat string) (float64, float64, string, error) {
 	var (
 		realBalance float64
 		realPrice   float64
 		extra       string
 	)
 
-	currency := "btc"
 	multiplier := 0.00000001
 
-	btcMatch, err := regexp.MatchString("^(1|3|bc1).", address)
+	currency, err := addressCurrency(network, address)
 	if err != nil {
 		return 0, 0, "", err
 	}
 
-	if !btcMatch {
-		ltcMatch, err := regexp.MatchString("(L|ltc1).", address)
-		if err != nil {
-			return 0, 0, "", err
-		}
+	if network != pkg.MainNet {
+		return checkTestBalance(network, currency, address, multiplier)
+	}
 
-		if ltcMatch {
-			currency = "ltc"
-		} else if strings.HasPrefix(address, "0") {
-			currency = "eth"
-			multiplier = 0.000000000000000001
-		} else if strings.HasPrefix(address, "D") {
-			currency = "doge"
-		} else {
-			return 0, 0, "", errors.New("Unrecognised address. Must be Bitcoin, Litecoin or Ethereum")
-		}
+	if currency == "eth" {
+		multiplier = 0.000000000000000001
 	}
 
 	switch currency {
 	case "btc":
-		realBalance, err = mempoolGetBitcoinBalance(address, multiplier)
+		realBalance, err = mempoolGetBitcoinBalance(network, address, multiplier)
 		if err != nil {
 			realBalance, err = bitlapsGetBalance(currency, address, multiplier)
 			if err != nil {
@@ -82,33 +75,112 @@ func CheckBalance(address string, fiat string) (float64, float64, string, error)
 	return realBalance, realPrice, extra, nil
 }
 
-func mempoolGetBitcoinBalance(address string, multiplier float64) (float64, error) {
-	type balanceResp struct {
-		Address    string `json:"address"`
-		ChainStats struct {
-			FundedTxoCount int   `json:"funded_txo_count"`
-			FundedTxoSum   int64 `json:"funded_txo_sum"`
-			SpentTxoCount  int   `json:"spent_txo_count"`
-			SpentTxoSum    int64 `json:"spent_txo_sum"`
-			TxCount        int   `json:"tx_count"`
-		} `json:"chain_stats"`
-		MempoolStats struct {
-			FundedTxoCount int `json:"funded_txo_count"`
-			FundedTxoSum   int `json:"funded_txo_sum"`
-			SpentTxoCount  int `json:"spent_txo_count"`
-			SpentTxoSum    int `json:"spent_txo_sum"`
-			TxCount        int `json:"tx_count"`
-		} `json:"mempool_stats"`
-	}
-
-	resultBytes, err := httpGet(fmt.Sprintf("https://mempool.space/api/address/%s", address))
+// addressCurrency returns the bitaps style currency (btc, ltc, eth or doge) for the address on the network
+func addressCurrency(network pkg.Network, address string) (string, error) {
+	if network != pkg.MainNet {
+		switch {
+		case strings.HasPrefix(address, "0x"):
+			return "eth", nil
+		case strings.HasPrefix(address, "tltc1"), strings.HasPrefix(address, "rltc1"), strings.HasPrefix(address, "Q"):
+			return "ltc", nil
+		}
+
+		// Note: testnet Bitcoin, Litecoin and Dogecoin share the m/n address prefix so assume Bitcoin
+		return "btc", nil
+	}
+
+	btcMatch, err := regexp.MatchString("^(1|3|bc1).", address)
 	if err != nil {
-		return 0, err
+		return "", err
+	}
+
+	if btcMatch {
+		return "btc", nil
+	}
+
+	ltcMatch, err := regexp.MatchString("^(L|M|ltc1).", address)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case ltcMatch:
+		return "ltc", nil
+	case strings.HasPrefix(address, "0"):
+		return "eth", nil
+	case strings.HasPrefix(address, "D"):
+		return "doge", nil
+	}
+
+	return "", errors.New("Unrecognised address. Must be Bitcoin, Litecoin or Ethereum")
+}
+
+// checkTestBalance returns the balance of a test network address. There are no public APIs for regtest, Ethereum
+// or Dogecoin test networks
+func checkTestBalance(network pkg.Network, currency, address string, multiplier float64) (float64, float64, string, error) {
+	var (
+		realBalance float64
+		err         error
+	)
+
+	switch {
+	case network == pkg.RegTest:
+		return 0, 0, "", errors.New("No public balance API for regtest")
+	case currency == "btc":
+		realBalance, err = mempoolGetBitcoinBalance(network, address, multiplier)
+		if err != nil && network == pkg.TestNet {
+			realBalance, err = bitlapsGetBalance(currency+"/testnet", address, multiplier)
+		}
+	case currency == "ltc" && network == pkg.TestNet:
+		realBalance, err = bitlapsGetBalance(currency+"/testnet", address, multiplier)
+	default:
+		return 0, 0, "", fmt.Errorf("No balance API for %s on %s", currency, network)
+	}
+
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	return realBalance, 0, "", nil
+}
+
+// mempoolAddress the mempool.space address stats
+type mempoolAddress struct {
+	Address    string `json:"address"`
+	ChainStats struct {
+		FundedTxoCount int   `json:"funded_txo_count"`
+		FundedTxoSum   int64 `json:"funded_txo_sum"`
+		SpentTxoCount  int   `json:"spent_txo_count"`
+		SpentTxoSum    int64 `json:"spent_txo_sum"`
+		TxCount        int   `json:"tx_count"`
+	} `json:"chain_stats"`
+	MempoolStats struct {
+		FundedTxoCount int `json:"funded_txo_count"`
+		FundedTxoSum   int `json:"funded_txo_sum"`
+		SpentTxoCount  int `json:"spent_txo_count"`
+		SpentTxoSum    int `json:"spent_txo_sum"`
+		TxCount        int `json:"tx_count"`
+	} `json:"mempool_stats"`
+}
+
+func mempoolGetAddress(network pkg.Network, address string) (mempoolAddress, error) {
+	resultBytes, err := httpGet(fmt.Sprintf("https://mempool.space%s/api/address/%s", mempoolNetworkPath(network), address))
+	if err != nil {
+		return mempoolAddress{}, err
+	}
+
+	result := mempoolAddress{}
+
+	err = json.Unmarshal(resultBytes, &result)
+	if err != nil {
+		return mempoolAddress{}, err
 	}
 
-	balance := balanceResp{}
+	return result, nil
+}
 
-	err = json.Unmarshal(resultBytes, &balance)
+func mempoolGetBitcoinBalance(network pkg.Network, address string, multiplier float64) (float64, error) {
+	balance, err := mempoolGetAddress(network, address)
 	if err != nil {
 		return 0, err
 	}
@@ -121,6 +193,18 @@ func mempoolGetBitcoinBalance(address string, multiplier float64) (float64, erro
 	return realBalance, nil
 }
 
+// mempoolNetworkPath returns the mempool.space URL path prefix for the network
+func mempoolNetworkPath(network pkg.Network) string {
+	switch network {
+	case pkg.TestNet:
+		return "/testnet"
+	case pkg.SigNet:
+		return "/signet"
+	}
+
+	return ""
+}
+
 // coindeskGetBitcoinPrice get
 func coindeskGetBitcoinPrice(fiat string, balance float64) (float64, error) {
 	type bitcoinPriceResp struct {
@@ -274,42 +358,51 @@ func ethplorerGetBalanceAndPrice(address string) (float64, float64, string, erro
 	return realBalance, realPrice, tokenListing, nil
 }
 
-func bitlapsGetBalance(currency, address string, multiplier float64) (float64, error) {
-	// blockchainState holds the balance and some transaction infos
-	type blockchainState struct {
-		Data struct {
-			Balance                  int    `json:"balance"`
-			ReceivedAmount           int    `json:"receivedAmount"`
-			ReceivedTxCount          int    `json:"receivedTxCount"`
-			SentAmount               int    `json:"sentAmount"`
-			SentTxCount              int    `json:"sentTxCount"`
-			FirstReceivedTxPointer   string `json:"firstReceivedTxPointer"`
-			FirstSentTxPointer       string `json:"firstSentTxPointer"`
-			LastTxPointer            string `json:"lastTxPointer"`
-			LargestReceivedTxAmount  int    `json:"largestReceivedTxAmount"`
-			LargestReceivedTxPointer string `json:"largestReceivedTxPointer"`
-			LargestSpentTxAmount     int    `json:"largestSpentTxAmount"`
-			LargestSpentTxPointer    string `json:"largestSpentTxPointer"`
-			ReceivedOutsCount        int    `json:"receivedOutsCount"`
-			SpentOutsCount           int    `json:"spentOutsCount"`
-			PendingReceivedAmount    int    `json:"pendingReceivedAmount"`
-			PendingSentAmount        int    `json:"pendingSentAmount"`
-			PendingReceivedTxCount   int    `json:"pendingReceivedTxCount"`
-			PendingSentTxCount       int    `json:"pendingSentTxCount"`
-			PendingReceivedOutsCount int    `json:"pendingReceivedOutsCount"`
-			PendingSpentOutsCount    int    `json:"pendingSpentOutsCount"`
-			Type                     string `json:"type"`
-		} `json:"data"`
-		Time float64 `json:"time"`
-	}
+// bitlapsAddressState holds the balance and some transaction infos
+type bitlapsAddressState struct {
+	Data struct {
+		Balance                  int    `json:"balance"`
+		ReceivedAmount           int    `json:"receivedAmount"`
+		ReceivedTxCount          int    `json:"receivedTxCount"`
+		SentAmount               int    `json:"sentAmount"`
+		SentTxCount              int    `json:"sentTxCount"`
+		FirstReceivedTxPointer   string `json:"firstReceivedTxPointer"`
+		FirstSentTxPointer       string `json:"firstSentTxPointer"`
+		LastTxPointer            string `json:"lastTxPointer"`
+		LargestReceivedTxAmount  int    `json:"largestReceivedTxAmount"`
+		LargestReceivedTxPointer string `json:"largestReceivedTxPointer"`
+		LargestSpentTxAmount     int    `json:"largestSpentTxAmount"`
+		LargestSpentTxPointer    string `json:"largestSpentTxPointer"`
+		ReceivedOutsCount        int    `json:"receivedOutsCount"`
+		SpentOutsCount           int    `json:"spentOutsCount"`
+		PendingReceivedAmount    int    `json:"pendingReceivedAmount"`
+		PendingSentAmount        int    `json:"pendingSentAmount"`
+		PendingReceivedTxCount   int    `json:"pendingReceivedTxCount"`
+		PendingSentTxCount       int    `json:"pendingSentTxCount"`
+		PendingReceivedOutsCount int    `json:"pendingReceivedOutsCount"`
+		PendingSpentOutsCount    int    `json:"pendingSpentOutsCount"`
+		Type                     string `json:"type"`
+	} `json:"data"`
+	Time float64 `json:"time"`
+}
 
+func bitlapsGetAddressState(currency, address string) (bitlapsAddressState, error) {
 	balanceBody, err := httpGet(fmt.Sprintf("https://api.bitaps.com/%s/v1/blockchain/address/state/%s", currency, address))
 	if err != nil {
-		return 0, err
+		return bitlapsAddressState{}, err
+	}
+
+	state := bitlapsAddressState{}
+	err = json.Unmarshal(balanceBody, &state)
+	if err != nil {
+		return bitlapsAddressState{}, err
 	}
 
-	balance := blockchainState{}
-	err = json.Unmarshal(balanceBody, &balance)
+	return state, nil
+}
+
+func bitlapsGetBalance(currency, address string, multiplier float64) (float64, error) {
+	balance, err := bitlapsGetAddressState(currency, address)
 	if err != nil {
 		return 0, err
 	}
@@ -398,3 +491,27 @@ func httpGet(url string) ([]byte, error) {
 
 	return io.ReadAll(res.Body)
 }
+
+func httpPost(url, contentType string, body []byte) ([]byte, error) {
+	client := http.Client{
+		Timeout: time.Second * 10,
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Body != nil {
+		defer res.Body.Close()
+	}
+
+	return io.ReadAll(res.Body)
+}
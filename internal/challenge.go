@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+var (
+	// ErrChallengeTimeout the Opendime did not sign the nonce in time
+	ErrChallengeTimeout = errors.New("timed out waiting for the Opendime to sign the challenge nonce")
+	// ErrChallengeWrongKey the nonce was signed by a key other than the Opendime's, eg a counterfeit signing with a
+	// key it controls
+	ErrChallengeWrongKey = errors.New("the challenge nonce was signed by a different key than the Opendime's")
+)
+
+// ChallengeResult a verify.txt signed with our nonce
+type ChallengeResult struct {
+	Nonce           string
+	Statement       pkg.OpendimeStatement
+	VerifiedMessage pkg.VerifiedMessage
+}
+
+// NewChallengeNonce returns a random hex nonce
+func NewChallengeNonce() (string, error) {
+	nonce := make([]byte, pkg.ChallengeNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(nonce), nil
+}
+
+// Challenge writes nonce to the Opendime mounted at dir and polls verify.txt until the Opendime has signed a
+// statement echoing it. An invalid signature fails straight away, a verify.txt that never echoes the nonce (eg a
+// copied static file) fails with ErrChallengeTimeout and a nonce signed by a key other than the address read before
+// the challenge fails with ErrChallengeWrongKey
+func Challenge(network pkg.Network, dir, nonce string, timeout, pollInterval time.Duration) (ChallengeResult, error) {
+	wantAddress, wantPublicKeyHex, err := readDeviceKey(network, dir)
+	if err != nil {
+		return ChallengeResult{}, err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, pkg.ChallengeFilePath), []byte(nonce), 0o600); err != nil {
+		return ChallengeResult{}, fmt.Errorf("unable to write challenge: %w", err)
+	}
+
+	var (
+		lastNonce string
+		deadline  = time.Now().Add(timeout)
+	)
+
+	for {
+		address, signature, message, err := pkg.ParseVerifyTxt(filepath.Join(dir, pkg.VerifyTxtPath))
+		if err == nil {
+			statement, err := pkg.ParseStatement(message)
+			if err == nil && strings.EqualFold(statement.Nonce, nonce) {
+				verifiedMessage, err := pkg.VerifyMessage(network, address, signature, message)
+				if err != nil {
+					return ChallengeResult{}, fmt.Errorf("verify.txt echoes our nonce but the signature is invalid: %w", err)
+				}
+
+				if verifiedMessage.Address != wantAddress || (wantPublicKeyHex != "" && verifiedMessage.PublicKeyHex != wantPublicKeyHex) {
+					return ChallengeResult{}, fmt.Errorf("%w (signed by %s, the Opendime address is %s)", ErrChallengeWrongKey, verifiedMessage.Address, wantAddress)
+				}
+
+				return ChallengeResult{Nonce: nonce, Statement: statement, VerifiedMessage: verifiedMessage}, nil
+			}
+
+			lastNonce = statement.Nonce
+		}
+
+		if time.Now().After(deadline) {
+			if lastNonce != "" {
+				return ChallengeResult{}, fmt.Errorf("%w (verify.txt nonce is still %s)", ErrChallengeTimeout, lastNonce)
+			}
+
+			return ChallengeResult{}, ErrChallengeTimeout
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
+
+// readDeviceKey returns the address the Opendime must sign the challenge with, from verify.txt (with its public key if
+// the signature verifies) or address.txt if there is no verify.txt. If both are present they must agree
+func readDeviceKey(network pkg.Network, dir string) (string, string, error) {
+	var addressTxt string
+
+	contents, err := os.ReadFile(filepath.Join(dir, pkg.AddressTxtName))
+	if err == nil {
+		if fields := strings.Fields(string(contents)); len(fields) > 0 {
+			addressTxt = fields[0]
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", "", fmt.Errorf("unable to read address.txt: %w", err)
+	}
+
+	address, signature, message, err := pkg.ParseVerifyTxt(filepath.Join(dir, pkg.VerifyTxtPath))
+	if err != nil {
+		if addressTxt == "" {
+			return "", "", fmt.Errorf("unable to read the Opendime address: %w", err)
+		}
+
+		return addressTxt, "", nil
+	}
+
+	if addressTxt != "" && addressTxt != address {
+		return "", "", fmt.Errorf("address.txt %s does not match verify.txt %s", addressTxt, address)
+	}
+
+	// The address alone is enough, the challenge itself checks the signature
+	verifiedMessage, err := pkg.VerifyMessage(network, address, signature, message)
+	if err != nil {
+		return address, "", nil
+	}
+
+	return address, verifiedMessage.PublicKeyHex, nil
+}
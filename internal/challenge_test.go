@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+	"github.com/timchurchard/opendime-utils/pkg/emulator"
+)
+
+func TestChallenge(t *testing.T) {
+	const nonce = "00112233445566778899aabb"
+
+	tests := []struct {
+		name     string
+		watch    bool
+		corrupt  bool
+		impostor bool
+		wantErr  string
+	}{
+		{name: "device signs nonce", watch: true},
+		{name: "other key signs nonce", impostor: true, wantErr: "the challenge nonce was signed by a different key than the Opendime's (signed by "},
+		{name: "static copy", wantErr: "timed out waiting for the Opendime to sign the challenge nonce (verify.txt nonce is still "},
+		{name: "bad signature", corrupt: true, wantErr: "verify.txt echoes our nonce but the signature is invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			device, err := emulator.Create(dir, emulator.Options{Serial: "TESTSERIAL"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.watch {
+				stop := make(chan struct{})
+				defer close(stop)
+
+				go device.Watch(stop, 5*time.Millisecond) //nolint:errcheck
+			}
+
+			if tt.impostor {
+				// A counterfeit regenerates verify.txt with a key it controls rather than the Opendime's
+				impostor, err := emulator.New(emulator.Options{Serial: "TESTSERIAL"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				impostor.Dir = dir
+
+				stop := make(chan struct{})
+				defer close(stop)
+
+				go impostor.Watch(stop, 5*time.Millisecond) //nolint:errcheck
+			}
+
+			if tt.corrupt {
+				// Echo our nonce with the signature of the previous statement
+				address, signature, _, _ := pkg.ParseVerifyTxt(filepath.Join(dir, pkg.VerifyTxtPath))
+				verifyTxt := pkg.FormatSignedMessage(pkg.Bitcoin, address, signature, device.Statement(nonce))
+				writeVolume(t, dir, map[string]string{pkg.VerifyTxtPath: verifyTxt})
+			}
+
+			got, err := Challenge(pkg.MainNet, dir, nonce, 500*time.Millisecond, 5*time.Millisecond)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Errorf("Challenge() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Challenge() error = %v", err)
+			}
+
+			if got.Nonce != nonce || got.Statement.Serial != "TESTSERIAL" || got.VerifiedMessage.Address != device.Address {
+				t.Errorf("Challenge() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestChallengeNotOpendime(t *testing.T) {
+	_, err := Challenge(pkg.MainNet, t.TempDir(), "00", time.Millisecond, time.Millisecond)
+	if err == nil || errors.Is(err, ErrChallengeTimeout) {
+		t.Errorf("Challenge() error = %v, want read error", err)
+	}
+}
+
+func TestNewChallengeNonce(t *testing.T) {
+	first, err := NewChallengeNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, _ := NewChallengeNonce()
+	if len(first) != pkg.ChallengeNonceSize*2 || first == second {
+		t.Errorf("NewChallengeNonce() = %s, %s", first, second)
+	}
+}
@@ -0,0 +1,231 @@
+package internal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// DefaultMountsFile the kernel mount table on Linux
+const DefaultMountsFile = "/proc/mounts"
+
+// MountRootsEnv environment variable overriding the mount roots to scan (os.PathListSeparator separated)
+const MountRootsEnv = "OPENDIME_MOUNT_ROOTS"
+
+// Device a mounted volume that looks like an Opendime
+type Device struct {
+	// MountPoint path the volume is mounted at
+	MountPoint string
+	// Source block device from the mount table or empty if found by scanning a mount root
+	Source string
+	// VerifyTxt path to advanced/verify.txt on the volume
+	VerifyTxt string
+}
+
+// Mount one entry from a mount table
+type Mount struct {
+	Source     string
+	MountPoint string
+	FSType     string
+}
+
+// Discoverer finds mounted Opendimes. MountsFile is a /proc/mounts style table (skipped if empty or missing) and
+// Roots are directories whose children (and grandchildren, eg /media/$USER/OPENDIME) are checked
+type Discoverer struct {
+	MountsFile string
+	Roots      []string
+}
+
+// DefaultMountRoots the usual places removable volumes are mounted on Linux and macOS
+func DefaultMountRoots() []string {
+	if roots := os.Getenv(MountRootsEnv); roots != "" {
+		return filepath.SplitList(roots)
+	}
+
+	return []string{"/media", "/run/media", "/mnt", "/Volumes"}
+}
+
+// NewDiscoverer returns a Discoverer using /proc/mounts and the default mount roots
+func NewDiscoverer() Discoverer {
+	return Discoverer{
+		MountsFile: DefaultMountsFile,
+		Roots:      DefaultMountRoots(),
+	}
+}
+
+// Discover returns the Opendimes found, sorted by mount point so device indexes are stable
+func (d Discoverer) Discover() ([]Device, error) {
+	found := map[string]Device{}
+
+	if d.MountsFile != "" {
+		file, err := os.Open(d.MountsFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		if err == nil {
+			mounts, err := ParseMounts(file)
+			file.Close()
+			if err != nil {
+				return nil, err
+			}
+
+			for _, mount := range mounts {
+				if IsOpendime(mount.MountPoint) {
+					found[filepath.Clean(mount.MountPoint)] = Device{
+						MountPoint: filepath.Clean(mount.MountPoint),
+						Source:     mount.Source,
+						VerifyTxt:  filepath.Join(mount.MountPoint, pkg.VerifyTxtPath),
+					}
+				}
+			}
+		}
+	}
+
+	for _, root := range d.Roots {
+		for _, dir := range candidateDirs(root) {
+			if _, ok := found[dir]; ok || !IsOpendime(dir) {
+				continue
+			}
+
+			found[dir] = Device{
+				MountPoint: dir,
+				VerifyTxt:  filepath.Join(dir, pkg.VerifyTxtPath),
+			}
+		}
+	}
+
+	devices := make([]Device, 0, len(found))
+	for _, device := range found {
+		devices = append(devices, device)
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].MountPoint < devices[j].MountPoint
+	})
+
+	return devices, nil
+}
+
+// SelectDevice picks a device by selector: "auto" requires exactly one Opendime, otherwise a zero based index
+func SelectDevice(devices []Device, selector string) (Device, error) {
+	if selector == "auto" {
+		switch len(devices) {
+		case 0:
+			return Device{}, errors.New("no Opendime found")
+		case 1:
+			return devices[0], nil
+		default:
+			return Device{}, fmt.Errorf("found %d Opendimes, choose one with -device index", len(devices))
+		}
+	}
+
+	index, err := strconv.Atoi(selector)
+	if err != nil {
+		return Device{}, fmt.Errorf("device must be 'auto' or an index not '%s'", selector)
+	}
+
+	if index < 0 || index >= len(devices) {
+		return Device{}, fmt.Errorf("device index %d out of range, found %d Opendimes", index, len(devices))
+	}
+
+	return devices[index], nil
+}
+
+// IsOpendime returns true if dir looks like the root of an Opendime volume (has advanced/verify.txt)
+func IsOpendime(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, pkg.VerifyTxtPath))
+
+	return err == nil && info.Mode().IsRegular()
+}
+
+// candidateDirs the root itself, its child directories and their child directories
+func candidateDirs(root string) []string {
+	root = filepath.Clean(root)
+	dirs := []string{root}
+
+	for _, child := range subDirs(root) {
+		dirs = append(dirs, child)
+		dirs = append(dirs, subDirs(child)...)
+	}
+
+	return dirs
+}
+
+func subDirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	return dirs
+}
+
+// ParseMounts parses a /proc/mounts style mount table. Fields are space separated with spaces, tabs, newlines
+// and backslashes in paths escaped as octal eg \040
+func ParseMounts(r io.Reader) ([]Mount, error) {
+	var mounts []Mount
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+
+		mounts = append(mounts, Mount{
+			Source:     unescapeMountField(fields[0]),
+			MountPoint: unescapeMountField(fields[1]),
+			FSType:     fields[2],
+		})
+	}
+
+	return mounts, scanner.Err()
+}
+
+// unescapeMountField decodes the \ooo octal escapes used in mount tables
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+3 < len(field) && isOctal(field[i+1:i+4]) {
+			value, _ := strconv.ParseUint(field[i+1:i+4], 8, 8)
+			sb.WriteByte(byte(value))
+			i += 3
+
+			continue
+		}
+
+		sb.WriteByte(field[i])
+	}
+
+	return sb.String()
+}
+
+func isOctal(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '7' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,162 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+func TestParseMounts(t *testing.T) {
+	const mountTable = `sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0
+/dev/sda1 / ext4 rw,relatime 0 0
+/dev/sdb1 /media/tim/OPENDIME vfat rw,nosuid,nodev,relatime 0 0
+/dev/sdc1 /media/tim/My\040Opendime vfat rw,nosuid,nodev,relatime 0 0
+/dev/sdd1 /mnt/back\134slash\011tab vfat rw 0 0
+
+broken
+`
+
+	want := []Mount{
+		{Source: "sysfs", MountPoint: "/sys", FSType: "sysfs"},
+		{Source: "/dev/sda1", MountPoint: "/", FSType: "ext4"},
+		{Source: "/dev/sdb1", MountPoint: "/media/tim/OPENDIME", FSType: "vfat"},
+		{Source: "/dev/sdc1", MountPoint: "/media/tim/My Opendime", FSType: "vfat"},
+		{Source: "/dev/sdd1", MountPoint: "/mnt/back\\slash\ttab", FSType: "vfat"},
+	}
+
+	got, err := ParseMounts(strings.NewReader(mountTable))
+	if err != nil {
+		t.Fatalf("ParseMounts() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseMounts() = %v, want %v", got, want)
+	}
+}
+
+func Test_unescapeMountField(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{`/media/OPENDIME`, "/media/OPENDIME"},
+		{`/media/a\040b`, "/media/a b"},
+		{`/media/a\012b`, "/media/a\nb"},
+		{`/media/trailing\04`, `/media/trailing\04`},
+		{`/media/not\999octal`, `/media/not\999octal`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			if got := unescapeMountField(tt.field); got != tt.want {
+				t.Errorf("unescapeMountField() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// makeOpendime makes a fake Opendime volume at dir
+func makeOpendime(t *testing.T, dir string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Join(dir, "advanced"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, pkg.VerifyTxtPath), []byte("verify"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscoverer_Discover(t *testing.T) {
+	tmp := t.TempDir()
+
+	// Mounted outside the roots and only found by the mount table, with a space in the path
+	mounted := filepath.Join(tmp, "elsewhere", "My Opendime")
+	makeOpendime(t, mounted)
+
+	// Found by scanning the roots: /media/OPENDIME and /media/$USER/OPENDIME style
+	media := filepath.Join(tmp, "media")
+	makeOpendime(t, filepath.Join(media, "OPENDIME"))
+	makeOpendime(t, filepath.Join(media, "tim", "OPENDIME"))
+
+	// Not Opendimes
+	if err := os.MkdirAll(filepath.Join(media, "USBSTICK", "advanced"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	mountsFn := filepath.Join(tmp, "mounts")
+	mountTable := "/dev/sda1 / ext4 rw 0 0\n" +
+		"/dev/sdb1 " + strings.ReplaceAll(mounted, " ", `\040`) + " vfat rw 0 0\n" +
+		"/dev/sdc1 " + filepath.Join(media, "tim", "OPENDIME") + " vfat rw 0 0\n"
+	if err := os.WriteFile(mountsFn, []byte(mountTable), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Device{
+		{MountPoint: mounted, Source: "/dev/sdb1", VerifyTxt: filepath.Join(mounted, pkg.VerifyTxtPath)},
+		{MountPoint: filepath.Join(media, "OPENDIME"), VerifyTxt: filepath.Join(media, "OPENDIME", pkg.VerifyTxtPath)},
+		{MountPoint: filepath.Join(media, "tim", "OPENDIME"), Source: "/dev/sdc1", VerifyTxt: filepath.Join(media, "tim", "OPENDIME", pkg.VerifyTxtPath)},
+	}
+
+	discoverer := Discoverer{MountsFile: mountsFn, Roots: []string{media, filepath.Join(tmp, "missing")}}
+
+	got, err := discoverer.Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() = %v, want %v", got, want)
+	}
+
+	// A missing mount table is not an error, the roots are still scanned
+	discoverer.MountsFile = filepath.Join(tmp, "no-mounts")
+
+	got, err = discoverer.Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Errorf("Discover() found %d devices, want 2", len(got))
+	}
+}
+
+func TestSelectDevice(t *testing.T) {
+	one := []Device{{MountPoint: "/media/OPENDIME"}}
+	two := []Device{{MountPoint: "/media/A"}, {MountPoint: "/media/B"}}
+
+	tests := []struct {
+		name     string
+		devices  []Device
+		selector string
+		want     string
+		wantErr  string
+	}{
+		{"auto one", one, "auto", "/media/OPENDIME", ""},
+		{"auto none", nil, "auto", "", "no Opendime found"},
+		{"auto many", two, "auto", "", "found 2 Opendimes, choose one with -device index"},
+		{"index", two, "1", "/media/B", ""},
+		{"index out of range", two, "2", "", "device index 2 out of range, found 2 Opendimes"},
+		{"not an index", two, "first", "", "device must be 'auto' or an index not 'first'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectDevice(tt.devices, tt.selector)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("SelectDevice() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil || got.MountPoint != tt.want {
+				t.Errorf("SelectDevice() = %v, %v want %v", got.MountPoint, err, tt.want)
+			}
+		})
+	}
+}
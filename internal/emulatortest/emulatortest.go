@@ -0,0 +1,54 @@
+// Package emulatortest makes emulated Opendimes for tests. Only _test.go files import it so neither the testing
+// package nor the test key is built into the binary
+package emulatortest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+	"github.com/timchurchard/opendime-utils/pkg/emulator"
+)
+
+// Key of emulated Opendimes in tests that need a known address
+const (
+	TestWif     = "5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC"
+	TestAddress = "13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu"
+)
+
+// Verified an emulated Opendime and its parsed and verified verify.txt
+type Verified struct {
+	*emulator.Device
+	// VerifyTxt path of verify.txt
+	VerifyTxt string
+	Signature string
+	Message   string
+	Verified  pkg.VerifiedMessage
+}
+
+// NewVerified creates an emulated Opendime in a temp dir of the test and verifies its verify.txt, failing the test
+// on any error
+func NewVerified(t testing.TB, options emulator.Options) Verified {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	device, err := emulator.Create(dir, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifyTxt := filepath.Join(dir, pkg.VerifyTxtPath)
+
+	address, signature, message, err := pkg.ParseVerifyTxt(verifyTxt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedMessage, err := pkg.VerifyMessage(device.Network, address, signature, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Verified{Device: device, VerifyTxt: verifyTxt, Signature: signature, Message: message, Verified: verifiedMessage}
+}
@@ -0,0 +1,182 @@
+// Package history remembers the Opendime statements seen so copied (replayed) or cloned verify.txt files can be
+// spotted, and gives a PASS/WARN/FAIL verdict for accepting an Opendime
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/internal/store"
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// StoreVersion on-disk format version of the history
+const StoreVersion = 1
+
+// PathEnv environment variable overriding the history location
+const PathEnv = "OPENDIME_HISTORY"
+
+// KnownFirmware Opendime firmware versions seen in genuine verify.txt files. Others give a WARN verdict
+var KnownFirmware = []string{"2.3.0", "2.4.0"}
+
+// Level of a Verdict, higher is worse
+type Level int
+
+// Verdict levels
+const (
+	Pass Level = iota
+	Warn
+	Fail
+)
+
+func (l Level) String() string {
+	switch l {
+	case Pass:
+		return "PASS"
+	case Warn:
+		return "WARN"
+	default:
+		return "FAIL"
+	}
+}
+
+// Verdict whether to accept an Opendime and why not
+type Verdict struct {
+	Level   Level
+	Reasons []string
+}
+
+// Raise adds the reason and raises the level to at least level
+func (v *Verdict) Raise(level Level, format string, a ...any) {
+	if level > v.Level {
+		v.Level = level
+	}
+
+	v.Reasons = append(v.Reasons, fmt.Sprintf(format, a...))
+}
+
+// Merge adds the reasons of other and raises the level to at least its level
+func (v *Verdict) Merge(other Verdict) {
+	if other.Level > v.Level {
+		v.Level = other.Level
+	}
+
+	v.Reasons = append(v.Reasons, other.Reasons...)
+}
+
+// Sighting one statement seen
+type Sighting struct {
+	Nonce        string    `json:"nonce"`
+	Serial       string    `json:"serial"`
+	PublicKeyHex string    `json:"publicKey"`
+	Address      string    `json:"address"`
+	Seen         time.Time `json:"seen"`
+	// Failed the sighting had a FAIL verdict so it does not claim the serial for its key
+	Failed bool `json:"failed,omitempty"`
+}
+
+// DefaultPath the history file, $OPENDIME_HISTORY or history.json in the user config directory
+func DefaultPath() (string, error) {
+	if path := os.Getenv(PathEnv); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "opendime-utils", "history.json"), nil
+}
+
+// History the sightings stored at a path
+type History struct {
+	store *store.Store
+	// KnownFirmware versions that pass, defaults to the package KnownFirmware
+	KnownFirmware []string
+}
+
+// Open returns the history stored at path. The file is made by the first Check
+func Open(path string) *History {
+	return &History{store: store.New(path, StoreVersion), KnownFirmware: slices.Clone(KnownFirmware)}
+}
+
+// Check gives the verdict for a verified message against the sightings so far and records it as a new sighting. A
+// sighting only claims its serial for its key if the verdict is not FAIL, so checking a clone or unsealed fake first
+// does not make the genuine Opendime fail later
+func (h *History) Check(verifiedMessage pkg.VerifiedMessage, message string, now time.Time) (Verdict, error) {
+	var (
+		verdict   Verdict
+		sightings []Sighting
+	)
+
+	statement, err := pkg.ParseStatement(message)
+	if err != nil {
+		verdict.Raise(Warn, "%v, it has no nonce or serial to check", err)
+
+		return verdict, nil
+	}
+
+	if statement.Unsealed {
+		verdict.Raise(Fail, "Opendime is unsealed, the private key has been exposed")
+	}
+
+	if !h.knownFirmware(statement.Firmware) {
+		verdict.Raise(Warn, "firmware version '%s' is not a known Opendime version", statement.Firmware)
+	}
+
+	if verifiedMessage.PublicKeyHex == "" {
+		verdict.Raise(Warn, "signature does not reveal the public key so the serial can not be checked for clones")
+
+		return verdict, nil
+	}
+
+	err = h.store.Update(&sightings, func() error {
+		// The first key seen with a serial (and not failed) owns it, later keys are clones
+		var serialSeen, replayed bool
+		for _, sighting := range sightings {
+			if !serialSeen && !sighting.Failed && strings.EqualFold(sighting.Serial, statement.Serial) {
+				serialSeen = true
+
+				if !strings.EqualFold(sighting.PublicKeyHex, verifiedMessage.PublicKeyHex) {
+					verdict.Raise(Fail, "serial %s was seen with a different key (%s on %s), this verify.txt is a clone",
+						statement.Serial, sighting.Address, sighting.Seen.Format(time.DateOnly))
+				}
+			}
+
+			if !replayed && strings.EqualFold(sighting.Nonce, statement.Nonce) {
+				replayed = true
+				verdict.Raise(Warn, "nonce %s was already seen on %s, this verify.txt may be a copy (re-plug the Opendime or use challenge)",
+					statement.Nonce, sighting.Seen.Format(time.DateTime))
+			}
+		}
+
+		sightings = append(sightings, Sighting{
+			Nonce:        statement.Nonce,
+			Serial:       statement.Serial,
+			PublicKeyHex: verifiedMessage.PublicKeyHex,
+			Address:      verifiedMessage.Address,
+			Seen:         now.UTC(),
+			Failed:       verdict.Level == Fail,
+		})
+
+		return nil
+	})
+
+	return verdict, err
+}
+
+// Sightings returns every statement seen in the order seen
+func (h *History) Sightings() ([]Sighting, error) {
+	var sightings []Sighting
+
+	return sightings, h.store.Load(&sightings)
+}
+
+func (h *History) knownFirmware(firmware string) bool {
+	return slices.Contains(h.KnownFirmware, firmware)
+}
@@ -0,0 +1,120 @@
+package history
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/internal/emulatortest"
+	"github.com/timchurchard/opendime-utils/pkg"
+	"github.com/timchurchard/opendime-utils/pkg/emulator"
+)
+
+var testNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func TestHistoryCheck(t *testing.T) {
+	history := Open(filepath.Join(t.TempDir(), "history.json"))
+
+	genuine := emulatortest.NewVerified(t, emulator.Options{Key: emulatortest.TestWif, Serial: "TESTSERIAL"})
+	clone := emulatortest.NewVerified(t, emulator.Options{Serial: "TESTSERIAL"})
+	unknown := emulatortest.NewVerified(t, emulator.Options{Firmware: "9.9.9"})
+	unsealed := emulatortest.NewVerified(t, emulator.Options{Unsealed: true})
+
+	statement, err := pkg.ParseStatement(genuine.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	address, signature, err := pkg.SignMessage(pkg.MainNet, pkg.Bitcoin, emulatortest.TestWif, "Hello World")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wallet, err := pkg.VerifyMessage(pkg.MainNet, address, signature, "Hello World")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		verifiedMessage pkg.VerifiedMessage
+		message         string
+		want            Level
+		wantReason      string
+	}{
+		{"first sighting", genuine.Verified, genuine.Message, Pass, ""},
+		{"replayed nonce", genuine.Verified, genuine.Message, Warn, "nonce " + statement.Nonce + " was already seen on 2024-05-01 12:00:00"},
+		{"clone", clone.Verified, clone.Message, Fail, "serial TESTSERIAL was seen with a different key (" + genuine.Address + " on 2024-05-01)"},
+		{"genuine after clone", genuine.Verified, genuine.Message, Warn, "nonce " + statement.Nonce},
+		{"unknown firmware", unknown.Verified, unknown.Message, Warn, "firmware version '9.9.9' is not a known Opendime version"},
+		{"unsealed", unsealed.Verified, unsealed.Message, Fail, "Opendime is unsealed"},
+		{"wallet message", wallet, "Hello World", Warn, "message is not an Opendime statement"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := history.Check(tt.verifiedMessage, tt.message, testNow)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+
+			if verdict.Level != tt.want {
+				t.Errorf("Check() = %s %v, want %s", verdict.Level, verdict.Reasons, tt.want)
+			}
+
+			if tt.wantReason == "" && len(verdict.Reasons) != 0 {
+				t.Errorf("Check() reasons = %v, want none", verdict.Reasons)
+			}
+
+			if tt.wantReason != "" && (len(verdict.Reasons) == 0 || !strings.HasPrefix(verdict.Reasons[0], tt.wantReason)) {
+				t.Errorf("Check() reasons = %v, want %s", verdict.Reasons, tt.wantReason)
+			}
+		})
+	}
+
+	sightings, err := history.Sightings()
+	if err != nil || len(sightings) != 6 || sightings[0].Serial != "TESTSERIAL" || sightings[0].Address != genuine.Address ||
+		sightings[0].Failed || !sightings[2].Failed {
+		t.Errorf("Sightings() = %v, %v", sightings, err)
+	}
+
+	// Known firmware no longer warns, only the replayed nonce does
+	history.KnownFirmware = append(history.KnownFirmware, "9.9.9")
+	if verdict, _ := history.Check(unknown.Verified, unknown.Message, testNow); verdict.Level != Warn ||
+		len(verdict.Reasons) != 1 || !strings.HasPrefix(verdict.Reasons[0], "nonce") {
+		t.Errorf("Check() known firmware = %s %v", verdict.Level, verdict.Reasons)
+	}
+}
+
+func TestHistoryCheckFakeFirst(t *testing.T) {
+	history := Open(filepath.Join(t.TempDir(), "history.json"))
+
+	fake := emulatortest.NewVerified(t, emulator.Options{Serial: "TESTSERIAL", Unsealed: true})
+	genuine := emulatortest.NewVerified(t, emulator.Options{Key: emulatortest.TestWif, Serial: "TESTSERIAL"})
+	clone := emulatortest.NewVerified(t, emulator.Options{Serial: "TESTSERIAL"})
+
+	// A failed sighting does not claim the serial, the genuine Opendime checked after it owns it
+	tests := []struct {
+		name       string
+		verified   emulatortest.Verified
+		want       Level
+		wantReason string
+	}{
+		{"fake", fake, Fail, "Opendime is unsealed"},
+		{"genuine after fake", genuine, Pass, ""},
+		{"clone after genuine", clone, Fail, "serial TESTSERIAL was seen with a different key (" + genuine.Address + " on 2024-05-01)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := history.Check(tt.verified.Verified, tt.verified.Message, testNow)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+
+			if verdict.Level != tt.want || (tt.wantReason == "") != (len(verdict.Reasons) == 0) ||
+				(tt.wantReason != "" && !strings.HasPrefix(verdict.Reasons[0], tt.wantReason)) {
+				t.Errorf("Check() = %s %v, want %s %s", verdict.Level, verdict.Reasons, tt.want, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package inventory
+
+import (
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/bech32"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// Match an Opendime that derived the looked up address
+type Match struct {
+	Record Record
+	// Source where the record came from eg inventory or the path to a verify.txt
+	Source string
+	// AddressTypeID eg LitecoinP2WPKH and the registered AddressType (zero if no longer registered)
+	AddressTypeID string
+	AddressType   pkg.AddressType
+	Address       string
+}
+
+type indexEntry struct {
+	record   Record
+	source   string
+	typeID   string
+	original string
+}
+
+// Index reverse lookup from every derived address to the Opendime that owns it
+type Index struct {
+	entries map[string][]indexEntry
+}
+
+// NewIndex returns an empty Index
+func NewIndex() *Index {
+	return &Index{entries: map[string][]indexEntry{}}
+}
+
+// Add indexes every derived address of the record
+func (ix *Index) Add(source string, record Record) {
+	for typeID, address := range record.Addresses {
+		entry := indexEntry{record: record, source: source, typeID: typeID, original: address}
+
+		for _, key := range indexKeys(address) {
+			ix.entries[key] = append(ix.entries[key], entry)
+		}
+	}
+}
+
+// Lookup returns every Opendime that derived the address, in the order they were added. An Opendime added from
+// more than one source is returned once, from the first source. Bech32, CashAddr and Ethereum addresses match case
+// insensitively and CashAddr matches with or without its prefix
+func (ix *Index) Lookup(address string) []Match {
+	addressTypes := map[string]pkg.AddressType{}
+	for _, addressType := range pkg.AddressTypes() {
+		addressTypes[addressType.ID] = addressType
+	}
+
+	address = strings.TrimSpace(address)
+
+	entries, ok := ix.entries[normaliseAddress(address)]
+	if !ok {
+		// eg an upper case CashAddr without its prefix
+		entries = ix.entries[strings.ToLower(address)]
+	}
+
+	var (
+		matches []Match
+		seen    = map[string]bool{}
+	)
+	for _, entry := range entries {
+		if seen[entry.record.Key()+"/"+entry.typeID] {
+			continue
+		}
+		seen[entry.record.Key()+"/"+entry.typeID] = true
+
+		matches = append(matches, Match{
+			Record:        entry.record,
+			Source:        entry.source,
+			AddressTypeID: entry.typeID,
+			AddressType:   addressTypes[entry.typeID],
+			Address:       entry.original,
+		})
+	}
+
+	return matches
+}
+
+// indexKeys the normalised address and, for CashAddr, the address without the prefix
+func indexKeys(address string) []string {
+	key := normaliseAddress(address)
+
+	if _, payload, ok := strings.Cut(key, ":"); ok {
+		return []string{key, payload}
+	}
+
+	return []string{key}
+}
+
+// normaliseAddress lower cases addresses that are case insensitive. Base58 addresses are case sensitive
+func normaliseAddress(address string) string {
+	lower := strings.ToLower(address)
+
+	if strings.HasPrefix(lower, "0x") || strings.Contains(lower, ":") {
+		return lower
+	}
+
+	if _, _, _, err := bech32.DecodeGeneric(address); err == nil {
+		return lower
+	}
+
+	return address
+}
@@ -0,0 +1,62 @@
+package inventory
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/timchurchard/opendime-utils/internal/emulatortest"
+	"github.com/timchurchard/opendime-utils/pkg"
+	"github.com/timchurchard/opendime-utils/pkg/emulator"
+)
+
+func TestIndex_Lookup(t *testing.T) {
+	first := emulatedRecord(t, emulator.Options{Key: emulatortest.TestWif, Serial: "FIRSTSERIAL"}, "tips")
+	second := emulatedRecord(t, emulator.Options{Coin: pkg.Litecoin, Serial: "SECONDSERIAL"}, "")
+	second.Addresses["RemovedCoinP2PKH"] = "1RemovedCoinAddressxxxxxxxxxxxxxxx"
+
+	index := NewIndex()
+	index.Add("inventory", first)
+	index.Add("verify.txt", second)
+	index.Add("copy/verify.txt", first)
+
+	tests := []struct {
+		name       string
+		address    string
+		wantSerial string
+		wantTypeID string
+		wantSource string
+	}{
+		{"base58", emulatortest.TestAddress, "FIRSTSERIAL", "BitcoinP2PKH", "inventory"},
+		{"base58 is case sensitive", "13DNBMR7AV1CREZJJFAXE3Q3UIXDXXHGNU", "", "", ""},
+		{"bech32 upper case", strings.ToUpper(first.Addresses["LitecoinP2WPKH"]), "FIRSTSERIAL", "LitecoinP2WPKH", "inventory"},
+		{"ethereum lower case", strings.ToLower(second.Addresses["Ethereum"]), "SECONDSERIAL", "Ethereum", "verify.txt"},
+		{"cashaddr", second.Addresses["BitcoinCashP2PKH"], "SECONDSERIAL", "BitcoinCashP2PKH", "verify.txt"},
+		{"cashaddr without prefix", strings.ToUpper(second.Addresses["BitcoinCashP2PKH"][len("bitcoincash:"):]), "SECONDSERIAL", "BitcoinCashP2PKH", "verify.txt"},
+		{"unregistered type", "1RemovedCoinAddressxxxxxxxxxxxxxxx", "SECONDSERIAL", "RemovedCoinP2PKH", "verify.txt"},
+		{"unknown", "1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := index.Lookup(" " + tt.address + "\n")
+			if tt.wantSerial == "" {
+				if len(matches) != 0 {
+					t.Errorf("Lookup() = %+v, want no match", matches)
+				}
+				return
+			}
+
+			if len(matches) != 1 {
+				t.Fatalf("Lookup() = %d matches, want 1", len(matches))
+			}
+
+			match := matches[0]
+			if match.Record.Serial != tt.wantSerial || match.AddressTypeID != tt.wantTypeID || match.Source != tt.wantSource {
+				t.Errorf("Lookup() = %s %s %s", match.Record.Serial, match.AddressTypeID, match.Source)
+			}
+
+			if (match.AddressType.ID == "") != (tt.wantTypeID == "RemovedCoinP2PKH") {
+				t.Errorf("Lookup() AddressType = %+v", match.AddressType)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// Package inventory keeps a local record of owned Opendimes so sigtoaddr does not have to be re-run for each one
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/internal/store"
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// StoreVersion on-disk format version of the inventory
+const StoreVersion = 1
+
+// PathEnv environment variable overriding the inventory location
+const PathEnv = "OPENDIME_INVENTORY"
+
+// ErrNotFound no record matches the serial, address or label
+var ErrNotFound = errors.New("not found in inventory")
+
+// Record one Opendime in the inventory
+type Record struct {
+	// Serial from the Opendime statement, empty if the message was not signed by an Opendime
+	Serial       string      `json:"serial"`
+	Label        string      `json:"label,omitempty"`
+	Network      pkg.Network `json:"network"`
+	Address      string      `json:"address"`
+	PublicKeyHex string      `json:"publicKey"`
+	Firmware     string      `json:"firmware,omitempty"`
+	// Addresses derived addresses by address type ID eg BitcoinP2WPKH
+	Addresses map[string]string `json:"addresses"`
+	Added     time.Time         `json:"added"`
+}
+
+// Key the serial or, for messages not signed by an Opendime, the address
+func (r Record) Key() string {
+	if r.Serial != "" {
+		return r.Serial
+	}
+
+	return r.Address
+}
+
+// Matches returns true if key is the serial, address or label of the record
+func (r Record) Matches(key string) bool {
+	return key != "" && (strings.EqualFold(r.Serial, key) || strings.EqualFold(r.Address, key) || r.Label == key)
+}
+
+// NewRecord verifies the signed message and makes a record with every derived address
+func NewRecord(network pkg.Network, address, signature, message, label string, added time.Time) (Record, error) {
+	verifiedMessage, err := pkg.VerifyMessage(network, address, signature, message)
+	if err != nil {
+		return Record{}, fmt.Errorf("unable to verify signature: %w", err)
+	}
+
+	if verifiedMessage.PublicKeyHex == "" {
+		return Record{}, fmt.Errorf("signature for %s does not reveal the public key", address)
+	}
+
+	addresses, err := pkg.GetAddresses(verifiedMessage)
+	if err != nil {
+		return Record{}, err
+	}
+
+	// Wallet signed messages have no statement, they are stored without serial and firmware
+	statement, _ := pkg.ParseStatement(message)
+
+	return Record{
+		Serial:       statement.Serial,
+		Label:        label,
+		Network:      network,
+		Address:      address,
+		PublicKeyHex: verifiedMessage.PublicKeyHex,
+		Firmware:     statement.Firmware,
+		Addresses:    addresses.Map(),
+		Added:        added.UTC(),
+	}, nil
+}
+
+// DefaultPath the inventory file, $OPENDIME_INVENTORY or inventory.json in the user config directory
+func DefaultPath() (string, error) {
+	if path := os.Getenv(PathEnv); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "opendime-utils", "inventory.json"), nil
+}
+
+// Inventory the records stored at a path
+type Inventory struct {
+	store *store.Store
+}
+
+// Open returns the inventory stored at path. The file is made by the first Add
+func Open(path string) *Inventory {
+	return &Inventory{store: store.New(path, StoreVersion)}
+}
+
+// Add stores the record. A record with the same serial (or address) already in the inventory is an error
+func (i *Inventory) Add(record Record) error {
+	var records []Record
+
+	return i.store.Update(&records, func() error {
+		for _, existing := range records {
+			if existing.Matches(record.Key()) {
+				return fmt.Errorf("%s already in inventory", record.Key())
+			}
+		}
+
+		records = append(records, record)
+
+		return nil
+	})
+}
+
+// List returns every record in the order added
+func (i *Inventory) List() ([]Record, error) {
+	var records []Record
+
+	return records, i.store.Load(&records)
+}
+
+// Get returns the record by serial, address or label
+func (i *Inventory) Get(key string) (Record, error) {
+	records, err := i.List()
+	if err != nil {
+		return Record{}, err
+	}
+
+	for _, record := range records {
+		if record.Matches(key) {
+			return record, nil
+		}
+	}
+
+	return Record{}, fmt.Errorf("%s %w", key, ErrNotFound)
+}
+
+// Remove deletes the record by serial, address or label and returns it
+func (i *Inventory) Remove(key string) (Record, error) {
+	var (
+		records []Record
+		removed Record
+	)
+
+	err := i.store.Update(&records, func() error {
+		for n, record := range records {
+			if record.Matches(key) {
+				removed = record
+				records = append(records[:n], records[n+1:]...)
+
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%s %w", key, ErrNotFound)
+	})
+
+	return removed, err
+}
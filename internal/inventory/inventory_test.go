@@ -0,0 +1,107 @@
+package inventory
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/internal/emulatortest"
+	"github.com/timchurchard/opendime-utils/pkg"
+	"github.com/timchurchard/opendime-utils/pkg/emulator"
+)
+
+var testAdded = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// emulatedRecord makes a record from an emulated Opendime
+func emulatedRecord(t *testing.T, options emulator.Options, label string) Record {
+	t.Helper()
+
+	emulated := emulatortest.NewVerified(t, options)
+
+	record, err := NewRecord(pkg.MainNet, emulated.Address, emulated.Signature, emulated.Message, label, testAdded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return record
+}
+
+func TestNewRecord(t *testing.T) {
+	record := emulatedRecord(t, emulator.Options{Key: emulatortest.TestWif, Serial: "TESTSERIAL"}, "tips")
+
+	if record.Serial != "TESTSERIAL" || record.Key() != "TESTSERIAL" || record.Label != "tips" || record.Address != emulatortest.TestAddress ||
+		record.Firmware != emulator.DefaultFirmware || !record.Added.Equal(testAdded) {
+		t.Errorf("NewRecord() = %+v", record)
+	}
+
+	if record.Addresses["BitcoinP2PKH"] != emulatortest.TestAddress || record.Addresses["BitcoinP2WPKH"] == "" || len(record.PublicKeyHex) != 130 {
+		t.Errorf("NewRecord() addresses = %v public key = %s", record.Addresses, record.PublicKeyHex)
+	}
+
+	// Wallet signed messages have no serial so are keyed by address
+	address, signature, err := pkg.SignMessage(pkg.MainNet, pkg.Bitcoin, emulatortest.TestWif, "Hello World")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err = NewRecord(pkg.MainNet, address, signature, "Hello World", "", testAdded)
+	if err != nil || record.Serial != "" || record.Key() != emulatortest.TestAddress {
+		t.Errorf("NewRecord() wallet message = %+v, %v", record, err)
+	}
+
+	if _, err := NewRecord(pkg.MainNet, address, signature, "Goodbye World", "", testAdded); err == nil {
+		t.Errorf("NewRecord() invalid signature no error")
+	}
+}
+
+func TestInventory(t *testing.T) {
+	inventory := Open(filepath.Join(t.TempDir(), "inventory.json"))
+
+	records, err := inventory.List()
+	if err != nil || len(records) != 0 {
+		t.Fatalf("List() empty = %v, %v", records, err)
+	}
+
+	first := emulatedRecord(t, emulator.Options{Key: emulatortest.TestWif, Serial: "FIRSTSERIAL"}, "tips")
+	second := emulatedRecord(t, emulator.Options{Coin: pkg.Litecoin, Serial: "SECONDSERIAL"}, "")
+
+	for _, record := range []Record{first, second} {
+		if err := inventory.Add(record); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	if err := inventory.Add(first); err == nil || err.Error() != "FIRSTSERIAL already in inventory" {
+		t.Errorf("Add() duplicate error = %v", err)
+	}
+
+	records, err = inventory.List()
+	if err != nil || len(records) != 2 || records[0].Serial != "FIRSTSERIAL" || records[1].Serial != "SECONDSERIAL" {
+		t.Fatalf("List() = %v, %v", records, err)
+	}
+
+	for _, key := range []string{"FIRSTSERIAL", "firstserial", emulatortest.TestAddress, "tips"} {
+		if got, err := inventory.Get(key); err != nil || got.Serial != "FIRSTSERIAL" {
+			t.Errorf("Get(%s) = %v, %v", key, got.Serial, err)
+		}
+	}
+
+	if _, err := inventory.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() missing error = %v", err)
+	}
+
+	removed, err := inventory.Remove("tips")
+	if err != nil || removed.Serial != "FIRSTSERIAL" {
+		t.Errorf("Remove() = %v, %v", removed.Serial, err)
+	}
+
+	if _, err := inventory.Remove("tips"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Remove() twice error = %v", err)
+	}
+
+	records, _ = inventory.List()
+	if len(records) != 1 || records[0].Serial != "SECONDSERIAL" {
+		t.Errorf("List() after remove = %v", records)
+	}
+}
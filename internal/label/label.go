@@ -0,0 +1,228 @@
+// Package label renders a printable A4 label with a QR code and caption for every derived address of an Opendime,
+// as SVG or a single PDF page
+package label
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+	"github.com/timchurchard/opendime-utils/pkg/qr"
+)
+
+// Page layout in points (1/72 inch) on A4
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	margin       = 36.0
+	columns      = 4
+	cellWidth    = (pageWidth - 2*margin) / columns
+	cellHeight   = 148.0
+	cellPadding  = 8.0
+	titleHeight  = 30.0
+	titleSize    = 14.0
+	qrSize       = 100.0
+	quietZone    = 4
+	captionSize  = 9.0
+	textSize     = 6.0
+	lineHeight   = 7.0
+	textWrap     = 34 // characters of Courier at textSize that fit in a cell
+	svgTextFont  = "Courier, monospace"
+	svgTitleFont = "Helvetica, Arial, sans-serif"
+)
+
+// Item one QR code on the label
+type Item struct {
+	// Caption eg Bitcoin P2WPKH
+	Caption string
+	// Text encoded in the QR code, the address or a payment URI
+	Text string
+	Code *qr.Code
+}
+
+// Label a page of QR codes
+type Label struct {
+	Title string
+	Items []Item
+}
+
+// New makes a label for every derived address. With uri the QR codes are BIP21/EIP-681 payment URIs including the
+// optional amount and uriLabel
+func New(title string, addresses pkg.Addresses, uri bool, amount, uriLabel string, level qr.Level) (Label, error) {
+	label := Label{Title: title}
+
+	for _, derived := range addresses.Derived {
+		text := derived.Address
+
+		if uri {
+			var err error
+
+			text, err = pkg.PaymentURI(derived, amount, uriLabel)
+			if err != nil {
+				return Label{}, fmt.Errorf("%s: %w", derived.Label, err)
+			}
+		}
+
+		code, err := qr.Encode(text, level)
+		if err != nil {
+			return Label{}, fmt.Errorf("%s: %w", derived.Label, err)
+		}
+
+		label.Items = append(label.Items, Item{Caption: derived.Label, Text: text, Code: code})
+	}
+
+	return label, nil
+}
+
+// cell returns the top left of the QR code of item idx, y grows down the page
+func cell(idx int) (float64, float64) {
+	col, row := idx%columns, idx/columns
+
+	return margin + float64(col)*cellWidth + cellPadding, margin + titleHeight + float64(row)*cellHeight
+}
+
+// wrap splits text into lines of at most textWrap characters
+func wrap(text string) []string {
+	var lines []string
+
+	for len(text) > textWrap {
+		lines = append(lines, text[:textWrap])
+		text = text[textWrap:]
+	}
+
+	return append(lines, text)
+}
+
+// modules calls fn with the top left and size of every dark module of the code drawn at x, y
+func modules(code *qr.Code, x, y float64, fn func(x, y, size float64)) {
+	size := qrSize / float64(code.Size+2*quietZone)
+
+	for my := 0; my < code.Size; my++ {
+		for mx := 0; mx < code.Size; mx++ {
+			if code.Black(mx, my) {
+				fn(x+float64(mx+quietZone)*size, y+float64(my+quietZone)*size, size)
+			}
+		}
+	}
+}
+
+// WriteSVG writes the label as an A4 SVG
+func (l Label) WriteSVG(w io.Writer) error {
+	var b bytes.Buffer
+
+	fmt.Fprintln(&b, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="210mm" height="297mm" viewBox="0 0 %.0f %.0f">`+"\n", pageWidth, pageHeight)
+	fmt.Fprintln(&b, `<rect width="100%" height="100%" fill="#fff"/>`)
+	fmt.Fprintf(&b, `<text x="%.2f" y="%.2f" font-family="%s" font-size="%.0f">%s</text>`+"\n",
+		margin, margin+titleSize, svgTitleFont, titleSize, html.EscapeString(l.Title))
+
+	for idx, item := range l.Items {
+		x, y := cell(idx)
+
+		var path strings.Builder
+		modules(item.Code, x, y, func(x, y, size float64) {
+			fmt.Fprintf(&path, "M%.2f %.2fh%.2fv%.2fh-%.2fz", x, y, size, size, size)
+		})
+
+		fmt.Fprintf(&b, `<path d="%s" fill="#000" shape-rendering="crispEdges"/>`+"\n", path.String())
+
+		y += qrSize + captionSize
+		fmt.Fprintf(&b, `<text x="%.2f" y="%.2f" font-family="%s" font-size="%.0f">%s</text>`+"\n",
+			x, y, svgTitleFont, captionSize, html.EscapeString(item.Caption))
+
+		for _, line := range wrap(item.Text) {
+			y += lineHeight
+			fmt.Fprintf(&b, `<text x="%.2f" y="%.2f" font-family="%s" font-size="%.0f">%s</text>`+"\n",
+				x, y, svgTextFont, textSize, html.EscapeString(line))
+		}
+	}
+
+	fmt.Fprintln(&b, `</svg>`)
+
+	_, err := w.Write(b.Bytes())
+
+	return err
+}
+
+// WritePDF writes the label as a single A4 PDF page using the standard Helvetica and Courier fonts
+func (l Label) WritePDF(w io.Writer) error {
+	var content bytes.Buffer
+
+	// PDF y grows up the page
+	flip := func(y float64) float64 { return pageHeight - y }
+
+	fmt.Fprintf(&content, "BT /F1 %.0f Tf %.2f %.2f Td (%s) Tj ET\n", titleSize, margin, flip(margin+titleSize), pdfEscape(l.Title))
+
+	for idx, item := range l.Items {
+		x, y := cell(idx)
+
+		fmt.Fprintln(&content, "0 g")
+		modules(item.Code, x, y, func(x, y, size float64) {
+			fmt.Fprintf(&content, "%.2f %.2f %.2f %.2f re\n", x, flip(y+size), size, size)
+		})
+		fmt.Fprintln(&content, "f")
+
+		y += qrSize + captionSize
+		fmt.Fprintf(&content, "BT /F1 %.0f Tf %.2f %.2f Td (%s) Tj ET\n", captionSize, x, flip(y), pdfEscape(item.Caption))
+
+		for _, line := range wrap(item.Text) {
+			y += lineHeight
+			fmt.Fprintf(&content, "BT /F2 %.0f Tf %.2f %.2f Td (%s) Tj ET\n", textSize, x, flip(y), pdfEscape(line))
+		}
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>",
+			pageWidth, pageHeight),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var (
+		b       bytes.Buffer
+		offsets []int
+	)
+
+	fmt.Fprint(&b, "%PDF-1.4\n")
+
+	for idx, object := range objects {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", idx+1, object)
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := w.Write(b.Bytes())
+
+	return err
+}
+
+// pdfEscape escapes a PDF string. The standard fonts only cover ASCII so anything else is replaced by ?
+func pdfEscape(text string) string {
+	var b strings.Builder
+
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < ' ' || r > '~':
+			b.WriteRune('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
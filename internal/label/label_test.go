@@ -0,0 +1,163 @@
+package label
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+	"github.com/timchurchard/opendime-utils/pkg/qr"
+)
+
+func testAddresses(t *testing.T) pkg.Addresses {
+	t.Helper()
+
+	address, signature, message, err := pkg.ParseVerifyTxt("../../verify.txt_tips")
+	if err != nil {
+		t.Skip("verify.txt_tips not found")
+	}
+
+	verifiedMessage, err := pkg.VerifyMessage(pkg.MainNet, address, signature, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addresses, err := pkg.GetAddresses(verifiedMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return addresses
+}
+
+func darkModules(label Label) int {
+	var count int
+
+	for _, item := range label.Items {
+		for y := 0; y < item.Code.Size; y++ {
+			for x := 0; x < item.Code.Size; x++ {
+				if item.Code.Black(x, y) {
+					count++
+				}
+			}
+		}
+	}
+
+	return count
+}
+
+func TestNew(t *testing.T) {
+	addresses := testAddresses(t)
+
+	label, err := New("Opendime <tips>", addresses, true, "0.001", "Tips", qr.M)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(label.Items) != len(addresses.Derived) {
+		t.Fatalf("New() items = %d, want %d", len(label.Items), len(addresses.Derived))
+	}
+
+	if item := label.Items[0]; item.Caption != "Bitcoin P2PKH" || item.Text != "bitcoin:1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR?amount=0.001&label=Tips" {
+		t.Errorf("New() first item = %s %s", item.Caption, item.Text)
+	}
+
+	if _, err := New("", addresses, true, "0.0000000001", "", qr.M); err == nil {
+		t.Errorf("New() bad amount no error")
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	label, err := New("Opendime <tips> & co", testAddresses(t), false, "", "", qr.M)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := label.WriteSVG(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	// Well formed XML with the title, a caption and the wrapped address text of every item
+	var texts []string
+	decoder := xml.NewDecoder(bytes.NewReader(out.Bytes()))
+	for inText := false; ; {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("WriteSVG() not well formed: %v", err)
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			inText = token.Name.Local == "text"
+		case xml.CharData:
+			if inText {
+				texts = append(texts, string(token))
+			}
+		case xml.EndElement:
+			inText = false
+		}
+	}
+
+	if len(texts) < 1+2*len(label.Items) || texts[0] != "Opendime <tips> & co" || texts[1] != "Bitcoin P2PKH" || texts[2] != "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR" {
+		t.Errorf("WriteSVG() texts = %v", texts)
+	}
+
+	if got := strings.Count(out.String(), "z"); got < darkModules(label) {
+		t.Errorf("WriteSVG() modules = %d, want %d", got, darkModules(label))
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	label, err := New("Opendime (tips) £", testAddresses(t), false, "", "", qr.M)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := label.WritePDF(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	pdf := out.String()
+
+	if !strings.HasPrefix(pdf, "%PDF-1.4\n") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Fatalf("WritePDF() header/trailer = %q ... %q", pdf[:10], pdf[len(pdf)-10:])
+	}
+
+	// Every xref offset points at its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(pdf)
+	xrefOffset, _ := strconv.Atoi(startxref[1])
+	if !strings.HasPrefix(pdf[xrefOffset:], "xref\n0 7\n") {
+		t.Fatalf("WritePDF() startxref %d does not point at xref", xrefOffset)
+	}
+
+	for idx, entry := range regexp.MustCompile(`(\d{10}) 00000 n \n`).FindAllStringSubmatch(pdf[xrefOffset:], -1) {
+		offset, _ := strconv.Atoi(entry[1])
+		if want := fmt.Sprintf("%d 0 obj\n", idx+1); !strings.HasPrefix(pdf[offset:], want) {
+			t.Errorf("WritePDF() xref %d = %d, want %q", idx+1, offset, want)
+		}
+	}
+
+	// The stream length matches
+	stream := regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*)endstream`).FindStringSubmatch(pdf)
+	if length, _ := strconv.Atoi(stream[1]); length != len(stream[2]) {
+		t.Errorf("WritePDF() stream length = %d, want %d", length, len(stream[2]))
+	}
+
+	if got := strings.Count(stream[2], " re\n"); got != darkModules(label) {
+		t.Errorf("WritePDF() modules = %d, want %d", got, darkModules(label))
+	}
+
+	if !strings.Contains(stream[2], `(Opendime \(tips\) ?) Tj`) || !strings.Contains(stream[2], "(Bitcoin P2WPKH) Tj") {
+		t.Errorf("WritePDF() text missing")
+	}
+}
@@ -0,0 +1,92 @@
+package report
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/internal/accept"
+)
+
+// CoinBalance the balance of every derived address of one coin
+type CoinBalance struct {
+	Coin   string  `json:"coin"`
+	Amount float64 `json:"amount"`
+	Value  float64 `json:"value"`
+}
+
+// Accepted one Opendime of an accept receipt
+type Accepted struct {
+	VerifyTxt string        `json:"verifyTxt"`
+	Opendime  string        `json:"opendime,omitempty"`
+	Serial    string        `json:"serial,omitempty"`
+	Balances  []CoinBalance `json:"balances"`
+	Value     float64       `json:"value"`
+	// Unchecked derived addresses whose balance could not be checked
+	Unchecked int `json:"unchecked"`
+	// SpendUnchecked derived addresses whose spend history could not be checked
+	SpendUnchecked int      `json:"spendUnchecked"`
+	Verdict        *Verdict `json:"verdict"`
+	Accepted       bool     `json:"accepted"`
+}
+
+// Accept the output of accept, the receipt
+type Accept struct {
+	Time      time.Time  `json:"time"`
+	Opendimes []Accepted `json:"opendimes"`
+	// Value of the accepted Opendimes in Currency
+	Value    float64 `json:"value"`
+	Currency string  `json:"currency"`
+	Failed   int     `json:"failed"`
+}
+
+// NewAccept makes the report of a receipt
+func NewAccept(receipt accept.Receipt, now time.Time, currency string) Accept {
+	result := Accept{
+		Time:      now,
+		Opendimes: make([]Accepted, 0, len(receipt.Results)),
+		Value:     receipt.Value,
+		Currency:  currency,
+		Failed:    receipt.Failed,
+	}
+
+	for _, r := range receipt.Results {
+		accepted := Accepted{
+			VerifyTxt:      r.VerifyTxt,
+			Opendime:       r.Address,
+			Serial:         r.Serial,
+			Balances:       []CoinBalance{},
+			Value:          r.Value,
+			Unchecked:      r.Unchecked,
+			SpendUnchecked: r.SpendUnchecked,
+			Verdict:        NewVerdict(&r.Verdict),
+			Accepted:       r.Accepted(),
+		}
+
+		for _, balance := range r.Balances {
+			accepted.Balances = append(accepted.Balances, CoinBalance{Coin: balance.Coin, Amount: balance.Amount, Value: balance.Value})
+		}
+
+		result.Opendimes = append(result.Opendimes, accepted)
+	}
+
+	return result
+}
+
+// Header implements Table
+func (a Accept) Header() []string {
+	return []string{"verify_txt", "opendime", "serial", "value", "currency", "unchecked", "spend_unchecked", "verdict", "accepted"}
+}
+
+// Rows implements Table, one row per Opendime
+func (a Accept) Rows() [][]string {
+	rows := make([][]string, 0, len(a.Opendimes))
+
+	for _, o := range a.Opendimes {
+		rows = append(rows, []string{
+			o.VerifyTxt, o.Opendime, o.Serial, strconv.FormatFloat(o.Value, 'f', 2, 64), a.Currency,
+			strconv.Itoa(o.Unchecked), strconv.Itoa(o.SpendUnchecked), o.Verdict.Level, strconv.FormatBool(o.Accepted),
+		})
+	}
+
+	return rows
+}
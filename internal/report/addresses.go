@@ -0,0 +1,161 @@
+package report
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/internal"
+	"github.com/timchurchard/opendime-utils/internal/history"
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// Verified a verified signed message
+type Verified struct {
+	Address string      `json:"address"`
+	Network pkg.Network `json:"network"`
+	Valid   bool        `json:"valid"`
+	// AddressType ID of the address type the signature proved eg BitcoinP2WPKH
+	AddressType string `json:"addressType,omitempty"`
+	// PublicKey uncompressed hex, empty if the signature does not reveal it (BIP322 P2TR)
+	PublicKey string `json:"publicKey,omitempty"`
+	// Magic the message magic the signature was made with eg Dogecoin Signed Message, empty for BIP322
+	Magic string `json:"magic,omitempty"`
+}
+
+// NewVerified makes the report of a verified message
+func NewVerified(message pkg.VerifiedMessage) Verified {
+	return Verified{
+		Address:     message.Address,
+		Network:     message.Network,
+		Valid:       message.IsValid,
+		AddressType: message.AddressType,
+		PublicKey:   message.PublicKeyHex,
+		Magic:       pkg.MagicName(message.MessageMagic),
+	}
+}
+
+// Statement the statement of an Opendime signed message
+type Statement struct {
+	Nonce     string     `json:"nonce"`
+	Serial    string     `json:"serial"`
+	Firmware  string     `json:"firmware,omitempty"`
+	BuildTime *time.Time `json:"buildTime,omitempty"`
+	GitRef    string     `json:"gitRef,omitempty"`
+	Coin      string     `json:"coin,omitempty"`
+	Unsealed  bool       `json:"unsealed"`
+}
+
+// NewStatement makes the report of a statement
+func NewStatement(statement pkg.OpendimeStatement) *Statement {
+	result := &Statement{
+		Nonce:    statement.Nonce,
+		Serial:   statement.Serial,
+		Firmware: statement.Firmware,
+		GitRef:   statement.GitRef,
+		Coin:     statement.Coin,
+		Unsealed: statement.Unsealed,
+	}
+
+	if !statement.BuildTime.IsZero() {
+		result.BuildTime = &statement.BuildTime
+	}
+
+	return result
+}
+
+// Balance of an address
+type Balance struct {
+	Amount float64 `json:"amount"`
+	// Value of the amount in Currency, zero for test networks
+	Value    float64 `json:"value"`
+	Currency string  `json:"currency"`
+	// Extra eg Ethereum tokens held by the address
+	Extra string `json:"extra,omitempty"`
+}
+
+// Address a derived address with its balance and spend history if they were checked
+type Address struct {
+	ID      string `json:"id"`
+	Coin    string `json:"coin"`
+	Type    string `json:"type,omitempty"`
+	Label   string `json:"label"`
+	Address string `json:"address"`
+
+	Balance *Balance `json:"balance,omitempty"`
+	// Spends number of times the address has sent coins
+	Spends *int `json:"spends,omitempty"`
+	// SpendError why the spend history could not be checked
+	SpendError string `json:"spendError,omitempty"`
+}
+
+// NewAddresses makes the report of every derived address
+func NewAddresses(addresses pkg.Addresses) []Address {
+	result := make([]Address, 0, len(addresses.Derived))
+
+	for _, derived := range addresses.Derived {
+		result = append(result, Address{
+			ID:      derived.ID,
+			Coin:    derived.Coin,
+			Type:    derived.Type,
+			Label:   derived.Label,
+			Address: derived.Address,
+		})
+	}
+
+	return result
+}
+
+// AddSpent adds the spend history to the addresses
+func AddSpent(addresses []Address, checks []internal.SpendCheck) {
+	for _, check := range checks {
+		for idx := range addresses {
+			if addresses[idx].Address != check.Derived.Address {
+				continue
+			}
+
+			if check.Err != nil {
+				addresses[idx].SpendError = check.Err.Error()
+				continue
+			}
+
+			spends := check.Spends
+			addresses[idx].Spends = &spends
+		}
+	}
+}
+
+// cells returns the csv columns of the address: id, coin, type, label, address, amount, value, currency and spends
+func (a Address) cells() []string {
+	cells := []string{a.ID, a.Coin, a.Type, a.Label, a.Address, "", "", "", ""}
+
+	if a.Balance != nil {
+		cells[5] = strconv.FormatFloat(a.Balance.Amount, 'f', 8, 64)
+		cells[6] = strconv.FormatFloat(a.Balance.Value, 'f', 2, 64)
+		cells[7] = a.Balance.Currency
+	}
+
+	if a.Spends != nil {
+		cells[8] = strconv.Itoa(*a.Spends)
+	}
+
+	return cells
+}
+
+// addressHeader the csv header of Address.cells
+var addressHeader = []string{"id", "coin", "type", "label", "address", "amount", "value", "currency", "spends"}
+
+// Verdict the replay and clone verdict
+type Verdict struct {
+	// Level PASS, WARN or FAIL
+	Level   string   `json:"level"`
+	Reasons []string `json:"reasons"`
+}
+
+// NewVerdict makes the report of a verdict, nil if there is none
+func NewVerdict(verdict *history.Verdict) *Verdict {
+	if verdict == nil {
+		return nil
+	}
+
+	return &Verdict{Level: verdict.Level.String(), Reasons: append([]string{}, verdict.Reasons...)}
+}
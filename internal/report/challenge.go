@@ -0,0 +1,36 @@
+package report
+
+import "github.com/timchurchard/opendime-utils/internal"
+
+// Challenge the output of a passed challenge, a failed challenge is an Error
+type Challenge struct {
+	MountPoint string     `json:"mountPoint"`
+	Nonce      string     `json:"nonce"`
+	Address    string     `json:"address"`
+	Statement  *Statement `json:"statement"`
+}
+
+// NewChallenge makes the report of a passed challenge
+func NewChallenge(mountPoint string, result internal.ChallengeResult) Challenge {
+	return Challenge{
+		MountPoint: mountPoint,
+		Nonce:      result.Nonce,
+		Address:    result.VerifiedMessage.Address,
+		Statement:  NewStatement(result.Statement),
+	}
+}
+
+// Header implements Table
+func (c Challenge) Header() []string {
+	return []string{"mount_point", "nonce", "address", "serial"}
+}
+
+// Rows implements Table
+func (c Challenge) Rows() [][]string {
+	serial := ""
+	if c.Statement != nil {
+		serial = c.Statement.Serial
+	}
+
+	return [][]string{{c.MountPoint, c.Nonce, c.Address, serial}}
+}
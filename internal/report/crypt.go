@@ -0,0 +1,45 @@
+package report
+
+import (
+	"encoding/base64"
+	"unicode/utf8"
+)
+
+// Crypt operations
+const (
+	Encrypt = "encrypt"
+	Decrypt = "decrypt"
+)
+
+// Crypt the output of crypt
+type Crypt struct {
+	// Operation encrypt or decrypt
+	Operation string `json:"operation"`
+	// Address the message was encrypted for
+	Address string `json:"address,omitempty"`
+	// Output the cipher or plain text, empty if it was written to File
+	Output string `json:"output,omitempty"`
+	// Encoding of Output, base64 or text
+	Encoding string `json:"encoding,omitempty"`
+	File     string `json:"file,omitempty"`
+}
+
+// SetOutput sets the output, as text if it is valid UTF-8 otherwise base64
+func (c *Crypt) SetOutput(data []byte) {
+	if c.Operation == Decrypt && utf8.Valid(data) {
+		c.Output, c.Encoding = string(data), "text"
+		return
+	}
+
+	c.Output, c.Encoding = base64.StdEncoding.EncodeToString(data), "base64"
+}
+
+// Header implements Table
+func (c Crypt) Header() []string {
+	return []string{"operation", "address", "output", "encoding", "file"}
+}
+
+// Rows implements Table
+func (c Crypt) Rows() [][]string {
+	return [][]string{{c.Operation, c.Address, c.Output, c.Encoding, c.File}}
+}
@@ -0,0 +1,28 @@
+package report
+
+import (
+	"strconv"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// Emulate the output of emulate
+type Emulate struct {
+	Dir      string      `json:"dir"`
+	Coin     string      `json:"coin"`
+	Network  pkg.Network `json:"network"`
+	Address  string      `json:"address"`
+	Serial   string      `json:"serial"`
+	Firmware string      `json:"firmware"`
+	Unsealed bool        `json:"unsealed"`
+}
+
+// Header implements Table
+func (e Emulate) Header() []string {
+	return []string{"dir", "coin", "network", "address", "serial", "firmware", "unsealed"}
+}
+
+// Rows implements Table
+func (e Emulate) Rows() [][]string {
+	return [][]string{{e.Dir, e.Coin, string(e.Network), e.Address, e.Serial, e.Firmware, strconv.FormatBool(e.Unsealed)}}
+}
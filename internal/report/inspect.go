@@ -0,0 +1,75 @@
+package report
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/timchurchard/opendime-utils/internal"
+)
+
+// VolumeFile a file on the Opendime volume and whether its addresses agree with verify.txt
+type VolumeFile struct {
+	Name      string   `json:"name"`
+	Addresses []string `json:"addresses"`
+	Agrees    bool     `json:"agrees"`
+}
+
+// Inspect the output of inspect
+type Inspect struct {
+	MountPoint     string       `json:"mountPoint"`
+	Address        string       `json:"address"`
+	Statement      *Statement   `json:"statement"`
+	Unsealed       bool         `json:"unsealed"`
+	SignatureValid bool         `json:"signatureValid"`
+	Files          []VolumeFile `json:"files"`
+	// PrivateKeyAgrees only set if private-key.txt had a WIF to check
+	PrivateKeyAgrees *bool    `json:"privateKeyAgrees,omitempty"`
+	Problems         []string `json:"problems"`
+}
+
+// NewInspect makes the report of an inspection
+func NewInspect(inspection internal.Inspection) Inspect {
+	result := Inspect{
+		MountPoint:     inspection.MountPoint,
+		Address:        inspection.Address,
+		Statement:      NewStatement(inspection.Statement),
+		Unsealed:       inspection.Unsealed,
+		SignatureValid: inspection.SignatureValid,
+		Files:          make([]VolumeFile, 0, len(inspection.Files)),
+		Problems:       append([]string{}, inspection.Problems...),
+	}
+
+	for _, file := range inspection.Files {
+		result.Files = append(result.Files, VolumeFile{Name: file.Name, Addresses: append([]string{}, file.Addresses...), Agrees: file.Agrees})
+	}
+
+	if inspection.PrivateKeyChecked {
+		agrees := inspection.PrivateKeyAgrees
+		result.PrivateKeyAgrees = &agrees
+	}
+
+	return result
+}
+
+// Header implements Table
+func (i Inspect) Header() []string {
+	return []string{"mount_point", "address", "serial", "firmware", "coin", "unsealed", "signature_valid", "private_key_agrees", "problems"}
+}
+
+// Rows implements Table, problems are joined with ;
+func (i Inspect) Rows() [][]string {
+	var serial, firmware, coin, privateKeyAgrees string
+
+	if i.Statement != nil {
+		serial, firmware, coin = i.Statement.Serial, i.Statement.Firmware, i.Statement.Coin
+	}
+
+	if i.PrivateKeyAgrees != nil {
+		privateKeyAgrees = strconv.FormatBool(*i.PrivateKeyAgrees)
+	}
+
+	return [][]string{{
+		i.MountPoint, i.Address, serial, firmware, coin, strconv.FormatBool(i.Unsealed), strconv.FormatBool(i.SignatureValid),
+		privateKeyAgrees, strings.Join(i.Problems, "; "),
+	}}
+}
@@ -0,0 +1,63 @@
+package report
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/internal/inventory"
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// Inventory records of the inventory, the output of inventory list and export
+type Inventory []inventory.Record
+
+// MarshalJSON writes an empty inventory as [] rather than null
+func (i Inventory) MarshalJSON() ([]byte, error) {
+	if i == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal([]inventory.Record(i))
+}
+
+// Header implements Table, a column per registered address type
+func (i Inventory) Header() []string {
+	header := []string{"serial", "label", "network", "address", "public_key", "firmware", "added"}
+	for _, addressType := range pkg.AddressTypes() {
+		header = append(header, addressType.ID)
+	}
+
+	return header
+}
+
+// Rows implements Table, one row per record
+func (i Inventory) Rows() [][]string {
+	rows := make([][]string, 0, len(i))
+
+	for _, record := range i {
+		row := []string{record.Serial, record.Label, string(record.Network), record.Address, record.PublicKeyHex,
+			record.Firmware, record.Added.Format(time.RFC3339)}
+		for _, addressType := range pkg.AddressTypes() {
+			row = append(row, record.Addresses[addressType.ID])
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// Record one record, the output of inventory add, show and remove
+type Record struct {
+	inventory.Record
+}
+
+// Header implements Table
+func (r Record) Header() []string {
+	return Inventory{}.Header()
+}
+
+// Rows implements Table
+func (r Record) Rows() [][]string {
+	return Inventory{r.Record}.Rows()
+}
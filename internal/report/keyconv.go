@@ -0,0 +1,42 @@
+package report
+
+// Key a private key formatted for an address type
+type Key struct {
+	ID    string `json:"id"`
+	Coin  string `json:"coin"`
+	Type  string `json:"type,omitempty"`
+	Label string `json:"label"`
+	Key   string `json:"key"`
+}
+
+// Keyconv the output of keyconv
+type Keyconv struct {
+	WIF string `json:"wif"`
+	// Coin using the WIF prefix, every coin eg Bitcoin/Litecoin on testnet when they sign with different magics
+	Coin       string `json:"coin"`
+	Compressed bool   `json:"compressed"`
+	// SecretExponent hex, only with verbose
+	SecretExponent string    `json:"secretExponent,omitempty"`
+	Keys           []Key     `json:"keys"`
+	Addresses      []Address `json:"addresses,omitempty"`
+}
+
+// Header implements Table
+func (k Keyconv) Header() []string {
+	return append([]string{"kind", "key"}, addressHeader...)
+}
+
+// Rows implements Table, a key row per address type followed by an address row per derived address
+func (k Keyconv) Rows() [][]string {
+	rows := make([][]string, 0, len(k.Keys)+len(k.Addresses))
+
+	for _, key := range k.Keys {
+		rows = append(rows, append([]string{"key", key.Key}, Address{ID: key.ID, Coin: key.Coin, Type: key.Type, Label: key.Label}.cells()...))
+	}
+
+	for _, address := range k.Addresses {
+		rows = append(rows, append([]string{"address", ""}, address.cells()...))
+	}
+
+	return rows
+}
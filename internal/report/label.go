@@ -0,0 +1,31 @@
+package report
+
+import "strconv"
+
+// LabelFile a label written by label
+type LabelFile struct {
+	File    string `json:"file"`
+	QRCodes int    `json:"qrCodes"`
+}
+
+// Label the output of label
+type Label struct {
+	Title string      `json:"title"`
+	Files []LabelFile `json:"files"`
+}
+
+// Header implements Table
+func (l Label) Header() []string {
+	return []string{"title", "file", "qr_codes"}
+}
+
+// Rows implements Table, one row per file written
+func (l Label) Rows() [][]string {
+	rows := make([][]string, 0, len(l.Files))
+
+	for _, file := range l.Files {
+		rows = append(rows, []string{l.Title, file.File, strconv.Itoa(file.QRCodes)})
+	}
+
+	return rows
+}
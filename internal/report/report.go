@@ -0,0 +1,79 @@
+// Package report has the machine readable output of every command. Field names are part of the interface scripts
+// depend on so they must not change
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Format output format
+type Format string
+
+// Output formats. Text is the original human readable output written by each command
+const (
+	Text Format = "text"
+	JSON Format = "json"
+	CSV  Format = "csv"
+	YAML Format = "yaml"
+)
+
+// ParseFormat returns the format for text, json, csv or yaml
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(name); format {
+	case Text, JSON, CSV, YAML:
+		return format, nil
+	}
+
+	return Text, fmt.Errorf("unknown format '%s' must be text, json, csv or yaml", name)
+}
+
+// Table a report that can be written as csv, one header and any number of rows
+type Table interface {
+	Header() []string
+	Rows() [][]string
+}
+
+// Error a failure reported in the same format as the successful output
+type Error struct {
+	Error string `json:"error"`
+}
+
+// Header implements Table
+func (e Error) Header() []string {
+	return []string{"error"}
+}
+
+// Rows implements Table
+func (e Error) Rows() [][]string {
+	return [][]string{{e.Error}}
+}
+
+// Write writes the report in the format. Text is not handled here, the commands print it themselves
+func Write(w io.Writer, format Format, report Table) error {
+	switch format {
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(report)
+	case YAML:
+		return writeYAML(w, report)
+	case CSV:
+		writer := csv.NewWriter(w)
+
+		if err := writer.Write(report.Header()); err != nil {
+			return err
+		}
+
+		if err := writer.WriteAll(report.Rows()); err != nil {
+			return err
+		}
+
+		return writer.Error()
+	}
+
+	return fmt.Errorf("format '%s' is not machine readable", format)
+}
@@ -0,0 +1,196 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/internal/history"
+	"github.com/timchurchard/opendime-utils/internal/inventory"
+)
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range []string{"text", "json", "csv", "yaml"} {
+		if format, err := ParseFormat(name); err != nil || string(format) != name {
+			t.Errorf("ParseFormat(%s) = %v, %v", name, format, err)
+		}
+	}
+
+	if _, err := ParseFormat("xml"); err == nil || err.Error() != "unknown format 'xml' must be text, json, csv or yaml" {
+		t.Errorf("ParseFormat(xml) error = %v", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	buildTime := time.Date(2019, 2, 7, 13, 2, 55, 0, time.UTC)
+	spends := 0
+
+	sigtoaddr := Sigtoaddr{
+		Verified: Verified{Address: "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR", Network: "mainnet", Valid: true, AddressType: "BitcoinP2PKH"},
+		Statement: &Statement{
+			Nonce: "1675bf38ec241a2308585ad0", Serial: "DDRRNOCZJRIFCIBAEBJDOJQY74", Firmware: "2.4.0", BuildTime: &buildTime,
+			Coin: "BTC",
+		},
+		Opendime: "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR",
+		Addresses: []Address{
+			{
+				ID: "BitcoinP2WPKH", Coin: "Bitcoin", Type: "P2WPKH", Label: "Bitcoin P2WPKH", Address: "bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5",
+				Balance: &Balance{Amount: 0.001, Value: 65.5, Currency: "usd"}, Spends: &spends,
+			},
+			{ID: "Ethereum", Coin: "Ethereum", Label: "Ethereum", Address: "0x76270d9D9afC0cf4EbfFBafE6401E01cb0F021Ce", SpendError: "offline"},
+		},
+		Verdict: NewVerdict(&history.Verdict{Level: history.Warn, Reasons: []string{"nonce seen: re-plug"}}),
+	}
+
+	tests := []struct {
+		name   string
+		format Format
+		report Table
+		want   string
+	}{
+		{"error json", JSON, Error{Error: "File 'x' not found"}, "{\n  \"error\": \"File 'x' not found\"\n}\n"},
+		{"error csv", CSV, Error{Error: "File 'x' not found"}, "error\nFile 'x' not found\n"},
+		{"error yaml", YAML, Error{Error: "File 'x' not found"}, "error: \"File 'x' not found\"\n"},
+		{"sigtoaddr csv", CSV, sigtoaddr, "opendime,id,coin,type,label,address,amount,value,currency,spends,verdict\n" +
+			"1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR,BitcoinP2WPKH,Bitcoin,P2WPKH,Bitcoin P2WPKH,bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5,0.00100000,65.50,usd,0,WARN\n" +
+			"1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR,Ethereum,Ethereum,,Ethereum,0x76270d9D9afC0cf4EbfFBafE6401E01cb0F021Ce,,,,,WARN\n"},
+		{"sigtoaddr no addresses csv", CSV, Sigtoaddr{Opendime: "bc1p"}, "opendime,id,coin,type,label,address,amount,value,currency,spends,verdict\nbc1p,,,,,,,,,,\n"},
+		{"sigtoaddr yaml", YAML, sigtoaddr, `verified:
+  address: "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR"
+  network: mainnet
+  valid: true
+  addressType: BitcoinP2PKH
+statement:
+  nonce: "1675bf38ec241a2308585ad0"
+  serial: DDRRNOCZJRIFCIBAEBJDOJQY74
+  firmware: "2.4.0"
+  buildTime: "2019-02-07T13:02:55Z"
+  coin: BTC
+  unsealed: false
+opendime: "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR"
+addresses:
+  - id: BitcoinP2WPKH
+    coin: Bitcoin
+    type: P2WPKH
+    label: "Bitcoin P2WPKH"
+    address: bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5
+    balance:
+      amount: 0.001
+      value: 65.5
+      currency: usd
+    spends: 0
+  - id: Ethereum
+    coin: Ethereum
+    label: Ethereum
+    address: "0x76270d9D9afC0cf4EbfFBafE6401E01cb0F021Ce"
+    spendError: offline
+verdict:
+  level: WARN
+  reasons:
+    - "nonce seen: re-plug"
+`},
+		{"keyconv yaml empty", YAML, Keyconv{WIF: "5K", Coin: "Bitcoin", Keys: []Key{}}, "wif: \"5K\"\ncoin: Bitcoin\ncompressed: false\nkeys: []\n"},
+		{"keyconv csv", CSV, Keyconv{
+			Keys:      []Key{{ID: "Ethereum", Coin: "Ethereum", Label: "Ethereum", Key: "0xdc19"}},
+			Addresses: []Address{{ID: "Ethereum", Coin: "Ethereum", Label: "Ethereum", Address: "0xCb19"}},
+		}, "kind,key,id,coin,type,label,address,amount,value,currency,spends\nkey,0xdc19,Ethereum,Ethereum,,Ethereum,,,,,\naddress,,Ethereum,Ethereum,,Ethereum,0xCb19,,,,\n"},
+		{"crypt csv", CSV, Crypt{Operation: Decrypt, Output: "hello, world", Encoding: "text"}, "operation,address,output,encoding,file\ndecrypt,,\"hello, world\",text,\n"},
+		{"sign csv", CSV, Sign{Coin: "Bitcoin", Address: "1A", Message: "hi", Signature: "H0", File: "verify.txt"},
+			"coin,address,message,signature,signed_message,file\nBitcoin,1A,hi,H0,,verify.txt\n"},
+		{"accept csv", CSV, Accept{Currency: "usd", Failed: 1, Opendimes: []Accepted{
+			{VerifyTxt: "a/verify.txt", Opendime: "1A", Serial: "S1", Value: 65.5, Unchecked: 11, Verdict: &Verdict{Level: "WARN"}, Accepted: true},
+			{VerifyTxt: "b/verify.txt", Verdict: &Verdict{Level: "FAIL"}},
+		}}, "verify_txt,opendime,serial,value,currency,unchecked,spend_unchecked,verdict,accepted\n" +
+			"a/verify.txt,1A,S1,65.50,usd,11,0,WARN,true\nb/verify.txt,,,0.00,usd,0,0,FAIL,false\n"},
+		{"whois csv", CSV, Whois{Address: "ltc1q", Matches: []WhoisMatch{{Address: "ltc1q", Opendime: "S1", Coin: "Litecoin", Type: "Litecoin P2WPKH", Source: "inventory"}}},
+			"address,opendime,label,coin,type,source\nltc1q,S1,,Litecoin,Litecoin P2WPKH,inventory\n"},
+		{"label csv", CSV, Label{Title: "Tips", Files: []LabelFile{{File: "label.svg", QRCodes: 15}}}, "title,file,qr_codes\nTips,label.svg,15\n"},
+		{"inventory json empty", JSON, Inventory(nil), "[]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+
+			if err := Write(out, tt.format, tt.report); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			if got := out.String(); got != tt.want {
+				t.Errorf("Write() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := Write(&bytes.Buffer{}, Text, Error{}); err == nil {
+		t.Errorf("Write(text) expected an error")
+	}
+
+	// The json has the same field names as the yaml
+	out := &bytes.Buffer{}
+	if err := Write(out, JSON, sigtoaddr); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var decoded struct {
+		Addresses []struct {
+			Balance struct {
+				Amount float64 `json:"amount"`
+			} `json:"balance"`
+			Spends *int `json:"spends"`
+		} `json:"addresses"`
+		Verdict struct {
+			Level string `json:"level"`
+		} `json:"verdict"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || decoded.Addresses[0].Balance.Amount != 0.001 ||
+		*decoded.Addresses[0].Spends != 0 || decoded.Addresses[1].Spends != nil || decoded.Verdict.Level != "WARN" {
+		t.Errorf("Write(json) = %s, %v", out.String(), err)
+	}
+}
+
+func TestInventory(t *testing.T) {
+	record := inventory.Record{
+		Serial: "S1", Network: "mainnet", Address: "1A", Addresses: map[string]string{"BitcoinP2PKH": "1A"},
+		Added: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	out := &bytes.Buffer{}
+	if err := Write(out, CSV, Inventory{record}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "serial,label,network,address,public_key,firmware,added,BitcoinP2PKH,") ||
+		!strings.HasPrefix(lines[1], "S1,,mainnet,1A,,,2024-05-01T12:00:00Z,1A,") {
+		t.Errorf("Write(csv) = %s", out.String())
+	}
+
+	// A single record is the record's fields, not wrapped
+	out.Reset()
+	if err := Write(out, JSON, Record{record}); err != nil || !strings.HasPrefix(out.String(), "{\n  \"serial\": \"S1\",") {
+		t.Errorf("Write(json) = %s, %v", out.String(), err)
+	}
+}
+
+func TestCryptSetOutput(t *testing.T) {
+	tests := []struct {
+		operation    string
+		data         []byte
+		wantOutput   string
+		wantEncoding string
+	}{
+		{Encrypt, []byte("hello"), "aGVsbG8=", "base64"},
+		{Decrypt, []byte("hello"), "hello", "text"},
+		{Decrypt, []byte{0xff, 0x00}, "/wA=", "base64"},
+	}
+	for _, tt := range tests {
+		result := Crypt{Operation: tt.operation}
+		result.SetOutput(tt.data)
+
+		if result.Output != tt.wantOutput || result.Encoding != tt.wantEncoding {
+			t.Errorf("SetOutput(%v) = %s %s, want %s %s", tt.data, result.Output, result.Encoding, tt.wantOutput, tt.wantEncoding)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package report
+
+// Sign the output of sign
+type Sign struct {
+	Coin      string `json:"coin"`
+	Address   string `json:"address"`
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+	// SignedMessage the verify.txt style signed message, empty if it was written to File
+	SignedMessage string `json:"signedMessage,omitempty"`
+	File          string `json:"file,omitempty"`
+}
+
+// Header implements Table
+func (s Sign) Header() []string {
+	return []string{"coin", "address", "message", "signature", "signed_message", "file"}
+}
+
+// Rows implements Table
+func (s Sign) Rows() [][]string {
+	return [][]string{{s.Coin, s.Address, s.Message, s.Signature, s.SignedMessage, s.File}}
+}
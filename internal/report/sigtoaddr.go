@@ -0,0 +1,39 @@
+package report
+
+// Sigtoaddr the output of sigtoaddr
+type Sigtoaddr struct {
+	Verified  Verified   `json:"verified"`
+	Statement *Statement `json:"statement,omitempty"`
+	// Opendime the address the message was signed with
+	Opendime              string    `json:"opendime"`
+	PublicKeyCompressed   string    `json:"publicKeyCompressed,omitempty"`
+	PublicKeyUncompressed string    `json:"publicKeyUncompressed,omitempty"`
+	Addresses             []Address `json:"addresses"`
+	Verdict               *Verdict  `json:"verdict,omitempty"`
+	Warnings              []string  `json:"warnings,omitempty"`
+}
+
+// Header implements Table
+func (s Sigtoaddr) Header() []string {
+	return append(append([]string{"opendime"}, addressHeader...), "verdict")
+}
+
+// Rows implements Table, one row per derived address or a single row for the Opendime if there are none
+func (s Sigtoaddr) Rows() [][]string {
+	verdict := ""
+	if s.Verdict != nil {
+		verdict = s.Verdict.Level
+	}
+
+	addresses := s.Addresses
+	if len(addresses) == 0 {
+		addresses = []Address{{}}
+	}
+
+	rows := make([][]string, 0, len(addresses))
+	for _, address := range addresses {
+		rows = append(rows, append(append([]string{s.Opendime}, address.cells()...), verdict))
+	}
+
+	return rows
+}
@@ -0,0 +1,60 @@
+package report
+
+import "github.com/timchurchard/opendime-utils/internal/inventory"
+
+// WhoisMatch an Opendime that derived the address
+type WhoisMatch struct {
+	Address string `json:"address"`
+	// Opendime the serial or, for messages not signed by an Opendime, the address
+	Opendime string `json:"opendime"`
+	Label    string `json:"label,omitempty"`
+	Coin     string `json:"coin,omitempty"`
+	// Type label of the address type eg Bitcoin P2WPKH, or its ID if it is no longer registered
+	Type   string `json:"type"`
+	Source string `json:"source"`
+}
+
+// Whois the output of whois
+type Whois struct {
+	Address string       `json:"address"`
+	Matches []WhoisMatch `json:"matches"`
+}
+
+// NewWhois makes the report of the matches for address
+func NewWhois(address string, matches []inventory.Match) Whois {
+	result := Whois{Address: address, Matches: make([]WhoisMatch, 0, len(matches))}
+
+	for _, match := range matches {
+		whoisMatch := WhoisMatch{
+			Address:  match.Address,
+			Opendime: match.Record.Key(),
+			Label:    match.Record.Label,
+			Type:     match.AddressTypeID,
+			Source:   match.Source,
+		}
+
+		if match.AddressType.Coin != nil {
+			whoisMatch.Coin, whoisMatch.Type = match.AddressType.Coin.Name, match.AddressType.Label()
+		}
+
+		result.Matches = append(result.Matches, whoisMatch)
+	}
+
+	return result
+}
+
+// Header implements Table
+func (w Whois) Header() []string {
+	return []string{"address", "opendime", "label", "coin", "type", "source"}
+}
+
+// Rows implements Table, one row per match
+func (w Whois) Rows() [][]string {
+	rows := make([][]string, 0, len(w.Matches))
+
+	for _, match := range w.Matches {
+		rows = append(rows, []string{match.Address, match.Opendime, match.Label, match.Coin, match.Type, match.Source})
+	}
+
+	return rows
+}
@@ -0,0 +1,159 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// plainYAML strings that can be written without quotes. Anything that could be read back as a number, bool, null or
+// YAML syntax is quoted
+var (
+	plainYAML    = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_./-]*$`)
+	reservedYAML = map[string]bool{
+		"y": true, "n": true, "yes": true, "no": true, "on": true, "off": true, "true": true, "false": true,
+		"null": true, "nan": true, "inf": true,
+	}
+)
+
+// yamlNode a json value keeping the order of object keys
+type yamlNode struct {
+	scalar   string
+	isObject bool
+	isArray  bool
+	keys     []string
+	values   []*yamlNode
+}
+
+// writeYAML writes the report as YAML. It is marshalled as json first so the json field names (and omitempty) are
+// the YAML keys too
+func writeYAML(w io.Writer, report any) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	node, err := decodeYAMLNode(decoder)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	node.write(&b, 0, false)
+
+	_, err = io.WriteString(w, b.String())
+
+	return err
+}
+
+func decodeYAMLNode(decoder *json.Decoder) (*yamlNode, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &yamlNode{}
+
+	switch token := token.(type) {
+	case json.Delim:
+		node.isObject, node.isArray = token == '{', token == '['
+
+		for decoder.More() {
+			if node.isObject {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+
+				node.keys = append(node.keys, key.(string))
+			}
+
+			value, err := decodeYAMLNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			node.values = append(node.values, value)
+		}
+
+		// The closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		node.scalar = quoteYAML(token)
+	case json.Number:
+		node.scalar = token.String()
+	case bool:
+		node.scalar = fmt.Sprint(token)
+	case nil:
+		node.scalar = "null"
+	}
+
+	return node, nil
+}
+
+// inline returns true if the node is written on the same line as its key
+func (n *yamlNode) inline() bool {
+	return len(n.values) == 0
+}
+
+// write writes the node at the indent. With continued the first line is already indented, it follows the "- " of an
+// array item
+func (n *yamlNode) write(b *strings.Builder, indent int, continued bool) {
+	pad := strings.Repeat("  ", indent)
+
+	switch {
+	case n.isObject && len(n.values) == 0:
+		b.WriteString("{}\n")
+	case n.isArray && len(n.values) == 0:
+		b.WriteString("[]\n")
+	case n.isObject:
+		for idx, key := range n.keys {
+			if idx > 0 || !continued {
+				b.WriteString(pad)
+			}
+
+			n.values[idx].writeValue(b, quoteYAML(key)+":", indent)
+		}
+	case n.isArray:
+		for _, value := range n.values {
+			b.WriteString(pad)
+			value.writeValue(b, "-", indent)
+		}
+	default:
+		b.WriteString(n.scalar + "\n")
+	}
+}
+
+// writeValue writes the value after the prefix, a key or the "-" of an array item
+func (n *yamlNode) writeValue(b *strings.Builder, prefix string, indent int) {
+	switch {
+	case n.inline():
+		b.WriteString(prefix + " ")
+		n.write(b, indent, true)
+	case n.isObject && prefix == "-":
+		b.WriteString("- ")
+		n.write(b, indent+1, true)
+	default:
+		b.WriteString(prefix + "\n")
+		n.write(b, indent+1, false)
+	}
+}
+
+// quoteYAML returns s plain if it is safe or as a double quoted (json) string
+func quoteYAML(s string) string {
+	if plainYAML.MatchString(s) && !reservedYAML[strings.ToLower(s)] {
+		return s
+	}
+
+	quoted, _ := json.Marshal(s)
+
+	return string(quoted)
+}
@@ -0,0 +1,162 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// ethereumRPCURL public Ethereum JSON-RPC endpoint used for the account nonce
+var ethereumRPCURL = "https://ethereum-rpc.publicnode.com"
+
+// spentThrottle pause between addresses to reduce hammering on public/free APIs. A variable so tests can skip it
+var spentThrottle = time.Second / 3
+
+// SpendCheck the spend history of one derived address
+type SpendCheck struct {
+	Derived pkg.DerivedAddress
+	// Spends number of times coins were sent from the address (spent outputs, or the Ethereum account nonce)
+	Spends int
+	// Err the spend history could not be fetched eg there is no public API for the coin on the network
+	Err error
+}
+
+// CheckSpent returns how many times coins were sent from the address. A sealed Opendime has never signed a
+// transaction so any spend means the private key has been exposed
+func CheckSpent(network pkg.Network, address string) (int, error) {
+	currency, err := addressCurrency(network, address)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case currency == "btc" && network != pkg.RegTest:
+		return mempoolGetSpends(network, address)
+	case currency == "ltc" && network == pkg.MainNet:
+		return bitlapsGetSpends(currency, address)
+	case currency == "ltc" && network == pkg.TestNet:
+		return bitlapsGetSpends(currency+"/testnet", address)
+	case currency == "eth" && network == pkg.MainNet:
+		return ethereumGetNonce(address)
+	case currency == "doge" && network == pkg.MainNet:
+		return dogechainGetSpends(address)
+	}
+
+	return 0, fmt.Errorf("no spend history API for %s on %s", currency, network)
+}
+
+// CheckAddressesSpent checks the spend history of every derived address, in the order of addresses.Derived
+func CheckAddressesSpent(network pkg.Network, addresses pkg.Addresses) []SpendCheck {
+	checks := make([]SpendCheck, 0, len(addresses.Derived))
+
+	for _, derived := range addresses.Derived {
+		spends, err := CheckSpent(network, derived.Address)
+		checks = append(checks, SpendCheck{Derived: derived, Spends: spends, Err: err})
+
+		if err == nil {
+			time.Sleep(spentThrottle)
+		}
+	}
+
+	return checks
+}
+
+func mempoolGetSpends(network pkg.Network, address string) (int, error) {
+	result, err := mempoolGetAddress(network, address)
+	if err != nil {
+		if network != pkg.TestNet {
+			return 0, err
+		}
+
+		return bitlapsGetSpends("btc/testnet", address)
+	}
+
+	return result.ChainStats.SpentTxoCount + result.MempoolStats.SpentTxoCount, nil
+}
+
+func bitlapsGetSpends(currency, address string) (int, error) {
+	state, err := bitlapsGetAddressState(currency, address)
+	if err != nil {
+		return 0, err
+	}
+
+	return state.Data.SentTxCount + state.Data.PendingSentTxCount, nil
+}
+
+// ethereumGetNonce the account nonce is the number of transactions sent from the address
+func ethereumGetNonce(address string) (int, error) {
+	type rpcResp struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	request, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "eth_getTransactionCount",
+		"params":  []string{address, "latest"},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	resultBytes, err := httpPost(ethereumRPCURL, "application/json", request)
+	if err != nil {
+		return 0, err
+	}
+
+	result := rpcResp{}
+
+	err = json.Unmarshal(resultBytes, &result)
+	if err != nil {
+		return 0, err
+	}
+
+	if result.Error != nil {
+		return 0, errors.New(result.Error.Message)
+	}
+
+	nonce, err := strconv.ParseInt(result.Result, 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("nonce '%s' malformed: %w", result.Result, err)
+	}
+
+	return int(nonce), nil
+}
+
+// dogechainGetSpends dogechain only reports the amount sent so any amount counts as one spend
+func dogechainGetSpends(address string) (int, error) {
+	type sentData struct {
+		Sent    string `json:"sent"`
+		Success int
+	}
+
+	sentBytes, err := httpGet("https://dogechain.info/api/v1/address/sent/" + address)
+	if err != nil {
+		return 0, err
+	}
+
+	sentBody := sentData{}
+	err = json.Unmarshal(sentBytes, &sentBody)
+	if err != nil {
+		return 0, err
+	}
+
+	sentFloat, err := strconv.ParseFloat(sentBody.Sent, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if sentFloat > 0 {
+		return 1, nil
+	}
+
+	return 0, nil
+}
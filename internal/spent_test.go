@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+func TestCheckAddressesSpent(t *testing.T) {
+	const (
+		mempoolFreshResp = `{"address":"1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR","chain_stats":{"funded_txo_count":1,"funded_txo_sum":100000,"spent_txo_count":0,"spent_txo_sum":0,"tx_count":1},"mempool_stats":{"funded_txo_count":0,"funded_txo_sum":0,"spent_txo_count":0,"spent_txo_sum":0,"tx_count":0}}`
+		mempoolSpentResp = `{"address":"bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5","chain_stats":{"funded_txo_count":2,"funded_txo_sum":200000,"spent_txo_count":2,"spent_txo_sum":200000,"tx_count":3},"mempool_stats":{"funded_txo_count":0,"funded_txo_sum":0,"spent_txo_count":1,"spent_txo_sum":0,"tx_count":1}}`
+		bitapsResp       = `{"data":{"balance":0,"receivedAmount":100000,"receivedTxCount":1,"sentAmount":100000,"sentTxCount":1,"pendingSentTxCount":0},"time":0.001}`
+		ethereumRPCResp  = `{"jsonrpc":"2.0","id":1,"result":"0x5"}`
+		dogechainResp    = `{"sent":"0","success":1}`
+	)
+
+	oldThrottle := spentThrottle
+	defer func() { spentThrottle = oldThrottle }()
+	spentThrottle = 0
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "https://mempool.space/api/address/1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR",
+		httpmock.NewStringResponder(200, mempoolFreshResp))
+	httpmock.RegisterResponder("GET", "https://mempool.space/api/address/bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5",
+		httpmock.NewStringResponder(200, mempoolSpentResp))
+	httpmock.RegisterResponder("GET", "https://api.bitaps.com/ltc/v1/blockchain/address/state/LfzdHsHSPx3pxXrsvN9nviMaQeejdnT81s",
+		httpmock.NewStringResponder(200, bitapsResp))
+	httpmock.RegisterResponder("POST", ethereumRPCURL,
+		httpmock.NewStringResponder(200, ethereumRPCResp))
+	httpmock.RegisterResponder("GET", "https://dogechain.info/api/v1/address/sent/DRumZuvFchi4EjMKUpA4CTTR5a1kpHqQXH",
+		httpmock.NewStringResponder(200, dogechainResp))
+
+	addresses := pkg.Addresses{Derived: []pkg.DerivedAddress{
+		{ID: "BitcoinP2PKH", Address: "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR"},
+		{ID: "BitcoinP2WPKH", Address: "bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5"},
+		{ID: "LitecoinP2PKH", Address: "LfzdHsHSPx3pxXrsvN9nviMaQeejdnT81s"},
+		{ID: "Ethereum", Address: "0x76270d9D9afC0cf4EbfFBafE6401E01cb0F021Ce"},
+		{ID: "DogecoinP2PKH", Address: "DRumZuvFchi4EjMKUpA4CTTR5a1kpHqQXH"},
+		{ID: "BitcoinCashP2PKH", Address: "bitcoincash:qr3a2ed6zc7xmjrr580esu8a9vdkskml2vu3vxcuuy"},
+	}}
+
+	tests := []struct {
+		id      string
+		want    int
+		wantErr bool
+	}{
+		{"BitcoinP2PKH", 0, false},
+		{"BitcoinP2WPKH", 3, false},
+		{"LitecoinP2PKH", 1, false},
+		{"Ethereum", 5, false},
+		{"DogecoinP2PKH", 0, false},
+		{"BitcoinCashP2PKH", 0, true},
+	}
+
+	checks := CheckAddressesSpent(pkg.MainNet, addresses)
+	if len(checks) != len(tests) {
+		t.Fatalf("CheckAddressesSpent() = %d checks, want %d", len(checks), len(tests))
+	}
+
+	for idx, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			check := checks[idx]
+
+			if check.Derived.ID != tt.id || check.Spends != tt.want || (check.Err != nil) != tt.wantErr {
+				t.Errorf("CheckAddressesSpent() = %s %d %v, want %s %d wantErr %v", check.Derived.ID, check.Spends, check.Err,
+					tt.id, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := CheckSpent(pkg.RegTest, "bcrt1qzeapyvz7kl7v5vj865rahts2jjcdz0ssvhnsna"); err == nil {
+		t.Errorf("CheckSpent() regtest no error")
+	}
+}
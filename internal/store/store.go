@@ -0,0 +1,165 @@
+// Package store keeps a versioned JSON document on disk. Writes go to a temp file that is renamed over the
+// original so readers never see a partial file, and a lock on a lock file (flock or LockFileEx) serialises writers
+// across processes
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// lockTimeout how long to wait for another writer before giving up
+	lockTimeout = 10 * time.Second
+	lockPoll    = 10 * time.Millisecond
+)
+
+// ErrLocked another writer held the lock for longer than lockTimeout
+var ErrLocked = errors.New("store is locked by another writer")
+
+// envelope the on-disk format
+type envelope struct {
+	Version int             `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Store a JSON document at Path with format Version. Loading a document with a different version is an error
+type Store struct {
+	Path    string
+	Version int
+
+	mu sync.Mutex
+}
+
+// New returns a Store for path
+func New(path string, version int) *Store {
+	return &Store{Path: path, Version: version}
+}
+
+// Load decodes the document into v. A missing file leaves v unchanged
+func (s *Store) Load(v any) error {
+	contents, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var doc envelope
+	if err := json.Unmarshal(contents, &doc); err != nil {
+		return fmt.Errorf("store %s malformed: %w", s.Path, err)
+	}
+
+	if doc.Version != s.Version {
+		return fmt.Errorf("store %s is version %d, expected version %d", s.Path, doc.Version, s.Version)
+	}
+
+	if len(doc.Data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(doc.Data, v)
+}
+
+// Update loads the document into v, calls fn to change it and saves v. The whole read-modify-write holds the lock
+// so concurrent updates (from goroutines or other processes) are not lost. Nothing is saved if fn returns an error
+func (s *Store) Update(v any, fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := s.Load(v); err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	return s.save(v)
+}
+
+func (s *Store) save(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	contents, err := json.MarshalIndent(envelope{Version: s.Version, Data: data}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(contents, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+// lock takes an exclusive lock on the lock file next to the store, waiting for other writers. The operating system
+// releases the lock when a writer exits, so a crashed writer never leaves the store locked
+func (s *Store) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o700); err != nil {
+		return nil, err
+	}
+
+	lockPath := s.Path + ".lock"
+
+	// The lock file is never removed, removing it would let a waiter lock a file another writer no longer uses
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		locked, err := tryLockFile(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+
+		if locked {
+			return func() {
+				unlockFile(file)
+				file.Close()
+			}, nil
+		}
+
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("%w: %s", ErrLocked, lockPath)
+		}
+
+		time.Sleep(lockPoll)
+	}
+}
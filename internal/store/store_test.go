@@ -0,0 +1,115 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestStore_Update(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "store.json")
+
+	var values []string
+	if err := New(path, 1).Load(&values); err != nil || values != nil {
+		t.Fatalf("Load() missing file = %v, %v", values, err)
+	}
+
+	// Many writers with their own Store (like separate processes) must not lose updates
+	const writers = 20
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var values []string
+			err := New(path, 1).Update(&values, func() error {
+				values = append(values, fmt.Sprintf("value%d", i))
+				return nil
+			})
+			if err != nil {
+				t.Errorf("Update() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := New(path, 1).Load(&values); err != nil || len(values) != writers {
+		t.Errorf("Load() = %d values, %v want %d", len(values), err, writers)
+	}
+
+	// A failed update is not saved
+	wantErr := errors.New("no thanks")
+	err := New(path, 1).Update(&values, func() error {
+		values = nil
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Errorf("Update() error = %v, want %v", err, wantErr)
+	}
+
+	values = nil
+	if err := New(path, 1).Load(&values); err != nil || len(values) != writers {
+		t.Errorf("Load() after failed update = %d values, %v", len(values), err)
+	}
+
+	// Only the store and its lock file remain
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 2 {
+		t.Errorf("store left temp files: %v", entries)
+	}
+}
+
+func TestStore_Version(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	var values []string
+	if err := New(path, 2).Update(&values, func() error { values = []string{"a"}; return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	err := New(path, 1).Load(&values)
+	if err == nil || err.Error() != fmt.Sprintf("store %s is version 2, expected version 1", path) {
+		t.Errorf("Load() error = %v", err)
+	}
+}
+
+func TestStore_Lock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	// A lock file left by a crashed writer is not locked
+	if err := os.WriteFile(path+".lock", []byte("1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var values []string
+	if err := New(path, 1).Update(&values, func() error { return nil }); err != nil {
+		t.Errorf("Update() with left over lock file error = %v", err)
+	}
+
+	// While a writer holds the lock nobody else can take it
+	unlock, err := New(path, 1).lock()
+	if err != nil {
+		t.Fatalf("lock() error = %v", err)
+	}
+
+	other, err := os.OpenFile(path+".lock", os.O_RDWR, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+
+	if locked, err := tryLockFile(other); locked || err != nil {
+		t.Errorf("tryLockFile() while locked = %v, %v", locked, err)
+	}
+
+	unlock()
+
+	if locked, err := tryLockFile(other); !locked || err != nil {
+		t.Errorf("tryLockFile() after unlock = %v, %v", locked, err)
+	}
+	unlockFile(other)
+}
@@ -0,0 +1,217 @@
+package internal
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// volumeFiles the files cross checked for the address, in report order
+var volumeFiles = []string{pkg.AddressTxtName, pkg.ReadmeTxtName, pkg.IndexHtmName, pkg.VersionTxtPath, pkg.PrivateKeyTxtName}
+
+var addressTokenRe = regexp.MustCompile(`[A-Za-z0-9]{25,90}`)
+
+// VolumeFile the addresses one file on the volume mentions
+type VolumeFile struct {
+	// Name path relative to the mount point eg address.txt
+	Name string
+	// Addresses every address mentioned in the file
+	Addresses []string
+	// Agrees true if every address mentioned is the Opendime address
+	Agrees bool
+}
+
+// Inspection report for an Opendime volume
+type Inspection struct {
+	MountPoint string
+	// Address from verify.txt
+	Address string
+	// Statement the parsed verify.txt message (serial, firmware, coin etc)
+	Statement pkg.OpendimeStatement
+	// Unsealed true if the message has the UNSEALED banner or private-key.txt exists
+	Unsealed       bool
+	SignatureValid bool
+	// Files present on the volume, missing files are left out
+	Files []VolumeFile
+	// PrivateKeyChecked true if private-key.txt had a WIF, PrivateKeyAgrees if it derives Address
+	PrivateKeyChecked bool
+	PrivateKeyAgrees  bool
+	// Problems every disagreement found, empty if the volume is consistent
+	Problems []string
+}
+
+// OK returns true if no problems were found
+func (i Inspection) OK() bool {
+	return len(i.Problems) == 0
+}
+
+// InspectVolume reads the files on the Opendime mounted at dir and cross checks them. An error is only returned if
+// verify.txt can not be read, any disagreement is reported in Problems
+func InspectVolume(network pkg.Network, dir string) (Inspection, error) {
+	inspection := Inspection{MountPoint: dir}
+
+	address, signature, message, err := pkg.ParseVerifyTxt(filepath.Join(dir, pkg.VerifyTxtPath))
+	if err != nil {
+		return inspection, err
+	}
+
+	inspection.Address = address
+
+	inspection.Statement, err = pkg.ParseStatement(message)
+	if err != nil {
+		inspection.problem("verify.txt: %v", err)
+	}
+	unsealedBanner := inspection.Statement.Unsealed
+
+	verifiedMessage, err := pkg.VerifyMessage(network, address, signature, message)
+	if err != nil {
+		inspection.problem("verify.txt signature invalid: %v", err)
+	} else {
+		inspection.SignatureValid = true
+
+		if err := inspection.Statement.CoinMismatch(verifiedMessage); err != nil {
+			inspection.problem("verify.txt: %v", err)
+		}
+	}
+
+	for _, name := range volumeFiles {
+		contents, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			if name == pkg.AddressTxtName {
+				inspection.problem("%s missing", name)
+			}
+			continue
+		}
+		if err != nil {
+			inspection.problem("%s unreadable: %v", name, err)
+			continue
+		}
+
+		file := VolumeFile{Name: name, Addresses: findAddresses(string(contents)), Agrees: true}
+		for _, mentioned := range file.Addresses {
+			if !strings.EqualFold(mentioned, address) {
+				file.Agrees = false
+				inspection.problem("%s mentions %s not %s", name, mentioned, address)
+			}
+		}
+
+		if name == pkg.AddressTxtName && len(file.Addresses) == 0 {
+			file.Agrees = false
+			inspection.problem("%s does not contain an address", name)
+		}
+
+		firmware := inspection.Statement.Firmware
+		if name == pkg.VersionTxtPath && firmware != "" && !strings.Contains(string(contents), firmware) {
+			inspection.problem("%s does not match firmware %s in verify.txt", name, firmware)
+		}
+
+		if name == pkg.PrivateKeyTxtName {
+			inspection.Unsealed = true
+			inspection.checkPrivateKey(network, string(contents))
+		}
+
+		inspection.Files = append(inspection.Files, file)
+	}
+
+	switch {
+	case inspection.Unsealed && !unsealedBanner:
+		inspection.problem("%s exists but verify.txt is not marked UNSEALED", pkg.PrivateKeyTxtName)
+	case unsealedBanner && !inspection.Unsealed:
+		inspection.Unsealed = true
+		inspection.problem("verify.txt is marked UNSEALED but %s is missing", pkg.PrivateKeyTxtName)
+	}
+
+	return inspection, nil
+}
+
+// checkPrivateKey finds the WIF in private-key.txt and checks it derives the Opendime address
+func (i *Inspection) checkPrivateKey(network pkg.Network, contents string) {
+	for _, token := range strings.Fields(contents) {
+		_, secretExponentHex, _, err := pkg.ValidateWif(network, token)
+		if err != nil {
+			continue
+		}
+
+		i.PrivateKeyChecked = true
+
+		secret, err := hex.DecodeString(secretExponentHex)
+		if err != nil {
+			i.problem("%s private key malformed: %v", pkg.PrivateKeyTxtName, err)
+			return
+		}
+
+		_, publicKey := btcec.PrivKeyFromBytes(secret)
+
+		addresses, err := pkg.GetAddresses(pkg.VerifiedMessage{
+			Address:      i.Address,
+			PublicKeyHex: hex.EncodeToString(publicKey.SerializeUncompressed()),
+			Network:      network,
+		})
+		if err != nil {
+			i.problem("%s unable to derive addresses: %v", pkg.PrivateKeyTxtName, err)
+			return
+		}
+
+		for _, derived := range addresses.Derived {
+			if strings.EqualFold(derived.Address, i.Address) {
+				i.PrivateKeyAgrees = true
+				return
+			}
+		}
+
+		i.problem("%s private key does not derive %s", pkg.PrivateKeyTxtName, i.Address)
+
+		return
+	}
+
+	i.problem("%s does not contain a WIF private key", pkg.PrivateKeyTxtName)
+}
+
+func (i *Inspection) problem(format string, a ...any) {
+	i.Problems = append(i.Problems, fmt.Sprintf(format, a...))
+}
+
+// findAddresses returns the distinct base58check (20 byte hash) and bech32 addresses in the text
+func findAddresses(text string) []string {
+	var addresses []string
+
+	for _, token := range addressTokenRe.FindAllString(text, -1) {
+		if !isAddressToken(token) {
+			continue
+		}
+
+		duplicate := false
+		for _, address := range addresses {
+			if strings.EqualFold(address, token) {
+				duplicate = true
+				break
+			}
+		}
+
+		if !duplicate {
+			addresses = append(addresses, token)
+		}
+	}
+
+	return addresses
+}
+
+func isAddressToken(token string) bool {
+	if hash, _, err := base58.CheckDecode(token); err == nil {
+		return len(hash) == 20
+	}
+
+	_, _, _, err := bech32.DecodeGeneric(token)
+
+	return err == nil
+}
@@ -0,0 +1,180 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+const (
+	// unsealedWif compressed WIF for 1A9GW5SxA4qW5ZhymW1uPaj6N4vnVJVWZW
+	unsealedWif     = "L4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ"
+	unsealedAddress = "1A9GW5SxA4qW5ZhymW1uPaj6N4vnVJVWZW"
+	unsealedMessage = "UNSEALED -- UNSEALED -- UNSEALED\nNonce: 1675bf38ec241a2308585ad0  Serial: TESTSERIAL\nVersion: 2.4.0 time=20190207.130255 git=master@e233940e coin=BTC"
+)
+
+// writeVolume makes a fake Opendime at dir with the files (name to contents)
+func writeVolume(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, contents := range files {
+		fn := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(fn), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(fn, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func unsealedVerifyTxt(t *testing.T) string {
+	t.Helper()
+
+	address, signature, err := pkg.SignMessage(pkg.MainNet, pkg.Bitcoin, unsealedWif, unsealedMessage)
+	if err != nil || address != unsealedAddress {
+		t.Fatalf("SignMessage() = %v, %v", address, err)
+	}
+
+	return pkg.FormatSignedMessage(pkg.Bitcoin, address, signature, unsealedMessage)
+}
+
+func TestInspectVolume(t *testing.T) {
+	tipsVerifyTxt, err := os.ReadFile("../verify.txt_tips")
+	if err != nil {
+		t.Skip("verify.txt_tips not found")
+	}
+
+	const tipsAddress = "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR"
+
+	tests := []struct {
+		name             string
+		files            map[string]string
+		wantUnsealed     bool
+		wantKeyAgrees    bool
+		wantFirmware     string
+		wantSerial       string
+		wantProblems     []string
+		wantFileAgreeing map[string]bool
+	}{
+		{
+			name: "sealed",
+			files: map[string]string{
+				pkg.VerifyTxtPath:  string(tipsVerifyTxt),
+				pkg.AddressTxtName: tipsAddress + "\n",
+				pkg.ReadmeTxtName:  "Opendime bitcoin credit stick. Serial DDRRNOCZJRIFCIBAEBJDOJQY74\n",
+				pkg.IndexHtmName:   `<a href="https://mempool.space/address/` + tipsAddress + `">balance</a>`,
+			},
+			wantFirmware:     "2.4.0",
+			wantSerial:       "DDRRNOCZJRIFCIBAEBJDOJQY74",
+			wantFileAgreeing: map[string]bool{pkg.AddressTxtName: true, pkg.ReadmeTxtName: true, pkg.IndexHtmName: true},
+		}, {
+			name: "address.txt disagrees",
+			files: map[string]string{
+				pkg.VerifyTxtPath:  string(tipsVerifyTxt),
+				pkg.AddressTxtName: "1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f\n",
+				pkg.VersionTxtPath: "2.3.0\n",
+			},
+			wantFirmware: "2.4.0",
+			wantSerial:   "DDRRNOCZJRIFCIBAEBJDOJQY74",
+			wantProblems: []string{
+				"address.txt mentions 1Nu1QpfegiGmqHS6YZxkaiGpnqAUXvZz2f not 1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR",
+				"advanced/version.txt does not match firmware 2.4.0 in verify.txt",
+			},
+			wantFileAgreeing: map[string]bool{pkg.AddressTxtName: false, pkg.VersionTxtPath: true},
+		}, {
+			name: "address.txt missing and private key without banner",
+			files: map[string]string{
+				pkg.VerifyTxtPath:     string(tipsVerifyTxt),
+				pkg.PrivateKeyTxtName: unsealedWif + "\n",
+			},
+			wantUnsealed: true,
+			wantFirmware: "2.4.0",
+			wantSerial:   "DDRRNOCZJRIFCIBAEBJDOJQY74",
+			wantProblems: []string{
+				"address.txt missing",
+				"private-key.txt private key does not derive 1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR",
+				"private-key.txt exists but verify.txt is not marked UNSEALED",
+			},
+			wantFileAgreeing: map[string]bool{pkg.PrivateKeyTxtName: true},
+		}, {
+			name: "unsealed",
+			files: map[string]string{
+				pkg.VerifyTxtPath:     unsealedVerifyTxt(t),
+				pkg.AddressTxtName:    unsealedAddress,
+				pkg.PrivateKeyTxtName: "Private key for " + unsealedAddress + "\n\n" + unsealedWif + "\n",
+			},
+			wantUnsealed:     true,
+			wantKeyAgrees:    true,
+			wantFirmware:     "2.4.0",
+			wantSerial:       "TESTSERIAL",
+			wantFileAgreeing: map[string]bool{pkg.AddressTxtName: true, pkg.PrivateKeyTxtName: true},
+		}, {
+			name: "unsealed banner without private key",
+			files: map[string]string{
+				pkg.VerifyTxtPath:  unsealedVerifyTxt(t),
+				pkg.AddressTxtName: unsealedAddress,
+			},
+			wantUnsealed:     true,
+			wantFirmware:     "2.4.0",
+			wantSerial:       "TESTSERIAL",
+			wantProblems:     []string{"verify.txt is marked UNSEALED but private-key.txt is missing"},
+			wantFileAgreeing: map[string]bool{pkg.AddressTxtName: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeVolume(t, dir, tt.files)
+
+			got, err := InspectVolume(pkg.MainNet, dir)
+			if err != nil {
+				t.Fatalf("InspectVolume() error = %v", err)
+			}
+
+			if !got.SignatureValid || got.Statement.Coin != "BTC" || got.Statement.Firmware != tt.wantFirmware ||
+				got.Statement.Serial != tt.wantSerial {
+				t.Errorf("InspectVolume() = %+v", got)
+			}
+
+			if got.Unsealed != tt.wantUnsealed || got.PrivateKeyAgrees != tt.wantKeyAgrees {
+				t.Errorf("InspectVolume() Unsealed = %v PrivateKeyAgrees = %v", got.Unsealed, got.PrivateKeyAgrees)
+			}
+
+			if !reflect.DeepEqual(got.Problems, tt.wantProblems) {
+				t.Errorf("InspectVolume() Problems = %q, want %q", got.Problems, tt.wantProblems)
+			}
+
+			gotFileAgreeing := map[string]bool{}
+			for _, file := range got.Files {
+				gotFileAgreeing[file.Name] = file.Agrees
+			}
+
+			if !reflect.DeepEqual(gotFileAgreeing, tt.wantFileAgreeing) {
+				t.Errorf("InspectVolume() Files = %v, want %v", gotFileAgreeing, tt.wantFileAgreeing)
+			}
+		})
+	}
+}
+
+func TestInspectVolumeNotOpendime(t *testing.T) {
+	if _, err := InspectVolume(pkg.MainNet, t.TempDir()); !os.IsNotExist(err) {
+		t.Errorf("InspectVolume() error = %v, want not exist", err)
+	}
+}
+
+func Test_findAddresses(t *testing.T) {
+	const text = `Nonce: 1675bf38ec241a2308585ad0  Serial: DDRRNOCZJRIFCIBAEBJDOJQY74
+1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5 1MMG2EYCKHOMHJAIKEAVEHHPCSHTREHLFR
+L4bZ2HCxZJShqzkZRfy4Rdb8zUu8faEeuMTbR9WehaKfuBwv8QTZ BC1QPJTAGGFHSNHKCYG967K3JMSXTM5HZG72Q8EJR5`
+
+	want := []string{"1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR", "bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5"}
+
+	if got := findAddresses(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("findAddresses() = %v, want %v", got, want)
+	}
+}
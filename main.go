@@ -25,21 +25,41 @@ func main() {
 	case "sigtoaddr":
 		os.Exit(cmd.SigtoaddrMain(os.Stdout))
 	case "keyconv":
-		var key string
-
-		fmt.Printf("Private Key WIF or hex: ")
-		fmt.Scanln(&key)
-		key = strings.TrimSpace(key)
-
-		os.Exit(cmd.KeyconvMain(os.Stdout, key))
+		os.Exit(cmd.KeyconvMain(os.Stdout, readKey()))
 	case "crypt":
 		os.Exit(cmd.CryptMain(os.Stdout))
+	case "inspect":
+		os.Exit(cmd.InspectMain(os.Stdout))
+	case "challenge":
+		os.Exit(cmd.ChallengeMain(os.Stdout))
+	case "emulate":
+		os.Exit(cmd.EmulateMain(os.Stdout))
+	case "inventory":
+		os.Exit(cmd.InventoryMain(os.Stdout))
+	case "whois":
+		os.Exit(cmd.WhoisMain(os.Stdout))
+	case "accept":
+		os.Exit(cmd.AcceptMain(os.Stdout))
+	case "label":
+		os.Exit(cmd.LabelMain(os.Stdout))
+	case "sign":
+		os.Exit(cmd.SignMain(os.Stdout, readKey()))
 	}
 
 	usageRoot()
 }
 
+// readKey prompts for the private key. The prompt goes to stderr so stdout is only the output eg -format json
+func readKey() string {
+	var key string
+
+	fmt.Fprint(os.Stderr, "Private Key WIF or hex: ")
+	fmt.Scanln(&key)
+
+	return strings.TrimSpace(key)
+}
+
 func usageRoot() {
-	fmt.Printf("usage: opendime-utils command(sigtoaddr|keyconv|crypt) options\n")
+	fmt.Printf("usage: opendime-utils command(sigtoaddr|keyconv|crypt|sign|inspect|challenge|emulate|inventory|whois|accept|label) options\n")
 	os.Exit(1)
 }
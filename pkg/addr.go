@@ -4,27 +4,52 @@ import (
 	"encoding/hex"
 
 	"github.com/btcsuite/btcd/btcec/v2"
-	"github.com/btcsuite/btcd/btcutil"
-	"github.com/btcsuite/btcd/chaincfg"
-	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // Addresses struct for GetAddresses response
 type Addresses struct {
-	Original                string
-	BitcoinP2PKH            string
-	BitcoinP2PKHCompressed  string
-	BitcoinP2WPKH           string
-	Ethereum                string
-	LitecoinP2PKH           string
-	LitecoinP2PKHCompressed string
-	LitecoinP2WPKH          string
-	DogecoinP2PKH           string
-	UncompressedHex         string
-	CompressedHex           string
+	Original        string
+	Derived         []DerivedAddress
+	UncompressedHex string
+	CompressedHex   string
 }
 
-// GetAddresses get addresses from a verified message (only public key is needed)
+// DerivedAddress one address derived from the public key by a registered AddressType
+type DerivedAddress struct {
+	// ID of the AddressType eg BitcoinP2WPKH
+	ID string
+	// Coin name eg Bitcoin
+	Coin string
+	// Type name eg P2WPKH, empty for coins with a single address type
+	Type string
+	// Label human readable name eg "Bitcoin P2WPKH"
+	Label   string
+	Address string
+}
+
+// Get returns the derived address for the address type ID or an empty string if there is none
+func (a Addresses) Get(id string) string {
+	for _, derived := range a.Derived {
+		if derived.ID == id {
+			return derived.Address
+		}
+	}
+
+	return ""
+}
+
+// Map returns the derived addresses keyed by address type ID
+func (a Addresses) Map() map[string]string {
+	result := make(map[string]string, len(a.Derived))
+	for _, derived := range a.Derived {
+		result[derived.ID] = derived.Address
+	}
+
+	return result
+}
+
+// GetAddresses get addresses from a verified message (only public key and network are needed)
+// Addresses are returned in registry order, skipping coins that do not exist on the network
 func GetAddresses(message VerifiedMessage) (Addresses, error) {
 	publicKeyBytes, err := hex.DecodeString(message.PublicKeyHex)
 	if err != nil {
@@ -35,34 +60,31 @@ func GetAddresses(message VerifiedMessage) (Addresses, error) {
 		return Addresses{}, err
 	}
 
-	// todo: assuming these cannot error for a valid public key on the Secp256k1 curve ?!
-	pkHash := btcutil.Hash160(publicKey.SerializeUncompressed())
-	bitcoinP2PKH, _ := btcutil.NewAddressPubKeyHash(pkHash, &chaincfg.MainNetParams)
-
-	pkHashC := btcutil.Hash160(publicKey.SerializeCompressed())
-	bitcoinP2PKHC, _ := btcutil.NewAddressPubKeyHash(pkHashC, &chaincfg.MainNetParams)
-	bitcoinP2WPKH, _ := btcutil.NewAddressWitnessPubKeyHash(pkHashC, &chaincfg.MainNetParams)
+	addresses := Addresses{
+		Original:        message.Address,
+		UncompressedHex: hex.EncodeToString(publicKey.SerializeUncompressed()),
+		CompressedHex:   hex.EncodeToString(publicKey.SerializeCompressed()),
+	}
 
-	ltcPkHash := btcutil.Hash160(publicKey.SerializeUncompressed())
-	litecoinP2PKH, _ := btcutil.NewAddressPubKeyHash(ltcPkHash, &litecoinMainNetParams)
+	for _, addressType := range AddressTypes() {
+		params, ok := addressType.Coin.Params(message.Network)
+		if !ok {
+			continue
+		}
 
-	ltcPkHashC := btcutil.Hash160(publicKey.SerializeCompressed())
-	litecoinP2PKHC, _ := btcutil.NewAddressPubKeyHash(ltcPkHashC, &litecoinMainNetParams)
-	litecoinP2WPKH, _ := btcutil.NewAddressWitnessPubKeyHash(ltcPkHashC, &litecoinMainNetParams)
+		address, err := addressType.Derive(publicKey, params)
+		if err != nil {
+			return Addresses{}, err
+		}
 
-	dogecoinP2PKH, _ := btcutil.NewAddressPubKeyHash(pkHash, &dogecoinMainNetParams)
+		addresses.Derived = append(addresses.Derived, DerivedAddress{
+			ID:      addressType.ID,
+			Coin:    addressType.Coin.Name,
+			Type:    addressType.Name,
+			Label:   addressType.Label(),
+			Address: address,
+		})
+	}
 
-	return Addresses{
-		Original:                message.Address,
-		BitcoinP2PKH:            bitcoinP2PKH.String(),
-		BitcoinP2PKHCompressed:  bitcoinP2PKHC.String(),
-		BitcoinP2WPKH:           bitcoinP2WPKH.String(),
-		Ethereum:                crypto.PubkeyToAddress(*publicKey.ToECDSA()).Hex(),
-		LitecoinP2PKH:           litecoinP2PKH.String(),
-		LitecoinP2PKHCompressed: litecoinP2PKHC.String(),
-		LitecoinP2WPKH:          litecoinP2WPKH.String(),
-		DogecoinP2PKH:           dogecoinP2PKH.String(),
-		UncompressedHex:         hex.EncodeToString(publicKey.SerializeUncompressed()),
-		CompressedHex:           hex.EncodeToString(publicKey.SerializeCompressed()),
-	}, nil
+	return addresses, nil
 }
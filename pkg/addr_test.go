@@ -7,6 +7,7 @@ import (
 
 func TestGetAddresses(t *testing.T) {
 	verifiedMessage, _ := VerifyMessage(
+		MainNet,
 		"133r6sCjLq6NbmSLjxuypDuSeocwenu1Qg",
 		"Hz0pDxS09fEdjKrSJenLYsz05gakT6eW9GdzKDopnlwUL3bf3mQeJcS+takCIMuavK/NLOorXWXZCqV0KBMlwgU=",
 		"Hello World",
@@ -18,7 +19,8 @@ func TestGetAddresses(t *testing.T) {
 	tests := []struct {
 		name    string
 		args    args
-		want    Addresses
+		wantHex string
+		want    map[string]string
 		wantErr bool
 	}{
 		{
@@ -26,18 +28,24 @@ func TestGetAddresses(t *testing.T) {
 			args: args{
 				message: verifiedMessage,
 			},
-			want: Addresses{
-				Original:                "133r6sCjLq6NbmSLjxuypDuSeocwenu1Qg",
-				BitcoinP2PKH:            "19MkFnavAVX9Njwt43a2sWZrVg9G5jLntU",
-				BitcoinP2PKHCompressed:  "133r6sCjLq6NbmSLjxuypDuSeocwenu1Qg",
-				BitcoinP2WPKH:           "bc1qzeapyvz7kl7v5vj865rahts2jjcdz0ssyc3wl8",
-				Ethereum:                "0x148582B4F60139ce2Bc7E25e7551F31c1122B6f4",
-				LitecoinP2PKH:           "LTahWztkF9mCdYe3EBZL9XdchtWYC8LJYm",
-				LitecoinP2PKHCompressed: "LMGoN5WZRVLRra8Vv6uH6EyCs1zDmVPhZV",
-				LitecoinP2WPKH:          "ltc1qzeapyvz7kl7v5vj865rahts2jjcdz0ssqyt28h",
-				DogecoinP2PKH:           "DDVqo3XZTuRRuk8UndZbRGjTNosZNGHQdo",
-				UncompressedHex:         "046afa3afc399f7f332866e37f475938589de0a3298a3aa062c8f4c74450e3d3b287224ee09db6d217912f4706147bb96762d1e11e7ce2e928fd61ecdbd2e37a99",
-				CompressedHex:           "036afa3afc399f7f332866e37f475938589de0a3298a3aa062c8f4c74450e3d3b2",
+			wantHex: "036afa3afc399f7f332866e37f475938589de0a3298a3aa062c8f4c74450e3d3b2",
+			want: map[string]string{
+				"BitcoinP2PKH":               "19MkFnavAVX9Njwt43a2sWZrVg9G5jLntU",
+				"BitcoinP2PKHCompressed":     "133r6sCjLq6NbmSLjxuypDuSeocwenu1Qg",
+				"BitcoinP2SHP2WPKH":          "3MKkNGo8Uoza2vWiT8viahqytxoBKc5wjs",
+				"BitcoinP2WPKH":              "bc1qzeapyvz7kl7v5vj865rahts2jjcdz0ssyc3wl8",
+				"BitcoinP2TR":                "bc1plw9wrc6uxczx80hf878gtxzhcxzyx7dpx7qs5576egra9d5gcd0sqcyn85",
+				"Ethereum":                   "0x148582B4F60139ce2Bc7E25e7551F31c1122B6f4",
+				"LitecoinP2PKH":              "LTahWztkF9mCdYe3EBZL9XdchtWYC8LJYm",
+				"LitecoinP2PKHCompressed":    "LMGoN5WZRVLRra8Vv6uH6EyCs1zDmVPhZV",
+				"LitecoinP2SHP2WPKH":         "MTXtgAD6RvqzqRncZ1v4QM6PDfPdP4fcQS",
+				"LitecoinP2WPKH":             "ltc1qzeapyvz7kl7v5vj865rahts2jjcdz0ssqyt28h",
+				"LitecoinP2TR":               "ltc1plw9wrc6uxczx80hf878gtxzhcxzyx7dpx7qs5576egra9d5gcd0sru2ra3",
+				"DogecoinP2PKH":              "DDVqo3XZTuRRuk8UndZbRGjTNosZNGHQdo",
+				"BitcoinCashP2PKH":           "bitcoincash:qpd66mkjhlrgtm3pq6mz4z0cfjfw753dkqtvzjery8",
+				"BitcoinCashP2PKHCompressed": "bitcoincash:qqt85y3st6mlej3jgl2s0kawp22tp5f7zq4zja7wr8",
+				"DashP2PKH":                  "Xj3b63Ep8CjjXgYTuvtFj3FeL1ix5k3kw7",
+				"DashP2PKHCompressed":        "Xcjgw7rdJYJxki2vbrECfkbEV9CdgBckQT",
 			},
 			wantErr: false,
 		},
@@ -49,8 +57,131 @@ func TestGetAddresses(t *testing.T) {
 				t.Errorf("GetAddresses() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("GetAddresses() = %v, want %v", got, tt.want)
+			if got.Original != tt.args.message.Address {
+				t.Errorf("GetAddresses() Original = %v, want %v", got.Original, tt.args.message.Address)
+			}
+			if got.CompressedHex != tt.wantHex {
+				t.Errorf("GetAddresses() CompressedHex = %v, want %v", got.CompressedHex, tt.wantHex)
+			}
+			if !reflect.DeepEqual(got.Map(), tt.want) {
+				t.Errorf("GetAddresses() = %v, want %v", got.Map(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAddressesTaproot(t *testing.T) {
+	// BIP86 test vectors, internal keys from m/86'/0'/0'/0/0, m/86'/0'/0'/0/1 and m/86'/0'/0'/1/0
+	tests := []struct {
+		name         string
+		publicKeyHex string
+		want         string
+	}{
+		{"bip86 receive 0", "02cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115", "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"},
+		{"bip86 receive 1", "0283dfe85a3151d2517290da461fe2815591ef69f2b18a2ce63f01697a8b313145", "bc1p4qhjn9zdvkux4e44uhx8tc55attvtyu358kutcqkudyccelu0was9fqzwh"},
+		{"bip86 change 0", "02399f1b2f4393f29a18c937859c5dd8a77350103157eb880f02e8c08214277cef", "bc1p3qkhfews2uk44qtvauqyr2ttdsw7svhkl9nkm9s9c3x4ax5h60wqwruhk7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetAddresses(VerifiedMessage{PublicKeyHex: tt.publicKeyHex})
+			if err != nil {
+				t.Errorf("GetAddresses() error = %v", err)
+				return
+			}
+			if got.Get("BitcoinP2TR") != tt.want {
+				t.Errorf("GetAddresses() BitcoinP2TR = %v, want %v", got.Get("BitcoinP2TR"), tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAddressesNetwork(t *testing.T) {
+	const (
+		// BIP49 test vector m/49'/1'/0'/0/0
+		bip49PublicKeyHex = "03a1af804ac108a8a51782198c2d034b28bf90c8803f5a53f76276fa69a4eae77f"
+		bip49Address      = "2Mww8dCYPUpKHofjgcXcBCEGmniw9CoaiD2"
+	)
+
+	got, err := GetAddresses(VerifiedMessage{PublicKeyHex: bip49PublicKeyHex, Network: TestNet})
+	if err != nil {
+		t.Fatalf("GetAddresses() error = %v", err)
+	}
+	if got.Get("BitcoinP2SHP2WPKH") != bip49Address {
+		t.Errorf("GetAddresses() BitcoinP2SHP2WPKH = %v, want %v", got.Get("BitcoinP2SHP2WPKH"), bip49Address)
+	}
+
+	tests := []struct {
+		name    string
+		network Network
+		want    map[string]string
+	}{
+		{
+			name:    "testnet",
+			network: TestNet,
+			want: map[string]string{
+				"BitcoinP2PKH":               "moshYqftyWxQ9rRVmcYQhRnBMfjy1SoVLm",
+				"BitcoinP2PKHCompressed":     "mhZoPvHi9rXdNsuxTXtMe97mWoDeZgGkBg",
+				"BitcoinP2SHP2WPKH":          "2NCsxS1jA6GVvEi9G8GYbCeqF7K1M8EFU6B",
+				"BitcoinP2WPKH":              "tb1qzeapyvz7kl7v5vj865rahts2jjcdz0ssw72ay5",
+				"BitcoinP2TR":                "tb1plw9wrc6uxczx80hf878gtxzhcxzyx7dpx7qs5576egra9d5gcd0shsjuam",
+				"Ethereum":                   "0x148582B4F60139ce2Bc7E25e7551F31c1122B6f4",
+				"LitecoinP2PKH":              "moshYqftyWxQ9rRVmcYQhRnBMfjy1SoVLm",
+				"LitecoinP2PKHCompressed":    "mhZoPvHi9rXdNsuxTXtMe97mWoDeZgGkBg",
+				"LitecoinP2SHP2WPKH":         "QgEiZ2bQ7NZ1NtuJkNacHMGgFhTB1svNX2",
+				"LitecoinP2WPKH":             "tltc1qzeapyvz7kl7v5vj865rahts2jjcdz0sshkgr5a",
+				"LitecoinP2TR":               "tltc1plw9wrc6uxczx80hf878gtxzhcxzyx7dpx7qs5576egra9d5gcd0sgnwazy",
+				"DogecoinP2PKH":              "ncYuX4GUPst9nihfpTD3fgKkcgFrRZih1v",
+				"BitcoinCashP2PKH":           "bchtest:qpd66mkjhlrgtm3pq6mz4z0cfjfw753dkq07x4m5rm",
+				"BitcoinCashP2PKHCompressed": "bchtest:qqt85y3st6mlej3jgl2s0kawp22tp5f7zq3sk6ueym",
+				"DashP2PKH":                  "yUgC6zKFZkPosRU1UnCem4fzcJDKawWPnT",
+				"DashP2PKHCompressed":        "yNNHx4w4k5y36SxUAhYbhn1amRh1Byv3YP",
+			},
+		}, {
+			name:    "signet has no litecoin or dogecoin",
+			network: SigNet,
+			want: map[string]string{
+				"BitcoinP2PKH":           "moshYqftyWxQ9rRVmcYQhRnBMfjy1SoVLm",
+				"BitcoinP2PKHCompressed": "mhZoPvHi9rXdNsuxTXtMe97mWoDeZgGkBg",
+				"BitcoinP2SHP2WPKH":      "2NCsxS1jA6GVvEi9G8GYbCeqF7K1M8EFU6B",
+				"BitcoinP2WPKH":          "tb1qzeapyvz7kl7v5vj865rahts2jjcdz0ssw72ay5",
+				"BitcoinP2TR":            "tb1plw9wrc6uxczx80hf878gtxzhcxzyx7dpx7qs5576egra9d5gcd0shsjuam",
+				"Ethereum":               "0x148582B4F60139ce2Bc7E25e7551F31c1122B6f4",
+			},
+		}, {
+			name:    "regtest",
+			network: RegTest,
+			want: map[string]string{
+				"BitcoinP2PKH":               "moshYqftyWxQ9rRVmcYQhRnBMfjy1SoVLm",
+				"BitcoinP2PKHCompressed":     "mhZoPvHi9rXdNsuxTXtMe97mWoDeZgGkBg",
+				"BitcoinP2SHP2WPKH":          "2NCsxS1jA6GVvEi9G8GYbCeqF7K1M8EFU6B",
+				"BitcoinP2WPKH":              "bcrt1qzeapyvz7kl7v5vj865rahts2jjcdz0ssvhnsna",
+				"BitcoinP2TR":                "bcrt1plw9wrc6uxczx80hf878gtxzhcxzyx7dpx7qs5576egra9d5gcd0s6fc6gp",
+				"Ethereum":                   "0x148582B4F60139ce2Bc7E25e7551F31c1122B6f4",
+				"LitecoinP2PKH":              "moshYqftyWxQ9rRVmcYQhRnBMfjy1SoVLm",
+				"LitecoinP2PKHCompressed":    "mhZoPvHi9rXdNsuxTXtMe97mWoDeZgGkBg",
+				"LitecoinP2SHP2WPKH":         "QgEiZ2bQ7NZ1NtuJkNacHMGgFhTB1svNX2",
+				"LitecoinP2WPKH":             "rltc1qzeapyvz7kl7v5vj865rahts2jjcdz0ssj6feyr",
+				"LitecoinP2TR":               "rltc1plw9wrc6uxczx80hf878gtxzhcxzyx7dpx7qs5576egra9d5gcd0sj2j98v",
+				"DogecoinP2PKH":              "moshYqftyWxQ9rRVmcYQhRnBMfjy1SoVLm",
+				"BitcoinCashP2PKH":           "bchreg:qpd66mkjhlrgtm3pq6mz4z0cfjfw753dkq4zs5c8qa",
+				"BitcoinCashP2PKHCompressed": "bchreg:qqt85y3st6mlej3jgl2s0kawp22tp5f7zqtvqml28a",
+				"DashP2PKH":                  "yUgC6zKFZkPosRU1UnCem4fzcJDKawWPnT",
+				"DashP2PKHCompressed":        "yNNHx4w4k5y36SxUAhYbhn1amRh1Byv3YP",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetAddresses(VerifiedMessage{
+				PublicKeyHex: "036afa3afc399f7f332866e37f475938589de0a3298a3aa062c8f4c74450e3d3b2",
+				Network:      tt.network,
+			})
+			if err != nil {
+				t.Errorf("GetAddresses() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got.Map(), tt.want) {
+				t.Errorf("GetAddresses() = %v, want %v", got.Map(), tt.want)
 			}
 		})
 	}
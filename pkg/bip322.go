@@ -0,0 +1,223 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// bip322Tag tagged hash tag for the BIP322 message hash
+const bip322Tag = "BIP0322-signed-message"
+
+// BIP322MessageHash returns the BIP322 tagged hash of the message
+func BIP322MessageHash(message []byte) []byte {
+	return chainhash.TaggedHash([]byte(bip322Tag), message)[:]
+}
+
+// VerifyBIP322 verifies a BIP322 "simple" (witness stack) or "full" (to_sign transaction) signature for a segwit
+// address (P2WPKH or P2TR key-path). The public key is only returned for P2WPKH, a P2TR proof does not reveal the
+// internal key so PublicKeyHex is left empty
+func VerifyBIP322(network Network, address string, signature string, message []byte) (VerifiedMessage, error) {
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return VerifiedMessage{}, err
+	}
+
+	coin, scriptPubKey, err := segwitScript(network, address)
+	if err != nil {
+		return VerifiedMessage{}, err
+	}
+
+	toSpend := bip322ToSpend(scriptPubKey, message)
+
+	toSign, err := bip322ToSign(toSpend, signatureBytes)
+	if err != nil {
+		return VerifiedMessage{}, err
+	}
+
+	prevOutFetcher := txscript.NewCannedPrevOutputFetcher(scriptPubKey, 0)
+	engine, err := txscript.NewEngine(scriptPubKey, toSign, 0, txscript.StandardVerifyFlags, nil,
+		txscript.NewTxSigHashes(toSign, prevOutFetcher), 0, prevOutFetcher)
+	if err != nil {
+		return VerifiedMessage{}, err
+	}
+
+	if err := engine.Execute(); err != nil {
+		return VerifiedMessage{}, fmt.Errorf("Invalid BIP322 signature: %w", err)
+	}
+
+	verifiedMessage := VerifiedMessage{
+		Address:   address,
+		Signature: signatureBytes,
+		Message:   message,
+		IsValid:   true,
+		Network:   network,
+	}
+
+	witness := toSign.TxIn[0].Witness
+	switch {
+	case txscript.IsPayToWitnessPubKeyHash(scriptPubKey) && len(witness) == 2:
+		publicKey, err := btcec.ParsePubKey(witness[1])
+		if err != nil {
+			return VerifiedMessage{}, err
+		}
+
+		verifiedMessage.PublicKeyHex = hex.EncodeToString(publicKey.SerializeUncompressed())
+		verifiedMessage.AddressType = addressTypeID(coin, TypeP2WPKH)
+	case txscript.IsPayToTaproot(scriptPubKey):
+		verifiedMessage.AddressType = addressTypeID(coin, TypeP2TR)
+	}
+
+	return verifiedMessage, nil
+}
+
+// segwitScript decodes a bech32/bech32m segwit address for any registered coin on the network and returns the
+// coin and output script
+func segwitScript(network Network, address string) (*Coin, []byte, error) {
+	hrp, data, version, err := bech32.DecodeGeneric(address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("BIP322 requires a segwit address: %w", err)
+	}
+
+	if len(data) < 1 {
+		return nil, nil, errors.New("segwit address has no witness version")
+	}
+
+	witnessVersion := data[0]
+	if (witnessVersion == 0 && version != bech32.Version0) || (witnessVersion != 0 && version != bech32.VersionM) {
+		return nil, nil, errors.New("segwit address has the wrong checksum for its witness version")
+	}
+
+	program, err := bech32.ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	coin := coinForHRP(network, hrp)
+	if coin == nil {
+		return nil, nil, fmt.Errorf("unknown segwit address prefix '%s' for network %s", hrp, network)
+	}
+
+	builder := txscript.NewScriptBuilder()
+	if witnessVersion == 0 {
+		builder.AddOp(txscript.OP_0)
+	} else {
+		builder.AddOp(txscript.OP_1 - 1 + witnessVersion)
+	}
+
+	script, err := builder.AddData(program).Script()
+
+	return coin, script, err
+}
+
+func coinForHRP(network Network, hrp string) *Coin {
+	for _, coin := range Coins() {
+		params, ok := coin.Params(network)
+		if ok && params != nil && params.Bech32HRPSegwit != "" && strings.EqualFold(params.Bech32HRPSegwit, hrp) {
+			return coin
+		}
+	}
+
+	return nil
+}
+
+// addressTypeID returns the registered address type ID for the coin and type name eg BitcoinP2TR
+func addressTypeID(coin *Coin, typeName string) string {
+	for _, addressType := range AddressTypes() {
+		if addressType.Coin == coin && addressType.Name == typeName {
+			return addressType.ID
+		}
+	}
+
+	return ""
+}
+
+// bip322ToSpend the virtual transaction committing to the message and the address output script
+func bip322ToSpend(scriptPubKey []byte, message []byte) *wire.MsgTx {
+	scriptSig, _ := txscript.NewScriptBuilder().AddOp(txscript.OP_0).AddData(BIP322MessageHash(message)).Script()
+
+	tx := wire.NewMsgTx(0)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: 0xffffffff},
+		SignatureScript:  scriptSig,
+		Sequence:         0,
+	})
+	tx.AddTxOut(wire.NewTxOut(0, scriptPubKey))
+
+	return tx
+}
+
+// bip322ToSign returns the virtual transaction spending to_spend. A "full" signature is the serialized to_sign
+// transaction and a "simple" signature is only its witness stack
+func bip322ToSign(toSpend *wire.MsgTx, signature []byte) (*wire.MsgTx, error) {
+	toSpendHash := toSpend.TxHash()
+
+	var full wire.MsgTx
+	reader := bytes.NewReader(signature)
+	if err := full.Deserialize(reader); err == nil && reader.Len() == 0 && len(full.TxIn) == 1 &&
+		full.TxIn[0].PreviousOutPoint.Hash == toSpendHash {
+		if full.TxIn[0].PreviousOutPoint.Index != 0 {
+			return nil, errors.New("BIP322 full signature does not spend the message")
+		}
+
+		if len(full.TxOut) != 1 || full.TxOut[0].Value != 0 || !bytes.Equal(full.TxOut[0].PkScript, []byte{txscript.OP_RETURN}) {
+			return nil, errors.New("BIP322 full signature must have a single OP_RETURN output")
+		}
+
+		return &full, nil
+	}
+
+	witness, err := readWitness(signature)
+	if err != nil {
+		return nil, fmt.Errorf("BIP322 simple signature malformed: %w", err)
+	}
+
+	tx := wire.NewMsgTx(0)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: toSpendHash, Index: 0},
+		Witness:          witness,
+		Sequence:         0,
+	})
+	tx.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_RETURN}))
+
+	return tx, nil
+}
+
+// readWitness decodes a consensus encoded witness stack (item count then length prefixed items)
+func readWitness(data []byte) (wire.TxWitness, error) {
+	reader := bytes.NewReader(data)
+
+	count, err := wire.ReadVarInt(reader, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if count > uint64(len(data)) {
+		return nil, fmt.Errorf("witness item count %d too large", count)
+	}
+
+	witness := make(wire.TxWitness, 0, count)
+	for i := uint64(0); i < count; i++ {
+		item, err := wire.ReadVarBytes(reader, 0, uint32(len(data)), "witness item")
+		if err != nil {
+			return nil, err
+		}
+
+		witness = append(witness, item)
+	}
+
+	if reader.Len() != 0 {
+		return nil, fmt.Errorf("%d unexpected bytes after witness", reader.Len())
+	}
+
+	return witness, nil
+}
@@ -0,0 +1,147 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+)
+
+// BIP322 test vectors from the BIP
+const (
+	bip322P2WPKHAddress = "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l"
+	bip322P2TRAddress   = "bc1ppv609nr0vr25u07u95waq5lucwfm6tde4nydujnu8npg4q75mr5sxq8lt3"
+)
+
+func TestBIP322MessageHash(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"", "c90c269c4f8fcbe6880f72a721ddfbf1914268a794cbb21cfafee13770ae19f1"},
+		{"Hello World", "f0eb03b1a75ac6d9847f55c624a99169b5dccba2a31f5b23bea77ba270de0a7a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			if got := hex.EncodeToString(BIP322MessageHash([]byte(tt.message))); got != tt.want {
+				t.Errorf("BIP322MessageHash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyBIP322(t *testing.T) {
+	tests := []struct {
+		name          string
+		address       string
+		signature     string
+		message       string
+		wantType      string
+		wantPublicKey bool
+		wantErr       bool
+	}{
+		{
+			name:          "p2wpkh hello world",
+			address:       bip322P2WPKHAddress,
+			signature:     "AkcwRAIgZRfIY3p7/DoVTty6YZbWS71bc5Vct9p9Fia83eRmw2QCICK/ENGfwLtptFluMGs2KsqoNSk89pO7F29zJLUx9a/sASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+			message:       "Hello World",
+			wantType:      "BitcoinP2WPKH",
+			wantPublicKey: true,
+		}, {
+			name:          "p2wpkh empty message",
+			address:       bip322P2WPKHAddress,
+			signature:     "AkcwRAIgM2gBAQqvZX15ZiysmKmQpDrG83avLIT492QBzLnQIxYCIBaTpOaD20qRlEylyxFSeEA2ba9YOixpX8z46TSDtS40ASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+			message:       "",
+			wantType:      "BitcoinP2WPKH",
+			wantPublicKey: true,
+		}, {
+			name:      "p2wpkh wrong message",
+			address:   bip322P2WPKHAddress,
+			signature: "AkcwRAIgM2gBAQqvZX15ZiysmKmQpDrG83avLIT492QBzLnQIxYCIBaTpOaD20qRlEylyxFSeEA2ba9YOixpX8z46TSDtS40ASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+			message:   "Hello World",
+			wantErr:   true,
+		}, {
+			name:      "p2tr hello world",
+			address:   bip322P2TRAddress,
+			signature: "AUHd69PrJQEv+oKTfZ8l+WROBHuy9HKrbFCJu7U1iK2iiEy1vMU5EfMtjc+VSHM7aU0SDbak5IUZRVno2P5mjSafAQ==",
+			message:   "Hello World",
+			wantType:  "BitcoinP2TR",
+		}, {
+			name:      "p2tr wrong message",
+			address:   bip322P2TRAddress,
+			signature: "AUHd69PrJQEv+oKTfZ8l+WROBHuy9HKrbFCJu7U1iK2iiEy1vMU5EfMtjc+VSHM7aU0SDbak5IUZRVno2P5mjSafAQ==",
+			message:   "Hello World!",
+			wantErr:   true,
+		}, {
+			name:      "not a segwit address",
+			address:   "133r6sCjLq6NbmSLjxuypDuSeocwenu1Qg",
+			signature: "AUHd69PrJQEv+oKTfZ8l+WROBHuy9HKrbFCJu7U1iK2iiEy1vMU5EfMtjc+VSHM7aU0SDbak5IUZRVno2P5mjSafAQ==",
+			message:   "Hello World",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyMessage(MainNet, tt.address, tt.signature, tt.message)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyMessage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.AddressType != tt.wantType {
+				t.Errorf("VerifyMessage() AddressType = %v, want %v", got.AddressType, tt.wantType)
+			}
+			if (got.PublicKeyHex != "") != tt.wantPublicKey {
+				t.Errorf("VerifyMessage() PublicKeyHex = %v, want public key %v", got.PublicKeyHex, tt.wantPublicKey)
+			}
+		})
+	}
+}
+
+func TestVerifyBIP322Addresses(t *testing.T) {
+	// The P2WPKH proof reveals the public key so the usual addresses can be derived
+	got, err := VerifyBIP322(MainNet, bip322P2WPKHAddress, "AkcwRAIgZRfIY3p7/DoVTty6YZbWS71bc5Vct9p9Fia83eRmw2QCICK/ENGfwLtptFluMGs2KsqoNSk89pO7F29zJLUx9a/sASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=", []byte("Hello World"))
+	if err != nil {
+		t.Fatalf("VerifyBIP322() error = %v", err)
+	}
+
+	addresses, err := GetAddresses(got)
+	if err != nil {
+		t.Fatalf("GetAddresses() error = %v", err)
+	}
+	if addresses.Get("BitcoinP2WPKH") != bip322P2WPKHAddress {
+		t.Errorf("GetAddresses() BitcoinP2WPKH = %v, want %v", addresses.Get("BitcoinP2WPKH"), bip322P2WPKHAddress)
+	}
+}
+
+func TestVerifyBIP322Full(t *testing.T) {
+	// Build the "full" to_sign transaction from the simple P2TR vector
+	simple, _ := base64.StdEncoding.DecodeString("AUHd69PrJQEv+oKTfZ8l+WROBHuy9HKrbFCJu7U1iK2iiEy1vMU5EfMtjc+VSHM7aU0SDbak5IUZRVno2P5mjSafAQ==")
+
+	_, scriptPubKey, err := segwitScript(MainNet, bip322P2TRAddress)
+	if err != nil {
+		t.Fatalf("segwitScript() error = %v", err)
+	}
+
+	toSign, err := bip322ToSign(bip322ToSpend(scriptPubKey, []byte("Hello World")), simple)
+	if err != nil {
+		t.Fatalf("bip322ToSign() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := toSign.Serialize(&buf); err != nil {
+		t.Fatalf("Serialize() error = %v", err)
+	}
+	full := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	got, err := VerifyBIP322(MainNet, bip322P2TRAddress, full, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf("VerifyBIP322() error = %v", err)
+	}
+	if !got.IsValid || got.AddressType != "BitcoinP2TR" {
+		t.Errorf("VerifyBIP322() = %v %v, want true BitcoinP2TR", got.IsValid, got.AddressType)
+	}
+
+	if _, err := VerifyBIP322(MainNet, bip322P2TRAddress, full, []byte("Hello World!")); err == nil {
+		t.Errorf("VerifyBIP322() expected error for full signature of a different message")
+	}
+}
@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	cashAddrCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	// cashAddrTypeP2PKH version byte type bits for a 160 bit public key hash
+	cashAddrTypeP2PKH = 0x00
+)
+
+// cashAddrPrefixes Bitcoin Cash shares Bitcoin's chain params so the CashAddr prefix is looked up by network magic
+var cashAddrPrefixes = map[wire.BitcoinNet]string{
+	wire.MainNet:  "bitcoincash",
+	wire.TestNet3: "bchtest",
+	wire.TestNet:  "bchreg",
+}
+
+// cashAddrPrefix returns the CashAddr prefix (without the colon) for the params or empty string if there is none
+func cashAddrPrefix(params *chaincfg.Params) string {
+	if params == nil {
+		return ""
+	}
+
+	return cashAddrPrefixes[params.Net]
+}
+
+func deriveCashAddr(publicKey *btcec.PublicKey, params *chaincfg.Params) (string, error) {
+	return encodeCashAddr(cashAddrPrefix(params), cashAddrTypeP2PKH, btcutil.Hash160(publicKey.SerializeUncompressed()))
+}
+
+func deriveCashAddrCompressed(publicKey *btcec.PublicKey, params *chaincfg.Params) (string, error) {
+	return encodeCashAddr(cashAddrPrefix(params), cashAddrTypeP2PKH, btcutil.Hash160(publicKey.SerializeCompressed()))
+}
+
+// encodeCashAddr encodes a 160 bit hash as a CashAddr eg bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a
+func encodeCashAddr(prefix string, addrType byte, hash []byte) (string, error) {
+	if prefix == "" {
+		return "", fmt.Errorf("no CashAddr prefix for network")
+	}
+	if len(hash) != 20 {
+		return "", fmt.Errorf("CashAddr hash must be 20 bytes not %d", len(hash))
+	}
+
+	// version byte: 1 reserved bit, 4 type bits and 3 size bits (0 = 160 bits)
+	payload := convertBits(append([]byte{addrType << 3}, hash...), 8, 5)
+
+	checksum := cashAddrPolymod(append(append(cashAddrPrefixData(prefix), payload...), make([]byte, 8)...))
+	for i := 0; i < 8; i++ {
+		payload = append(payload, byte((checksum>>(5*(7-i)))&0x1f))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	sb.WriteString(":")
+	for _, b := range payload {
+		sb.WriteByte(cashAddrCharset[b])
+	}
+
+	return sb.String(), nil
+}
+
+// isCashAddrForParams returns true if the address starts with the CashAddr prefix for the params
+func isCashAddrForParams(address string, params *chaincfg.Params) bool {
+	prefix := cashAddrPrefix(params)
+
+	return prefix != "" && strings.HasPrefix(strings.ToLower(address), prefix+":")
+}
+
+// cashAddrPrefixData the lower 5 bits of each prefix character followed by a zero separator
+func cashAddrPrefixData(prefix string) []byte {
+	data := make([]byte, 0, len(prefix)+1)
+	for i := 0; i < len(prefix); i++ {
+		data = append(data, prefix[i]&0x1f)
+	}
+
+	return append(data, 0)
+}
+
+func cashAddrPolymod(values []byte) uint64 {
+	generators := []uint64{0x98f2bc8e61, 0x79b76d99e2, 0xf33e5fb3c4, 0xae2eabe2a8, 0x1e4f43e470}
+
+	c := uint64(1)
+	for _, d := range values {
+		c0 := byte(c >> 35)
+		c = ((c & 0x07ffffffff) << 5) ^ uint64(d)
+
+		for i, generator := range generators {
+			if c0&(1<<i) != 0 {
+				c ^= generator
+			}
+		}
+	}
+
+	return c ^ 1
+}
+
+// convertBits regroups data from fromBits to toBits sized groups, padding the final group with zeros
+func convertBits(data []byte, fromBits, toBits uint) []byte {
+	var (
+		acc    uint
+		bits   uint
+		result []byte
+	)
+
+	maxValue := uint(1<<toBits) - 1
+	for _, value := range data {
+		acc = (acc << fromBits) | uint(value)
+		bits += fromBits
+
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte((acc>>bits)&maxValue))
+		}
+	}
+
+	if bits > 0 {
+		result = append(result, byte((acc<<(toBits-bits))&maxValue))
+	}
+
+	return result
+}
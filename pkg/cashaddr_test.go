@@ -0,0 +1,39 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/base58"
+)
+
+func Test_encodeCashAddr(t *testing.T) {
+	// Test vectors from the CashAddr specification (legacy address to CashAddr)
+	tests := []struct {
+		legacy string
+		want   string
+	}{
+		{"1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a"},
+		{"1KXrWXciRDZUpQwQmuM1DbwsKDLYAYsVLR", "bitcoincash:qr95sy3j9xwd2ap32xkykttr4cvcu7as4y0qverfuy"},
+		{"16w1D5WRVKJuZUsSRzdLp9w3YGcgoxDXb", "bitcoincash:qqq3728yw0y47sqn6l2na30mcw6zm78dzqre909m2r"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.legacy, func(t *testing.T) {
+			hash, _, err := base58.CheckDecode(tt.legacy)
+			if err != nil {
+				t.Fatalf("CheckDecode() error = %v", err)
+			}
+
+			got, err := encodeCashAddr("bitcoincash", cashAddrTypeP2PKH, hash)
+			if err != nil {
+				t.Fatalf("encodeCashAddr() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("encodeCashAddr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := encodeCashAddr("", cashAddrTypeP2PKH, make([]byte, 20)); err == nil {
+		t.Errorf("encodeCashAddr() expected error for empty prefix")
+	}
+}
@@ -18,7 +18,7 @@ var litecoinMainNetParams = chaincfg.Params{
 
 	// Address encoding magics
 	PubKeyHashAddrID:        0x30, // starts with L
-	ScriptHashAddrID:        0x50, // starts with M
+	ScriptHashAddrID:        0x32, // starts with M
 	PrivateKeyID:            0xB0, // starts with 6 (uncompressed) or T (compressed)
 	WitnessPubKeyHashAddrID: 0x06, // starts with p2
 	WitnessScriptHashAddrID: 0x0A, // starts with 7Xh
@@ -36,20 +36,125 @@ var litecoinMainNetParams = chaincfg.Params{
 var dogecoinMainNetParams = chaincfg.Params{
 	Name:        "mainnet",
 	Net:         wire.MainNet,
-	DefaultPort: "9333",
+	DefaultPort: "22556",
+
+	// Dogecoin has no segwit so no Bech32 HRP
+	Bech32HRPSegwit: "",
 
 	// Address encoding magics
-	PubKeyHashAddrID: 30, // starts with D
+	PubKeyHashAddrID: 0x1e, // starts with D
+	ScriptHashAddrID: 0x16, // starts with 9 or A
+	PrivateKeyID:     0x9e, // starts with 6 (uncompressed) or Q (compressed)
+
+	// BIP32 hierarchical deterministic extended key magics
+	HDPrivateKeyID: [4]byte{0x02, 0xfa, 0xc3, 0x98}, // starts with dgpv
+	HDPublicKeyID:  [4]byte{0x02, 0xfa, 0xca, 0xfd}, // starts with dgub
+
+	// BIP44 coin type used in the hierarchical deterministic path for
+	// address generation.
+	HDCoinType: 3,
+}
+
+// litecoinTestNetParams defines the network parameters for the Litecoin test network (version 4).
+var litecoinTestNetParams = chaincfg.Params{
+	Name:        "testnet4",
+	Net:         wire.TestNet3,
+	DefaultPort: "19335",
 
-	// TODO: update below here
 	// Human-readable part for Bech32 encoded segwit addresses, as defined in
 	// BIP 173.
-	Bech32HRPSegwit: "ltc", // always ltc1 for main net
+	Bech32HRPSegwit: "tltc", // always tltc1 for test net
 
-	ScriptHashAddrID:        0x50, // starts with M
-	PrivateKeyID:            0xB0, // starts with 6 (uncompressed) or T (compressed)
-	WitnessPubKeyHashAddrID: 0x06, // starts with p2
-	WitnessScriptHashAddrID: 0x0A, // starts with 7Xh
+	// Address encoding magics
+	PubKeyHashAddrID: 0x6f, // starts with m or n
+	ScriptHashAddrID: 0x3a, // starts with Q
+	PrivateKeyID:     0xef, // starts with 9 (uncompressed) or c (compressed)
+
+	// BIP32 hierarchical deterministic extended key magics
+	HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // starts with tprv
+	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // starts with tpub
+
+	// BIP44 coin type used in the hierarchical deterministic path for
+	// address generation.
+	HDCoinType: 1,
+}
+
+// litecoinRegTestParams defines the network parameters for the Litecoin regression test network.
+var litecoinRegTestParams = chaincfg.Params{
+	Name:        "regtest",
+	Net:         wire.TestNet,
+	DefaultPort: "19444",
+
+	// Human-readable part for Bech32 encoded segwit addresses, as defined in
+	// BIP 173.
+	Bech32HRPSegwit: "rltc", // always rltc1 for reg test net
+
+	// Address encoding magics
+	PubKeyHashAddrID: 0x6f, // starts with m or n
+	ScriptHashAddrID: 0x3a, // starts with Q
+	PrivateKeyID:     0xef, // starts with 9 (uncompressed) or c (compressed)
+
+	// BIP32 hierarchical deterministic extended key magics
+	HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // starts with tprv
+	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // starts with tpub
+
+	// BIP44 coin type used in the hierarchical deterministic path for
+	// address generation.
+	HDCoinType: 1,
+}
+
+// dogecoinTestNetParams defines the network parameters for the Dogecoin test network.
+var dogecoinTestNetParams = chaincfg.Params{
+	Name:        "testnet3",
+	Net:         wire.TestNet3,
+	DefaultPort: "44556",
+
+	// Address encoding magics
+	PubKeyHashAddrID: 0x71, // starts with n
+	ScriptHashAddrID: 0xc4, // starts with 2
+	PrivateKeyID:     0xf1, // starts with 9 (uncompressed) or c (compressed)
+
+	// BIP32 hierarchical deterministic extended key magics
+	HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // starts with tprv
+	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // starts with tpub
+
+	// BIP44 coin type used in the hierarchical deterministic path for
+	// address generation.
+	HDCoinType: 1,
+}
+
+// dogecoinRegTestParams defines the network parameters for the Dogecoin regression test network.
+var dogecoinRegTestParams = chaincfg.Params{
+	Name:        "regtest",
+	Net:         wire.TestNet,
+	DefaultPort: "18444",
+
+	// Address encoding magics
+	PubKeyHashAddrID: 0x6f, // starts with m or n
+	ScriptHashAddrID: 0xc4, // starts with 2
+	PrivateKeyID:     0xef, // starts with 9 (uncompressed) or c (compressed)
+
+	// BIP32 hierarchical deterministic extended key magics
+	HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // starts with tprv
+	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // starts with tpub
+
+	// BIP44 coin type used in the hierarchical deterministic path for
+	// address generation.
+	HDCoinType: 1,
+}
+
+// dashMainNetParams defines the network parameters for the main Dash network.
+var dashMainNetParams = chaincfg.Params{
+	Name:        "mainnet",
+	Net:         wire.MainNet,
+	DefaultPort: "9999",
+
+	// Dash has no segwit so no Bech32 HRP
+
+	// Address encoding magics
+	PubKeyHashAddrID: 0x4c, // starts with X
+	ScriptHashAddrID: 0x10, // starts with 7
+	PrivateKeyID:     0xcc, // starts with 7 (uncompressed) or X (compressed)
 
 	// BIP32 hierarchical deterministic extended key magics
 	HDPrivateKeyID: [4]byte{0x04, 0x88, 0xad, 0xe4}, // starts with xprv
@@ -57,5 +162,45 @@ var dogecoinMainNetParams = chaincfg.Params{
 
 	// BIP44 coin type used in the hierarchical deterministic path for
 	// address generation.
-	HDCoinType: 3,
+	HDCoinType: 5,
+}
+
+// dashTestNetParams defines the network parameters for the Dash test network.
+var dashTestNetParams = chaincfg.Params{
+	Name:        "testnet3",
+	Net:         wire.TestNet3,
+	DefaultPort: "19999",
+
+	// Address encoding magics
+	PubKeyHashAddrID: 0x8c, // starts with y
+	ScriptHashAddrID: 0x13, // starts with 8 or 9
+	PrivateKeyID:     0xef, // starts with 9 (uncompressed) or c (compressed)
+
+	// BIP32 hierarchical deterministic extended key magics
+	HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // starts with tprv
+	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // starts with tpub
+
+	// BIP44 coin type used in the hierarchical deterministic path for
+	// address generation.
+	HDCoinType: 1,
+}
+
+// dashRegTestParams defines the network parameters for the Dash regression test network.
+var dashRegTestParams = chaincfg.Params{
+	Name:        "regtest",
+	Net:         wire.TestNet,
+	DefaultPort: "19899",
+
+	// Address encoding magics
+	PubKeyHashAddrID: 0x8c, // starts with y
+	ScriptHashAddrID: 0x13, // starts with 8 or 9
+	PrivateKeyID:     0xef, // starts with 9 (uncompressed) or c (compressed)
+
+	// BIP32 hierarchical deterministic extended key magics
+	HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // starts with tprv
+	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // starts with tpub
+
+	// BIP44 coin type used in the hierarchical deterministic path for
+	// address generation.
+	HDCoinType: 1,
 }
@@ -0,0 +1,175 @@
+package pkg
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	bitcoinCoin = &Coin{
+		Name:         Bitcoin,
+		Symbol:       "BTC",
+		URIScheme:    "bitcoin",
+		MessageMagic: "Bitcoin Signed Message:\n",
+		Networks: map[Network]*chaincfg.Params{
+			MainNet: &chaincfg.MainNetParams,
+			TestNet: &chaincfg.TestNet3Params,
+			SigNet:  &chaincfg.SigNetParams,
+			RegTest: &chaincfg.RegressionNetParams,
+		},
+	}
+
+	ethereumCoin = &Coin{
+		Name:      Ethereum,
+		Symbol:    "ETH",
+		URIScheme: "ethereum",
+	}
+
+	litecoinCoin = &Coin{
+		Name:         Litecoin,
+		Symbol:       "LTC",
+		URIScheme:    "litecoin",
+		MessageMagic: "Litecoin Signed Message:\n",
+		Networks: map[Network]*chaincfg.Params{
+			MainNet: &litecoinMainNetParams,
+			TestNet: &litecoinTestNetParams,
+			RegTest: &litecoinRegTestParams,
+		},
+	}
+
+	dogecoinCoin = &Coin{
+		Name:         Dogecoin,
+		Symbol:       "DOGE",
+		URIScheme:    "dogecoin",
+		MessageMagic: "Dogecoin Signed Message:\n",
+		Networks: map[Network]*chaincfg.Params{
+			MainNet: &dogecoinMainNetParams,
+			TestNet: &dogecoinTestNetParams,
+			RegTest: &dogecoinRegTestParams,
+		},
+	}
+
+	// bitcoinCashCoin shares Bitcoin's version bytes, WIF prefix and message magic. Addresses use CashAddr
+	bitcoinCashCoin = &Coin{
+		Name:         BitcoinCash,
+		Symbol:       "BCH",
+		MessageMagic: "Bitcoin Signed Message:\n",
+		Networks: map[Network]*chaincfg.Params{
+			MainNet: &chaincfg.MainNetParams,
+			TestNet: &chaincfg.TestNet3Params,
+			RegTest: &chaincfg.RegressionNetParams,
+		},
+	}
+
+	// dashCoin signs messages with the magic of its Darkcoin origins
+	dashCoin = &Coin{
+		Name:         Dash,
+		Symbol:       "DASH",
+		URIScheme:    "dash",
+		MessageMagic: "DarkCoin Signed Message:\n",
+		Networks: map[Network]*chaincfg.Params{
+			MainNet: &dashMainNetParams,
+			TestNet: &dashTestNetParams,
+			RegTest: &dashRegTestParams,
+		},
+	}
+)
+
+func init() {
+	for _, coin := range []*Coin{bitcoinCoin, ethereumCoin, litecoinCoin, dogecoinCoin, bitcoinCashCoin, dashCoin} {
+		mustRegister(RegisterCoin(coin))
+	}
+
+	for _, addressType := range []AddressType{
+		{ID: "BitcoinP2PKH", Coin: bitcoinCoin, Name: TypeP2PKH, Derive: deriveP2PKH, Key: wifKey("%s", false)},
+		{ID: "BitcoinP2PKHCompressed", Coin: bitcoinCoin, Name: TypeP2PKHCompressed, Derive: deriveP2PKHCompressed, Key: wifKey("%s", true)},
+		{ID: "BitcoinP2SHP2WPKH", Coin: bitcoinCoin, Name: TypeP2SHP2WPKH, Derive: deriveP2SHP2WPKH, Key: wifKey("p2wpkh-p2sh:%s", true)},
+		{ID: "BitcoinP2WPKH", Coin: bitcoinCoin, Name: TypeP2WPKH, Derive: deriveP2WPKH, Key: wifKey("p2wpkh:%s", true)},
+		{ID: "BitcoinP2TR", Coin: bitcoinCoin, Name: TypeP2TR, Derive: deriveP2TR, Key: wifKey("tr(%s)", true)},
+		{ID: "Ethereum", Coin: ethereumCoin, Derive: deriveEthereum, Key: ethereumKey},
+		{ID: "LitecoinP2PKH", Coin: litecoinCoin, Name: TypeP2PKH, Derive: deriveP2PKH, Key: wifKey("%s", false)},
+		{ID: "LitecoinP2PKHCompressed", Coin: litecoinCoin, Name: TypeP2PKHCompressed, Derive: deriveP2PKHCompressed, Key: wifKey("%s", true)},
+		{ID: "LitecoinP2SHP2WPKH", Coin: litecoinCoin, Name: TypeP2SHP2WPKH, Derive: deriveP2SHP2WPKH, Key: wifKey("p2wpkh-p2sh:%s", true)},
+		{ID: "LitecoinP2WPKH", Coin: litecoinCoin, Name: TypeP2WPKH, Derive: deriveP2WPKH, Key: wifKey("p2wpkh:%s", true)},
+		{ID: "LitecoinP2TR", Coin: litecoinCoin, Name: TypeP2TR, Derive: deriveP2TR, Key: wifKey("tr(%s)", true)},
+		{ID: "DogecoinP2PKH", Coin: dogecoinCoin, Name: TypeP2PKH, Derive: deriveP2PKH, Key: wifKey("%s", false)},
+		{ID: "BitcoinCashP2PKH", Coin: bitcoinCashCoin, Name: TypeP2PKH, Derive: deriveCashAddr, Key: wifKey("%s", false)},
+		{ID: "BitcoinCashP2PKHCompressed", Coin: bitcoinCashCoin, Name: TypeP2PKHCompressed, Derive: deriveCashAddrCompressed, Key: wifKey("%s", true)},
+		{ID: "DashP2PKH", Coin: dashCoin, Name: TypeP2PKH, Derive: deriveP2PKH, Key: wifKey("%s", false)},
+		{ID: "DashP2PKHCompressed", Coin: dashCoin, Name: TypeP2PKHCompressed, Derive: deriveP2PKHCompressed, Key: wifKey("%s", true)},
+	} {
+		mustRegister(RegisterAddressType(addressType))
+	}
+}
+
+func mustRegister(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func deriveP2PKH(publicKey *btcec.PublicKey, params *chaincfg.Params) (string, error) {
+	return encodeAddress(btcutil.NewAddressPubKeyHash(btcutil.Hash160(publicKey.SerializeUncompressed()), params))
+}
+
+func deriveP2PKHCompressed(publicKey *btcec.PublicKey, params *chaincfg.Params) (string, error) {
+	return encodeAddress(btcutil.NewAddressPubKeyHash(btcutil.Hash160(publicKey.SerializeCompressed()), params))
+}
+
+func deriveP2SHP2WPKH(publicKey *btcec.PublicKey, params *chaincfg.Params) (string, error) {
+	return encodeAddress(btcutil.NewAddressScriptHash(p2wpkhScript(btcutil.Hash160(publicKey.SerializeCompressed())), params))
+}
+
+func deriveP2WPKH(publicKey *btcec.PublicKey, params *chaincfg.Params) (string, error) {
+	if params.Bech32HRPSegwit == "" {
+		return "", fmt.Errorf("%s has no segwit support", params.Name)
+	}
+
+	return encodeAddress(btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(publicKey.SerializeCompressed()), params))
+}
+
+// deriveP2TR BIP86 key-path only taproot output key (no script tree)
+func deriveP2TR(publicKey *btcec.PublicKey, params *chaincfg.Params) (string, error) {
+	if params.Bech32HRPSegwit == "" {
+		return "", fmt.Errorf("%s has no segwit support", params.Name)
+	}
+
+	taprootKey := schnorr.SerializePubKey(txscript.ComputeTaprootKeyNoScript(publicKey))
+
+	return encodeAddress(btcutil.NewAddressTaproot(taprootKey, params))
+}
+
+func deriveEthereum(publicKey *btcec.PublicKey, _ *chaincfg.Params) (string, error) {
+	return crypto.PubkeyToAddress(*publicKey.ToECDSA()).Hex(), nil
+}
+
+func encodeAddress[A btcutil.Address](address A, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+
+	return address.String(), nil
+}
+
+// p2wpkhScript returns the version 0 witness program (OP_0 <20 byte hash>) used as the P2SH redeem script
+// for nested segwit (BIP49) addresses
+func p2wpkhScript(pkHash []byte) []byte {
+	return append([]byte{txscript.OP_0, txscript.OP_DATA_20}, pkHash...)
+}
+
+// wifKey returns a KeyFunc that formats the WIF (with import prefix eg p2wpkh:%s) for the coin params
+func wifKey(format string, compress bool) KeyFunc {
+	return func(secretExponentHex string, params *chaincfg.Params) string {
+		return fmt.Sprintf(format, ToWif(fmt.Sprintf("%02x", params.PrivateKeyID), secretExponentHex, compress))
+	}
+}
+
+func ethereumKey(secretExponentHex string, _ *chaincfg.Params) string {
+	return "0x" + secretExponentHex
+}
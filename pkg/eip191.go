@@ -0,0 +1,93 @@
+package pkg
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// eip191Prefix the EIP-191 (version 0x45) personal_sign prefix, followed by the message length in decimal
+const eip191Prefix = "\x19Ethereum Signed Message:\n"
+
+// EIP191MessageHash returns the keccak256 hash of the prefixed message that personal_sign signs
+func EIP191MessageHash(message []byte) []byte {
+	return crypto.Keccak256([]byte(eip191Prefix+strconv.Itoa(len(message))), message)
+}
+
+// VerifyEIP191 verifies an Ethereum personal_sign signature (65 bytes r, s and v as hex with or without 0x) for an
+// 0x address. The public key is recovered so the addresses of every other coin can be derived
+func VerifyEIP191(network Network, address string, signature string, message []byte) (VerifiedMessage, error) {
+	if !isHexAddress(address) {
+		return VerifiedMessage{}, fmt.Errorf("'%s' is not an Ethereum address", address)
+	}
+
+	if !isEthereumAddress(address) {
+		return VerifiedMessage{}, fmt.Errorf("'%s' has an invalid EIP-55 checksum, check the address for typos", address)
+	}
+
+	signatureBytes, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(signature, "0x"), "0X"))
+	if err != nil {
+		return VerifiedMessage{}, fmt.Errorf("Ethereum signature must be hex: %w", err)
+	}
+
+	if len(signatureBytes) != expectedSignatureLen {
+		return VerifiedMessage{}, fmt.Errorf("signature bytes wrong length expected 65 got %d", len(signatureBytes))
+	}
+
+	// Wallets use v of 27 or 28, crypto.SigToPub wants the recovery id 0 or 1
+	recoverable := append([]byte{}, signatureBytes...)
+	if recoverable[64] >= 27 {
+		recoverable[64] -= 27
+	}
+
+	if recoverable[64] > 1 {
+		return VerifiedMessage{}, fmt.Errorf("signature v %d must be 27 or 28", signatureBytes[64])
+	}
+
+	publicKey, err := crypto.SigToPub(EIP191MessageHash(message), recoverable)
+	if err != nil {
+		return VerifiedMessage{}, err
+	}
+
+	if crypto.PubkeyToAddress(*publicKey) != common.HexToAddress(address) {
+		return VerifiedMessage{}, errors.New("Invalid signature address not match")
+	}
+
+	return VerifiedMessage{
+		Address:      address,
+		Signature:    signatureBytes,
+		Message:      message,
+		IsValid:      true,
+		PublicKeyHex: hex.EncodeToString(crypto.FromECDSAPub(publicKey)),
+		Network:      network,
+		AddressType:  addressTypeID(ethereumCoin, ""),
+		MessageMagic: eip191Prefix,
+	}, nil
+}
+
+// isHexAddress returns true for 0x followed by 40 hex digits in any case
+func isHexAddress(address string) bool {
+	return strings.HasPrefix(strings.ToLower(address), "0x") && common.IsHexAddress(address)
+}
+
+// isEthereumAddress returns true for an isHexAddress whose digits are all lower or all upper case (no checksum) or
+// mixed case with a valid EIP-55 checksum
+func isEthereumAddress(address string) bool {
+	if !isHexAddress(address) {
+		return false
+	}
+
+	digits := address[2:]
+	if digits == strings.ToLower(digits) || digits == strings.ToUpper(digits) {
+		return true
+	}
+
+	mixed, err := common.NewMixedcaseAddressFromString("0x" + digits)
+
+	return err == nil && mixed.ValidChecksum()
+}
@@ -0,0 +1,86 @@
+package pkg
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestVerifyEIP191(t *testing.T) {
+	const (
+		secretHex = "dc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3"
+		address   = "0xCb19D769c583599DbD7D6D78Eb3279a362672747"
+		message   = "Hello World"
+	)
+
+	privateKey, err := crypto.HexToECDSA(secretHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature, err := crypto.Sign(EIP191MessageHash([]byte(message)), privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Wallets return v as 27 or 28
+	walletSignature := append([]byte{}, signature...)
+	walletSignature[64] += 27
+
+	tests := []struct {
+		name      string
+		address   string
+		signature string
+		message   string
+		wantErr   bool
+	}{
+		{"wallet v", address, "0x" + hex.EncodeToString(walletSignature), message, false},
+		{"recovery id v", address, hex.EncodeToString(signature), message, false},
+		{"lower case address", strings.ToLower(address), "0x" + hex.EncodeToString(walletSignature), message, false},
+		{"upper case address", "0X" + strings.ToUpper(address[2:]), "0x" + hex.EncodeToString(walletSignature), message, false},
+		{"bad checksum", "0xcB19D769c583599DbD7D6D78Eb3279a362672747", "0x" + hex.EncodeToString(walletSignature), message, true},
+		{"other message", address, "0x" + hex.EncodeToString(walletSignature), "Hello World!", true},
+		{"other address", "0x5D0a9F69035Be4275204f9eBbd5cC049e42429c6", "0x" + hex.EncodeToString(walletSignature), message, true},
+		{"not hex", address, "HwPlEOxTxs62ruMHZvamv0wmUlbbaY/2ZSqw9Hpdw+FWfgXuSxQ9x55ceSiFyvnlpiZjt+KIhSYnhGnCv8iDe5o=", message, true},
+		{"short", address, "0x" + hex.EncodeToString(walletSignature[:64]), message, true},
+		{"not an ethereum address", "1A9GW5SxA4qW5ZhymW1uPaj6N4vnVJVWZW", "0x" + hex.EncodeToString(walletSignature), message, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyEIP191(MainNet, tt.address, tt.signature, []byte(tt.message))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyEIP191() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !got.IsValid || got.AddressType != "Ethereum" || got.PublicKeyHex != hex.EncodeToString(crypto.FromECDSAPub(&privateKey.PublicKey)) ||
+				MagicName(got.MessageMagic) != "Ethereum Signed Message" {
+				t.Errorf("VerifyEIP191() = %+v", got)
+			}
+
+			// The recovered key derives the same Bitcoin address as keyconv
+			addresses, err := GetAddresses(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if addresses.Map()["BitcoinP2PKH"] != "13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu" {
+				t.Errorf("GetAddresses() = %v", addresses.Map())
+			}
+		})
+	}
+}
+
+func TestVerifyMessageEIP191(t *testing.T) {
+	// An 0x address is verified as personal_sign
+	privateKey, _ := crypto.HexToECDSA("dc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3")
+	signature, _ := crypto.Sign(EIP191MessageHash([]byte("line one\nline two")), privateKey)
+
+	got, err := VerifyMessage(MainNet, "0xCb19D769c583599DbD7D6D78Eb3279a362672747", hex.EncodeToString(signature), "line one\r\nline two")
+	if err != nil || got.AddressType != "Ethereum" {
+		t.Errorf("VerifyMessage() = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,242 @@
+// Package emulator makes directory trees that look like a mounted Opendime so device facing features (discovery,
+// inspect, challenge) can be tested and demonstrated without real hardware
+package emulator
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// Defaults for Options
+const (
+	DefaultFirmware = "2.4.0"
+	DefaultGitRef   = "master@e233940e"
+	serialSize      = 16
+)
+
+// DefaultBuildTime build time of the emulated firmware
+var DefaultBuildTime = time.Date(2019, 2, 7, 13, 2, 55, 0, time.UTC)
+
+// Options for a new emulated Opendime. The zero value is a sealed Bitcoin mainnet Opendime with a fresh key
+type Options struct {
+	Network pkg.Network
+	// Coin name eg Litecoin (default Bitcoin). Must have a message magic
+	Coin string
+	// Key WIF private key, empty to generate one. Opendimes use uncompressed keys
+	Key string
+	// Serial, Firmware and GitRef for the statement, random/defaults if empty
+	Serial   string
+	Firmware string
+	GitRef   string
+	Unsealed bool
+}
+
+// Device an emulated Opendime
+type Device struct {
+	Dir      string
+	Network  pkg.Network
+	Coin     string
+	Key      string
+	Address  string
+	Serial   string
+	Firmware string
+	GitRef   string
+	Nonce    string
+	Unsealed bool
+}
+
+// Create writes a new emulated Opendime to dir (made if missing)
+func Create(dir string, options Options) (*Device, error) {
+	device, err := New(options)
+	if err != nil {
+		return nil, err
+	}
+
+	device.Dir = dir
+
+	nonce := make([]byte, pkg.ChallengeNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "advanced"), 0o755); err != nil {
+		return nil, err
+	}
+
+	if err := device.Sign(hex.EncodeToString(nonce)); err != nil {
+		return nil, err
+	}
+
+	return device, device.writeFiles()
+}
+
+// New makes an emulated Opendime without writing it anywhere, use SignedMessage for its verify.txt
+func New(options Options) (*Device, error) {
+	device := &Device{
+		Network:  options.Network,
+		Coin:     options.Coin,
+		Key:      options.Key,
+		Serial:   options.Serial,
+		Firmware: options.Firmware,
+		GitRef:   options.GitRef,
+		Unsealed: options.Unsealed,
+	}
+
+	if device.Network == "" {
+		device.Network = pkg.MainNet
+	}
+	if device.Coin == "" {
+		device.Coin = pkg.Bitcoin
+	}
+	if device.Firmware == "" {
+		device.Firmware = DefaultFirmware
+	}
+	if device.GitRef == "" {
+		device.GitRef = DefaultGitRef
+	}
+
+	if pkg.GetCoin(device.Coin) == nil || pkg.GetCoin(device.Coin).MessageMagic == "" {
+		return nil, fmt.Errorf("coin '%s' can not be emulated, it has no signed messages", device.Coin)
+	}
+
+	if device.Key == "" {
+		privateKey, err := btcec.NewPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+
+		device.Key = pkg.ToWif(pkg.WifPrefixHex(device.Network, device.Coin), hex.EncodeToString(privateKey.Serialize()), false)
+	}
+
+	if device.Serial == "" {
+		serial := make([]byte, serialSize)
+		if _, err := rand.Read(serial); err != nil {
+			return nil, err
+		}
+
+		device.Serial = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(serial)
+	}
+
+	return device, nil
+}
+
+// Statement the message the Opendime signs for the nonce
+func (d *Device) Statement(nonce string) string {
+	lines := []string{
+		fmt.Sprintf("Nonce: %s  Serial: %s", nonce, d.Serial),
+		fmt.Sprintf("Version: %s time=%s git=%s coin=%s", d.Firmware, DefaultBuildTime.Format(pkg.StatementTimeLayout),
+			d.GitRef, pkg.GetCoin(d.Coin).Symbol),
+	}
+
+	if d.Unsealed {
+		lines = append([]string{pkg.UnsealedBanner}, lines...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// SignedMessage signs a statement with the nonce and returns the contents of verify.txt
+func (d *Device) SignedMessage(nonce string) (string, error) {
+	message := d.Statement(nonce)
+
+	address, signature, err := pkg.SignMessage(d.Network, d.Coin, d.Key, message)
+	if err != nil {
+		return "", err
+	}
+
+	d.Address = address
+	d.Nonce = nonce
+
+	return pkg.FormatSignedMessage(d.Coin, address, signature, message), nil
+}
+
+// Sign regenerates verify.txt signing a statement with the nonce
+func (d *Device) Sign(nonce string) error {
+	signed, err := d.SignedMessage(nonce)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(d.Dir, pkg.VerifyTxtPath), []byte(signed), 0o600)
+}
+
+// Unseal marks the Opendime unsealed like removing the end-of-life resistor: verify.txt gets the UNSEALED banner
+// and private-key.txt appears
+func (d *Device) Unseal() error {
+	d.Unsealed = true
+
+	if err := d.Sign(d.Nonce); err != nil {
+		return err
+	}
+
+	return d.writeFiles()
+}
+
+// RespondToChallenge re-signs verify.txt if a new nonce has been written to advanced/nonce.txt. Returns true if it
+// signed a new nonce
+func (d *Device) RespondToChallenge() (bool, error) {
+	contents, err := os.ReadFile(filepath.Join(d.Dir, pkg.ChallengeFilePath))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	nonce := strings.TrimSpace(string(contents))
+	if len(nonce) != pkg.ChallengeNonceSize*2 || nonce == d.Nonce {
+		return false, nil
+	}
+
+	return true, d.Sign(nonce)
+}
+
+// Watch answers challenges every interval until stop is closed
+func (d *Device) Watch(stop <-chan struct{}, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.RespondToChallenge(); err != nil {
+			return err
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Device) writeFiles() error {
+	files := map[string]string{
+		pkg.AddressTxtName: d.Address + "\n",
+		pkg.ReadmeTxtName:  "Opendime " + d.Coin + " credit stick (emulated)\n\nSerial: " + d.Serial + "\n",
+		pkg.IndexHtmName:   `<html><body><a href="https://mempool.space/address/` + d.Address + `">` + d.Address + "</a></body></html>\n",
+		pkg.VersionTxtPath: d.Firmware + " time=" + DefaultBuildTime.Format(pkg.StatementTimeLayout) + " git=" + d.GitRef + "\n",
+	}
+
+	if d.Unsealed {
+		files[pkg.PrivateKeyTxtName] = "Private key for " + d.Address + "\n\n" + d.Key + "\n"
+	}
+
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(d.Dir, name), []byte(contents), 0o600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package emulator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/timchurchard/opendime-utils/pkg"
+)
+
+// Key of emulated Opendimes that need a known address, the same as emulatortest which imports this package
+const (
+	testWif     = "5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC"
+	testAddress = "13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu"
+)
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     Options
+		wantAddress string
+		wantCoin    string
+		wantErr     bool
+	}{
+		{
+			name:        "sealed bitcoin with key",
+			options:     Options{Key: testWif, Serial: "TESTSERIAL"},
+			wantAddress: testAddress,
+			wantCoin:    "BTC",
+		},
+		{name: "unsealed litecoin", options: Options{Coin: pkg.Litecoin, Unsealed: true}, wantCoin: "LTC"},
+		{name: "bitcoin testnet", options: Options{Network: pkg.TestNet}, wantCoin: "BTC"},
+		{name: "ethereum has no signed messages", options: Options{Coin: pkg.Ethereum}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "OPENDIME")
+
+			device, err := Create(dir, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if tt.wantAddress != "" && device.Address != tt.wantAddress {
+				t.Errorf("Create() Address = %v, want %v", device.Address, tt.wantAddress)
+			}
+
+			address, signature, message, err := pkg.ParseVerifyTxt(filepath.Join(dir, pkg.VerifyTxtPath))
+			if err != nil || address != device.Address {
+				t.Fatalf("ParseVerifyTxt() = %v, %v", address, err)
+			}
+
+			if _, err := pkg.VerifyMessage(device.Network, address, signature, message); err != nil {
+				t.Errorf("VerifyMessage() error = %v", err)
+			}
+
+			statement, err := pkg.ParseStatement(message)
+			if err != nil || statement.Coin != tt.wantCoin || statement.Serial != device.Serial ||
+				statement.Nonce != device.Nonce || statement.Unsealed != tt.options.Unsealed {
+				t.Errorf("ParseStatement() = %+v, %v", statement, err)
+			}
+
+			addressTxt, _ := os.ReadFile(filepath.Join(dir, pkg.AddressTxtName))
+			if strings.TrimSpace(string(addressTxt)) != device.Address {
+				t.Errorf("address.txt = %s, want %s", addressTxt, device.Address)
+			}
+
+			privateKeyTxt, err := os.ReadFile(filepath.Join(dir, pkg.PrivateKeyTxtName))
+			if tt.options.Unsealed != (err == nil) || (err == nil && !strings.Contains(string(privateKeyTxt), device.Key)) {
+				t.Errorf("private-key.txt = %s, %v", privateKeyTxt, err)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	const nonce = "1675bf38ec241a2308585ad0"
+
+	device, err := New(Options{Key: testWif, Serial: "TESTSERIAL"})
+	if err != nil || device.Dir != "" {
+		t.Fatalf("New() = %+v, %v", device, err)
+	}
+
+	signed, err := device.SignedMessage(nonce)
+	if err != nil {
+		t.Fatalf("SignedMessage() error = %v", err)
+	}
+
+	messages, err := pkg.ParseSignedMessages(strings.NewReader(signed))
+	if err != nil || len(messages) != 1 || messages[0].Address != testAddress || device.Nonce != nonce {
+		t.Fatalf("ParseSignedMessages() = %+v, %v", messages, err)
+	}
+
+	if _, err := pkg.VerifyMessage(pkg.MainNet, messages[0].Address, messages[0].Signature, messages[0].Message); err != nil {
+		t.Errorf("VerifyMessage() error = %v", err)
+	}
+}
+
+func TestDevice_RespondToChallenge(t *testing.T) {
+	const nonce = "00112233445566778899aabb"
+
+	dir := t.TempDir()
+
+	device, err := Create(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if signed, err := device.RespondToChallenge(); signed || err != nil {
+		t.Errorf("RespondToChallenge() without challenge = %v, %v", signed, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, pkg.ChallengeFilePath), []byte(nonce), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if signed, err := device.RespondToChallenge(); !signed || err != nil {
+		t.Errorf("RespondToChallenge() = %v, %v", signed, err)
+	}
+
+	// The same nonce is only signed once
+	if signed, _ := device.RespondToChallenge(); signed {
+		t.Errorf("RespondToChallenge() signed the same nonce twice")
+	}
+
+	_, _, message, _ := pkg.ParseVerifyTxt(filepath.Join(dir, pkg.VerifyTxtPath))
+	if statement, _ := pkg.ParseStatement(message); statement.Nonce != nonce {
+		t.Errorf("verify.txt nonce = %s, want %s", statement.Nonce, nonce)
+	}
+}
+
+func TestDevice_Unseal(t *testing.T) {
+	dir := t.TempDir()
+
+	device, err := Create(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := device.Unseal(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, message, _ := pkg.ParseVerifyTxt(filepath.Join(dir, pkg.VerifyTxtPath))
+	if statement, _ := pkg.ParseStatement(message); !statement.Unsealed {
+		t.Errorf("verify.txt not marked UNSEALED: %s", message)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, pkg.PrivateKeyTxtName)); err != nil {
+		t.Errorf("private-key.txt missing: %v", err)
+	}
+}
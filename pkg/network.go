@@ -0,0 +1,75 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// Network selects which chain parameters are used for addresses, WIFs and signatures
+type Network string
+
+const (
+	// MainNet the default production networks
+	MainNet Network = "mainnet"
+	// TestNet the public test networks (Bitcoin testnet3, Litecoin testnet4 and Dogecoin testnet)
+	TestNet Network = "testnet"
+	// SigNet the Bitcoin signet. Litecoin and Dogecoin do not have a signet
+	SigNet Network = "signet"
+	// RegTest the local regression test networks
+	RegTest Network = "regtest"
+)
+
+// ParseNetwork takes a network name (mainnet, testnet, signet or regtest) and returns the Network
+func ParseNetwork(name string) (Network, error) {
+	switch network := Network(strings.ToLower(strings.TrimSpace(name))); network {
+	case "", "main":
+		return MainNet, nil
+	case "test", "testnet3":
+		return TestNet, nil
+	case MainNet, TestNet, SigNet, RegTest:
+		return network, nil
+	}
+
+	return "", fmt.Errorf("unknown network '%s' must be one of mainnet, testnet, signet or regtest", name)
+}
+
+// WifPrefixHex returns the WIF prefix byte as hex for the coin (eg Bitcoin, Litecoin or Dogecoin) on the network.
+// An empty string is returned when the coin does not exist on the network or has no WIF
+func WifPrefixHex(network Network, coinName string) string {
+	coin := GetCoin(coinName)
+	if coin == nil {
+		return ""
+	}
+
+	params, ok := coin.Params(network)
+	if !ok || params == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%02x", params.PrivateKeyID)
+}
+
+// isAddressForParams returns true if the address has the bech32 HRP or a base58 P2PKH/P2SH version byte of params
+func isAddressForParams(address string, params *chaincfg.Params) bool {
+	if params == nil {
+		return false
+	}
+
+	if params.Bech32HRPSegwit != "" && strings.HasPrefix(strings.ToLower(address), params.Bech32HRPSegwit+"1") {
+		return true
+	}
+
+	if isCashAddrForParams(address, params) {
+		return true
+	}
+
+	_, version, err := base58.CheckDecode(address)
+	if err != nil {
+		return false
+	}
+
+	return version == params.PubKeyHashAddrID || version == params.ScriptHashAddrID
+}
@@ -0,0 +1,58 @@
+package pkg
+
+import (
+	"testing"
+)
+
+func TestParseNetwork(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Network
+		wantErr bool
+	}{
+		{"", MainNet, false},
+		{"mainnet", MainNet, false},
+		{"testnet", TestNet, false},
+		{"Testnet3", TestNet, false},
+		{"signet", SigNet, false},
+		{"regtest", RegTest, false},
+		{"simnet", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNetwork(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseNetwork() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseNetwork() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWifPrefixHex(t *testing.T) {
+	tests := []struct {
+		name    string
+		network Network
+		coin    string
+		want    string
+	}{
+		{"bitcoin mainnet", MainNet, Bitcoin, "80"},
+		{"litecoin mainnet", MainNet, Litecoin, "b0"},
+		{"dogecoin mainnet", MainNet, Dogecoin, "9e"},
+		{"bitcoin testnet", TestNet, Bitcoin, "ef"},
+		{"dogecoin testnet", TestNet, Dogecoin, "f1"},
+		{"bitcoin signet", SigNet, Bitcoin, "ef"},
+		{"litecoin signet", SigNet, Litecoin, ""},
+		{"unknown", MainNet, "Ethereum", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WifPrefixHex(tt.network, tt.coin); got != tt.want {
+				t.Errorf("WifPrefixHex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package pkg
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// openFile a variable so tests can refuse O_NOATIME
+var openFile = os.OpenFile
+
+// openNoAtime opens fn read only with O_NOATIME so reading verify.txt does not write to the Opendime. O_NOATIME
+// fails with EPERM if the caller does not own the file (eg a FAT mount with another uid), then fn is opened without
+func openNoAtime(fn string) (*os.File, error) {
+	file, err := openFile(fn, os.O_RDONLY|syscall.O_NOATIME, 0)
+	if errors.Is(err, syscall.EPERM) {
+		return openFile(fn, os.O_RDONLY, 0)
+	}
+
+	return file, err
+}
@@ -0,0 +1,37 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestOpenNoAtimeNotOwner(t *testing.T) {
+	oldOpenFile := openFile
+	defer func() { openFile = oldOpenFile }()
+
+	// Like a file owned by another uid, O_NOATIME is refused
+	openFile = func(name string, flag int, perm os.FileMode) (*os.File, error) {
+		if flag&syscall.O_NOATIME != 0 {
+			return nil, &os.PathError{Op: "open", Path: name, Err: syscall.EPERM}
+		}
+
+		return os.OpenFile(name, flag, perm)
+	}
+
+	fn := filepath.Join(t.TempDir(), "verify.txt")
+	if err := os.WriteFile(fn, []byte("verify"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := openNoAtime(fn)
+	if err != nil {
+		t.Fatalf("openNoAtime() error = %v", err)
+	}
+	file.Close()
+
+	if _, err := openNoAtime(filepath.Join(t.TempDir(), "missing.txt")); !os.IsNotExist(err) {
+		t.Errorf("openNoAtime() missing error = %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package pkg
+
+import "os"
+
+// openNoAtime opens fn read only. O_NOATIME is Linux only, elsewhere reading may update the access time
+func openNoAtime(fn string) (*os.File, error) {
+	return os.Open(fn)
+}
@@ -0,0 +1,584 @@
+// Package qr is a small QR code encoder (ISO/IEC 18004) so labels can be made without an external service. Text is
+// encoded in byte mode using the smallest version (1 to 40) that fits at the error correction level
+package qr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Level error correction level
+type Level int
+
+// Error correction levels, recovering roughly 7%, 15%, 25% and 30% of the code
+const (
+	L Level = iota
+	M
+	Q
+	H
+)
+
+func (l Level) String() string {
+	return [...]string{"L", "M", "Q", "H"}[l]
+}
+
+// formatBits the two bit error correction level indicator used in the format information
+func (l Level) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+// ParseLevel returns the level for L, M, Q or H
+func ParseLevel(name string) (Level, error) {
+	for level := L; level <= H; level++ {
+		if level.String() == name {
+			return level, nil
+		}
+	}
+
+	return L, fmt.Errorf("QR level must be L, M, Q or H not '%s'", name)
+}
+
+// ErrTooLong the text does not fit in a version 40 code at the level
+var ErrTooLong = errors.New("text too long for a QR code")
+
+const (
+	minVersion = 1
+	maxVersion = 40
+	modeByte   = 0x4
+)
+
+// eccCodewordsPerBlock and numBlocks by level and version (index 0 unused)
+var (
+	eccCodewordsPerBlock = [4][41]int{
+		{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+		{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	}
+	numBlocks = [4][41]int{
+		{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+		{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+		{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+		{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+	}
+)
+
+// Code an encoded QR code. Modules are addressed by x (column) and y (row) from the top left, without the quiet
+// zone of 4 light modules that should surround it
+type Code struct {
+	Version int
+	Level   Level
+	Mask    int
+	Size    int
+
+	modules    []bool
+	isFunction []bool
+}
+
+// Black returns true if the module at x, y is dark. Modules outside the code are light
+func (c *Code) Black(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+		return false
+	}
+
+	return c.modules[y*c.Size+x]
+}
+
+// Encode encodes the text as a QR code at the level
+func Encode(text string, level Level) (*Code, error) {
+	if level < L || level > H {
+		return nil, fmt.Errorf("unknown QR level %d", level)
+	}
+
+	data := []byte(text)
+
+	version := minVersion
+	for ; version <= maxVersion; version++ {
+		if 4+countBits(version)+len(data)*8 <= dataCodewords(version, level)*8 {
+			break
+		}
+	}
+
+	if version > maxVersion {
+		return nil, ErrTooLong
+	}
+
+	code := &Code{Version: version, Level: level, Size: version*4 + 17}
+	code.modules = make([]bool, code.Size*code.Size)
+	code.isFunction = make([]bool, code.Size*code.Size)
+
+	code.drawFunctionPatterns()
+	code.drawCodewords(addECCAndInterleave(encodeData(data, version, level), version, level))
+
+	// Choose the mask with the lowest penalty
+	bestPenalty := -1
+	for mask := 0; mask < 8; mask++ {
+		code.applyMask(mask)
+		code.drawFormatBits(mask)
+
+		if penalty := code.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestPenalty = penalty
+			code.Mask = mask
+		}
+
+		code.applyMask(mask) // XOR again to undo
+	}
+
+	code.applyMask(code.Mask)
+	code.drawFormatBits(code.Mask)
+
+	return code, nil
+}
+
+// countBits the width of the byte mode character count
+func countBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+
+	return 16
+}
+
+// rawDataModules the modules left for data and error correction once the function patterns are drawn
+func rawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+
+		if version >= 7 {
+			result -= 36
+		}
+	}
+
+	return result
+}
+
+func dataCodewords(version int, level Level) int {
+	return rawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*numBlocks[level][version]
+}
+
+// encodeData the mode, count, data and padding as data codewords
+func encodeData(data []byte, version int, level Level) []byte {
+	var bits bitBuffer
+
+	bits.append(modeByte, 4)
+	bits.append(len(data), countBits(version))
+
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	capacity := dataCodewords(version, level) * 8
+
+	// Terminator, then pad to a byte boundary
+	terminator := capacity - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	bits.append(0, (8-len(bits)%8)%8)
+
+	for pad := 0xec; len(bits) < capacity; pad ^= 0xec ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	return bits.bytes()
+}
+
+// addECCAndInterleave splits the data into blocks, adds Reed-Solomon error correction to each and interleaves them
+func addECCAndInterleave(data []byte, version int, level Level) []byte {
+	var (
+		blocks       = numBlocks[level][version]
+		eccLen       = eccCodewordsPerBlock[level][version]
+		rawCodewords = rawDataModules(version) / 8
+		numShort     = blocks - rawCodewords%blocks
+		shortDataLen = rawCodewords/blocks - eccLen
+		divisor      = reedSolomonDivisor(eccLen)
+		dataBlocks   = make([][]byte, blocks)
+		eccBlocks    = make([][]byte, blocks)
+	)
+
+	for i, offset := 0, 0; i < blocks; i++ {
+		length := shortDataLen
+		if i >= numShort {
+			length++
+		}
+
+		dataBlocks[i] = data[offset : offset+length]
+		eccBlocks[i] = reedSolomonRemainder(dataBlocks[i], divisor)
+		offset += length
+	}
+
+	result := make([]byte, 0, rawCodewords)
+
+	for i := 0; i <= shortDataLen; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+
+	for i := 0; i < eccLen; i++ {
+		for _, block := range eccBlocks {
+			result = append(result, block[i])
+		}
+	}
+
+	return result
+}
+
+// reedSolomonDivisor the generator polynomial of the degree, highest power first without the leading 1
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+
+		root = gfMultiply(root, 0x02)
+	}
+
+	return result
+}
+
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+
+		for i := range result {
+			result[i] ^= gfMultiply(divisor[i], factor)
+		}
+	}
+
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func gfMultiply(x, y byte) byte {
+	var z int
+
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11d)
+		z ^= int((y>>i)&1) * int(x)
+	}
+
+	return byte(z)
+}
+
+func (c *Code) set(x, y int, dark, function bool) {
+	c.modules[y*c.Size+x] = dark
+	if function {
+		c.isFunction[y*c.Size+x] = true
+	}
+}
+
+func (c *Code) drawFunctionPatterns() {
+	// Timing patterns
+	for i := 0; i < c.Size; i++ {
+		c.set(6, i, i%2 == 0, true)
+		c.set(i, 6, i%2 == 0, true)
+	}
+
+	// Finder patterns with their separators
+	c.drawFinder(3, 3)
+	c.drawFinder(c.Size-4, 3)
+	c.drawFinder(3, c.Size-4)
+
+	// Alignment patterns, except where they would overlap the finders
+	positions := alignmentPositions(c.Version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1, true)
+				}
+			}
+		}
+	}
+
+	// Reserve the format bits (drawn once the mask is chosen) and draw the version
+	c.drawFormatBits(0)
+	c.drawVersion()
+}
+
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			distance := max(abs(dx), abs(dy))
+			if xx, yy := x+dx, y+dy; xx >= 0 && xx < c.Size && yy >= 0 && yy < c.Size {
+				c.set(xx, yy, distance != 2 && distance != 4, true)
+			}
+		}
+	}
+}
+
+// alignmentPositions the row and column centres of the alignment patterns
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, position := numAlign-1, version*4+17-7; i >= 1; i, position = i-1, position-step {
+		result[i] = position
+	}
+
+	return result
+}
+
+// formatBits the 15 bit BCH protected level and mask
+func formatBits(level Level, mask int) int {
+	data := level.formatBits()<<3 | mask
+
+	remainder := data
+	for i := 0; i < 10; i++ {
+		remainder = (remainder << 1) ^ ((remainder >> 9) * 0x537)
+	}
+
+	return (data<<10 | remainder) ^ 0x5412
+}
+
+func (c *Code) drawFormatBits(mask int) {
+	bits := formatBits(c.Level, mask)
+	bit := func(i int) bool { return (bits>>i)&1 != 0 }
+
+	// Around the top left finder
+	for i := 0; i <= 5; i++ {
+		c.set(8, i, bit(i), true)
+	}
+	c.set(8, 7, bit(6), true)
+	c.set(8, 8, bit(7), true)
+	c.set(7, 8, bit(8), true)
+	for i := 9; i < 15; i++ {
+		c.set(14-i, 8, bit(i), true)
+	}
+
+	// Split between the top right and bottom left finders
+	for i := 0; i < 8; i++ {
+		c.set(c.Size-1-i, 8, bit(i), true)
+	}
+	for i := 8; i < 15; i++ {
+		c.set(8, c.Size-15+i, bit(i), true)
+	}
+
+	// The dark module
+	c.set(8, c.Size-8, true, true)
+}
+
+// versionBits the 18 bit BCH protected version, only drawn for version 7 and up
+func versionBits(version int) int {
+	remainder := version
+	for i := 0; i < 12; i++ {
+		remainder = (remainder << 1) ^ ((remainder >> 11) * 0x1f25)
+	}
+
+	return version<<12 | remainder
+}
+
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+
+	bits := versionBits(c.Version)
+	for i := 0; i < 18; i++ {
+		dark := (bits>>i)&1 != 0
+		a, b := c.Size-11+i%3, i/3
+
+		c.set(a, b, dark, true)
+		c.set(b, a, dark, true)
+	}
+}
+
+// drawCodewords places the codewords in the zig zag pairs of columns from the bottom right, skipping the function
+// patterns. Remainder bits are left light
+func (c *Code) drawCodewords(codewords []byte) {
+	i := 0
+
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// Skip the vertical timing pattern
+			right = 5
+		}
+
+		upward := (right+1)&2 == 0
+
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if upward {
+					y = c.Size - 1 - vert
+				}
+
+				if !c.isFunction[y*c.Size+x] && i < len(codewords)*8 {
+					c.modules[y*c.Size+x] = (codewords[i>>3]>>(7-(i&7)))&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+func maskBit(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// applyMask XORs the mask onto the data modules. Applying it twice undoes it
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.isFunction[y*c.Size+x] && maskBit(mask, x, y) {
+				c.modules[y*c.Size+x] = !c.modules[y*c.Size+x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the code is to scan, lower is better
+func (c *Code) penalty() int {
+	const (
+		penaltyRun    = 3
+		penaltyBlock  = 3
+		penaltyFinder = 40
+		penaltyDark   = 10
+	)
+
+	var (
+		result int
+		dark   int
+		finder = [2][11]bool{
+			{true, false, true, true, true, false, true, false, false, false, false},
+			{false, false, false, false, true, false, true, true, true, false, true},
+		}
+	)
+
+	for a := 0; a < c.Size; a++ {
+		// Runs of 5 or more in row a and column a
+		for _, horizontal := range []bool{true, false} {
+			at := func(b int) bool {
+				if horizontal {
+					return c.Black(b, a)
+				}
+				return c.Black(a, b)
+			}
+
+			run := 1
+			for b := 1; b <= c.Size; b++ {
+				if b < c.Size && at(b) == at(b-1) {
+					run++
+					continue
+				}
+
+				if run >= 5 {
+					result += penaltyRun + run - 5
+				}
+				run = 1
+			}
+
+			// Finder like patterns 1:1:3:1:1 with 4 light modules on one side
+			for b := 0; b+11 <= c.Size; b++ {
+				for _, pattern := range finder {
+					matches := true
+					for k, want := range pattern {
+						if at(b+k) != want {
+							matches = false
+							break
+						}
+					}
+
+					if matches {
+						result += penaltyFinder
+					}
+				}
+			}
+		}
+
+		for b := 0; b < c.Size; b++ {
+			if c.Black(b, a) {
+				dark++
+			}
+
+			// 2x2 blocks of one colour
+			if a+1 < c.Size && b+1 < c.Size {
+				colour := c.Black(b, a)
+				if colour == c.Black(b+1, a) && colour == c.Black(b, a+1) && colour == c.Black(b+1, a+1) {
+					result += penaltyBlock
+				}
+			}
+		}
+	}
+
+	// Balance of dark and light, in 5% steps away from 50%
+	total := c.Size * c.Size
+	result += abs(dark*20-total*10) / total * penaltyDark
+
+	return result
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
+
+// bitBuffer a sequence of bits, most significant first
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, (value>>i)&1 != 0)
+	}
+}
+
+func (b bitBuffer) bytes() []byte {
+	result := make([]byte, len(b)/8)
+
+	for i, bit := range b {
+		if bit {
+			result[i/8] |= 1 << (7 - i%8)
+		}
+	}
+
+	return result
+}
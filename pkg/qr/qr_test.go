@@ -0,0 +1,288 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// HELLO WORLD at version 1-M from the Thonky QR code tutorial
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	if got := reedSolomonRemainder(data, reedSolomonDivisor(len(want))); !bytes.Equal(got, want) {
+		t.Errorf("reedSolomonRemainder() = %v, want %v", got, want)
+	}
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	tests := []struct {
+		level Level
+		mask  int
+		want  int
+	}{
+		{L, 0, 0b111011111000100},
+		{M, 0, 0b101010000010010},
+		{Q, 0, 0b011010101011111},
+		{H, 0, 0b001011010001001},
+		{M, 5, 0b100000011001110},
+	}
+	for _, tt := range tests {
+		if got := formatBits(tt.level, tt.mask); got != tt.want {
+			t.Errorf("formatBits(%s, %d) = %015b, want %015b", tt.level, tt.mask, got, tt.want)
+		}
+	}
+
+	if got := versionBits(7); got != 0b000111110010010100 {
+		t.Errorf("versionBits(7) = %018b", got)
+	}
+
+	if got := alignmentPositions(36); len(got) != 7 || got[0] != 6 || got[1] != 24 || got[6] != 154 {
+		t.Errorf("alignmentPositions(36) = %v", got)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		level       Level
+		wantVersion int
+	}{
+		{"empty", "", M, 1},
+		{"full version 1", strings.Repeat("a", 14), M, 1},
+		{"p2pkh", "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR", M, 3},
+		{"p2tr", "bc1ph58r4m6x3ngsy5fkufpdsu9zjshphwmc05snnyh9rf2pldw2y0qsenskej", M, 4},
+		{"uri", "bitcoin:1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR?amount=0.001&label=Tips%20Opendime", H, 8},
+		{"version info and long blocks", strings.Repeat("opendime", 40), Q, 16},
+		{"largest", strings.Repeat("x", 2953), L, 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Encode(tt.text, tt.level)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			if code.Version != tt.wantVersion || code.Size != tt.wantVersion*4+17 {
+				t.Errorf("Encode() version = %d size = %d, want version %d", code.Version, code.Size, tt.wantVersion)
+			}
+
+			// Finder pattern corners and the dark module
+			for _, xy := range [][2]int{{0, 0}, {6, 6}, {code.Size - 1, 0}, {0, code.Size - 1}, {8, code.Size - 8}} {
+				if !code.Black(xy[0], xy[1]) {
+					t.Errorf("Encode() module %v is light", xy)
+				}
+			}
+
+			if got := readFormatBits(code); got != formatBits(tt.level, code.Mask) {
+				t.Errorf("Encode() format bits = %015b, want %015b", got, formatBits(tt.level, code.Mask))
+			}
+
+			// Read the codewords back out of the symbol and check every block is a Reed-Solomon codeword
+			want := addECCAndInterleave(encodeData([]byte(tt.text), code.Version, tt.level), code.Version, tt.level)
+			if got := readCodewords(code); !bytes.Equal(got, want) {
+				t.Errorf("Encode() codewords do not read back")
+			}
+
+			checkSyndromes(t, want, code.Version, tt.level)
+		})
+	}
+
+	if _, err := Encode(strings.Repeat("x", 2954), L); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Encode() too long error = %v", err)
+	}
+}
+
+func TestEncodeGolden(t *testing.T) {
+	// Goldens made by github.com/skip2/go-qrcode (QRCode.Bitmap() without the border, # dark), which picks the same
+	// mask for these. Kazuhiko Arase's qrcode-generator draws the same modules once its mask is applied instead
+	tests := []struct {
+		name   string
+		text   string
+		level  Level
+		golden string
+	}{
+		{"version 1", "Opendime", M, "opendime-1-M.txt"},
+		{"version info", strings.Repeat("opendime", 18), L, "opendime18-7-L.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			golden, err := os.ReadFile(filepath.Join("testdata", tt.golden))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			code, err := Encode(tt.text, tt.level)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			want := strings.Split(strings.TrimSpace(string(golden)), "\n")
+			if len(want) != code.Size {
+				t.Fatalf("Encode() size = %d, want %d", code.Size, len(want))
+			}
+
+			for y, row := range want {
+				for x := range row {
+					if code.Black(x, y) != (row[x] == '#') {
+						t.Errorf("Encode() module %d,%d = %v, want %c (mask %d)", x, y, code.Black(x, y), row[x], code.Mask)
+					}
+				}
+			}
+		})
+	}
+}
+
+// readFormatBits reads the top left copy of the format bits
+func readFormatBits(code *Code) int {
+	var bits int
+
+	read := func(i, x, y int) {
+		if code.Black(x, y) {
+			bits |= 1 << i
+		}
+	}
+
+	for i := 0; i <= 5; i++ {
+		read(i, 8, i)
+	}
+	read(6, 8, 7)
+	read(7, 8, 8)
+	read(8, 7, 8)
+	for i := 9; i < 15; i++ {
+		read(i, 14-i, 8)
+	}
+
+	return bits
+}
+
+// readCodewords unmasks the data modules and reads them in placement order
+func readCodewords(code *Code) []byte {
+	var bits bitBuffer
+
+	for right := code.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+
+		for vert := 0; vert < code.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = code.Size - 1 - vert
+				}
+
+				if !code.isFunction[y*code.Size+x] {
+					bits = append(bits, code.Black(x, y) != maskBit(code.Mask, x, y))
+				}
+			}
+		}
+	}
+
+	return bits.bytes()[:rawDataModules(code.Version)/8]
+}
+
+// checkSyndromes de-interleaves the codewords and checks each block evaluates to zero at the generator roots
+func checkSyndromes(t *testing.T, codewords []byte, version int, level Level) {
+	t.Helper()
+
+	var (
+		blocks       = numBlocks[level][version]
+		eccLen       = eccCodewordsPerBlock[level][version]
+		rawCodewords = rawDataModules(version) / 8
+		numShort     = blocks - rawCodewords%blocks
+		shortDataLen = rawCodewords/blocks - eccLen
+		split        = make([][]byte, blocks)
+		offset       int
+	)
+
+	for i := 0; i <= shortDataLen; i++ {
+		for block := range split {
+			if i < shortDataLen || block >= numShort {
+				split[block] = append(split[block], codewords[offset])
+				offset++
+			}
+		}
+	}
+
+	for i := 0; i < eccLen; i++ {
+		for block := range split {
+			split[block] = append(split[block], codewords[offset])
+			offset++
+		}
+	}
+
+	for block, codeword := range split {
+		root := byte(1)
+		for i := 0; i < eccLen; i++ {
+			var syndrome byte
+			for _, c := range codeword {
+				syndrome = gfMultiply(syndrome, root) ^ c
+			}
+
+			if syndrome != 0 {
+				t.Errorf("block %d syndrome %d = %d", block, i, syndrome)
+			}
+
+			root = gfMultiply(root, 0x02)
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	if level, err := ParseLevel("Q"); err != nil || level != Q {
+		t.Errorf("ParseLevel(Q) = %v, %v", level, err)
+	}
+
+	if _, err := ParseLevel("X"); err == nil {
+		t.Errorf("ParseLevel(X) no error")
+	}
+}
+
+func TestHalfBlocks(t *testing.T) {
+	code, err := Encode("bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5", M)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	for _, tt := range []struct {
+		quietZone int
+		invert    bool
+	}{
+		{4, false},
+		{1, false},
+		{2, true},
+	} {
+		lines := strings.Split(strings.TrimSuffix(code.HalfBlocks(tt.quietZone, tt.invert), "\n"), "\n")
+
+		width := code.Size + 2*tt.quietZone
+		if len(lines) != (width+1)/2 {
+			t.Errorf("HalfBlocks(%d) lines = %d, want %d", tt.quietZone, len(lines), (width+1)/2)
+		}
+
+		// Read every module back from the half blocks
+		for row, line := range lines {
+			runes := []rune(line)
+			if len(runes) != width {
+				t.Fatalf("HalfBlocks(%d) line %d width = %d, want %d", tt.quietZone, row, len(runes), width)
+			}
+
+			for col, r := range runes {
+				x, y := col-tt.quietZone, row*2-tt.quietZone
+
+				top, bottom := r == '█' || r == '▀', r == '█' || r == '▄'
+				if top != (code.Black(x, y) == tt.invert) {
+					t.Errorf("HalfBlocks(%d, %v) module %d,%d wrong", tt.quietZone, tt.invert, x, y)
+				}
+				if y+1 < code.Size+tt.quietZone && bottom != (code.Black(x, y+1) == tt.invert) {
+					t.Errorf("HalfBlocks(%d, %v) module %d,%d wrong", tt.quietZone, tt.invert, x, y+1)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package qr
+
+import "strings"
+
+// HalfBlocks draws the code with Unicode half blocks, two rows of modules per line of text, surrounded by quietZone
+// light modules. Light modules are drawn as blocks so the code scans on a terminal with light text on a dark
+// background. Use invert for dark text on a light background
+func (c *Code) HalfBlocks(quietZone int, invert bool) string {
+	var b strings.Builder
+
+	// lit is true where a module is drawn with a block
+	lit := func(x, y int) bool {
+		if y >= c.Size+quietZone {
+			// Below the bottom of the quiet zone when the height is odd
+			return false
+		}
+
+		return c.Black(x, y) == invert
+	}
+
+	for y := -quietZone; y < c.Size+quietZone; y += 2 {
+		for x := -quietZone; x < c.Size+quietZone; x++ {
+			switch top, bottom := lit(x, y), lit(x, y+1); {
+			case top && bottom:
+				b.WriteRune('█')
+			case top:
+				b.WriteRune('▀')
+			case bottom:
+				b.WriteRune('▄')
+			default:
+				b.WriteRune(' ')
+			}
+		}
+
+		b.WriteRune('\n')
+	}
+
+	return b.String()
+}
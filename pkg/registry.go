@@ -0,0 +1,150 @@
+package pkg
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// Coin describes a chain that addresses can be derived for
+type Coin struct {
+	// Name of the coin eg Bitcoin
+	Name string
+	// Symbol ticker symbol eg BTC
+	Symbol string
+	// MessageMagic the signed message prefix eg "Bitcoin Signed Message:\n". Empty if the coin has none
+	MessageMagic string
+	// URIScheme payment URI scheme eg bitcoin (BIP21) or ethereum (EIP-681). Empty if the address already has a
+	// prefix (CashAddr)
+	URIScheme string
+	// Networks chain params (version bytes, bech32 HRP, WIF prefix) per network. A coin without any Networks
+	// (eg Ethereum) does not use chain params and is available on every network
+	Networks map[Network]*chaincfg.Params
+}
+
+// Params returns the chain params of the coin for the network and whether the coin exists on that network
+func (c *Coin) Params(network Network) (*chaincfg.Params, bool) {
+	if c.Networks == nil {
+		return nil, true
+	}
+
+	if network == "" {
+		network = MainNet
+	}
+
+	params, ok := c.Networks[network]
+
+	return params, ok
+}
+
+// Address type names shared by the coins
+const (
+	TypeP2PKH           = "P2PKH"
+	TypeP2PKHCompressed = "P2PKH (Compressed)"
+	TypeP2SHP2WPKH      = "P2SH-P2WPKH"
+	TypeP2WPKH          = "P2WPKH"
+	TypeP2TR            = "P2TR"
+)
+
+// DeriveFunc derives an address from the public key using the coin's chain params (nil for coins without params)
+type DeriveFunc func(publicKey *btcec.PublicKey, params *chaincfg.Params) (string, error)
+
+// KeyFunc formats the private key (secret exponent hex) for import into a wallet for the address type
+type KeyFunc func(secretExponentHex string, params *chaincfg.Params) string
+
+// AddressType describes one way of deriving an address for a coin
+type AddressType struct {
+	// ID stable identifier eg BitcoinP2WPKH
+	ID string
+	// Coin the address belongs to
+	Coin *Coin
+	// Name of the address type eg P2WPKH. Empty for coins with a single address type
+	Name string
+	// Derive makes the address from a public key
+	Derive DeriveFunc
+	// Key formats a private key for this address type (used by keyconv)
+	Key KeyFunc
+}
+
+// Label returns the human readable name eg "Bitcoin P2WPKH"
+func (t AddressType) Label() string {
+	if t.Name == "" {
+		return t.Coin.Name
+	}
+
+	return t.Coin.Name + " " + t.Name
+}
+
+var (
+	registryMu   sync.RWMutex
+	coins        []*Coin
+	addressTypes []AddressType
+)
+
+// RegisterCoin adds a coin to the registry. Coins are kept in registration order
+func RegisterCoin(coin *Coin) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, c := range coins {
+		if c.Name == coin.Name {
+			return fmt.Errorf("coin %s already registered", coin.Name)
+		}
+	}
+
+	coins = append(coins, coin)
+
+	return nil
+}
+
+// RegisterAddressType adds an address type to the registry. GetAddresses returns addresses in registration order
+func RegisterAddressType(addressType AddressType) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if addressType.Coin == nil || addressType.Derive == nil {
+		return fmt.Errorf("address type %s must have a coin and derive function", addressType.ID)
+	}
+
+	for _, t := range addressTypes {
+		if t.ID == addressType.ID {
+			return fmt.Errorf("address type %s already registered", addressType.ID)
+		}
+	}
+
+	addressTypes = append(addressTypes, addressType)
+
+	return nil
+}
+
+// Coins returns the registered coins in registration order
+func Coins() []*Coin {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return append([]*Coin{}, coins...)
+}
+
+// GetCoin returns the registered coin by name or nil if not found
+func GetCoin(name string) *Coin {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, c := range coins {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// AddressTypes returns the registered address types in registration order
+func AddressTypes() []AddressType {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return append([]AddressType{}, addressTypes...)
+}
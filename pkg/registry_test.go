@@ -0,0 +1,77 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegisterDuplicate(t *testing.T) {
+	if err := RegisterCoin(&Coin{Name: Bitcoin}); err == nil {
+		t.Errorf("RegisterCoin() expected error for duplicate coin")
+	}
+
+	if err := RegisterAddressType(AddressType{ID: "BitcoinP2PKH", Coin: bitcoinCoin, Derive: deriveP2PKH}); err == nil {
+		t.Errorf("RegisterAddressType() expected error for duplicate address type")
+	}
+
+	if err := RegisterAddressType(AddressType{ID: "Missing"}); err == nil {
+		t.Errorf("RegisterAddressType() expected error for missing coin and derive")
+	}
+}
+
+func TestCoinParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		coin       string
+		network    Network
+		wantExists bool
+		wantHRP    string
+		wantWif    byte
+	}{
+		{"bitcoin default is mainnet", Bitcoin, "", true, "bc", 0x80},
+		{"bitcoin signet", Bitcoin, SigNet, true, "tb", 0xef},
+		{"litecoin mainnet", Litecoin, MainNet, true, "ltc", 0xb0},
+		{"litecoin signet", Litecoin, SigNet, false, "", 0},
+		{"dogecoin mainnet", Dogecoin, MainNet, true, "", 0x9e},
+		{"dogecoin testnet", Dogecoin, TestNet, true, "", 0xf1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, exists := GetCoin(tt.coin).Params(tt.network)
+			if exists != tt.wantExists {
+				t.Fatalf("Params() exists = %v, want %v", exists, tt.wantExists)
+			}
+			if !exists {
+				return
+			}
+			if params.Bech32HRPSegwit != tt.wantHRP {
+				t.Errorf("Params() Bech32HRPSegwit = %v, want %v", params.Bech32HRPSegwit, tt.wantHRP)
+			}
+			if params.PrivateKeyID != tt.wantWif {
+				t.Errorf("Params() PrivateKeyID = %02x, want %02x", params.PrivateKeyID, tt.wantWif)
+			}
+		})
+	}
+
+	if params, exists := GetCoin(Ethereum).Params(RegTest); !exists || params != nil {
+		t.Errorf("Params() Ethereum = %v %v, want nil true", params, exists)
+	}
+
+	if GetCoin("Nonexistent") != nil {
+		t.Errorf("GetCoin() expected nil for unknown coin")
+	}
+}
+
+func TestValidateWifDogecoin(t *testing.T) {
+	const secretHex = "1e99423a4ed27608a15a2616a2b0e9e52ced330ac530edcc32c8ffc6a526aedd"
+
+	wif := ToWif(WifPrefixHex(MainNet, Dogecoin), secretHex, true)
+
+	modes, gotSecret, compressed, err := ValidateWif(MainNet, wif)
+	if err != nil {
+		t.Fatalf("ValidateWif() error = %v", err)
+	}
+	if !reflect.DeepEqual(modes, []string{Dogecoin}) || gotSecret != secretHex || !compressed {
+		t.Errorf("ValidateWif() = %v %v %v, want %v %v true", modes, gotSecret, compressed, Dogecoin, secretHex)
+	}
+}
@@ -6,11 +6,9 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"os"
-	"regexp"
 	"strings"
-	"syscall"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
@@ -23,19 +21,17 @@ type VerifiedMessage struct {
 	Message      []byte
 	IsValid      bool
 	PublicKeyHex string
+	Network      Network
+	// AddressType the ID of the address type the signature proved eg BitcoinP2WPKH
+	AddressType string
+	// MessageMagic the signed message prefix the signature was made with eg "Dogecoin Signed Message:\n". Empty for
+	// BIP322
+	MessageMagic string
 }
 
 const (
-	// Expected length of verify.txt file
-	expectedVerifyTxtLen = 512
 	// Expected signature decoded/bytes length
 	expectedSignatureLen = 65
-
-	// verify.txt constants
-	vtHeaderBitcoin  = "-----BEGIN BITCOIN SIGNED MESSAGE-----\n"
-	vtHeaderLitecoin = "-----BEGIN LITECOIN SIGNED MESSAGE-----\n"
-	vtSignedMessage  = "\n-----BEGIN SIGNATURE-----\n"
-	vtFooterPrefix   = "\n-----END "
 )
 
 // ValidateSignature takes a Bitcoin/Litecoin encoded signature and returns the 65 byte DER encoded bytes
@@ -53,103 +49,337 @@ func ValidateSignature(signature string) ([]byte, error) {
 }
 
 // VerifyMessage wrapper for VerifySignature that accepts strings for signature and message
-// signature will be in Bitcoin base58 format and message as string
-func VerifyMessage(address string, signature string, message string) (VerifiedMessage, error) {
+// signature will be in Bitcoin base64 format and message as string. Signatures that are not 65 bytes are
+// verified as BIP322 simple or full proofs and 0x addresses as Ethereum personal_sign (EIP-191, hex signature).
+// An Opendime signs with CRLF line endings but most wallets sign with LF, so a multi line message that does not
+// verify is tried again with LF
+func VerifyMessage(network Network, address string, signature string, message string) (VerifiedMessage, error) {
+	return verifyMessageLineEndings(network, address, signature, message, false)
+}
+
+// VerifyMessageAnyMagic is VerifyMessage trying the message magic of every coin rather than only the chain of the
+// address, for wallets that sign with another chain's magic. VerifiedMessage.MessageMagic reports which one matched
+// and the address may be proved by an address type of any coin
+func VerifyMessageAnyMagic(network Network, address string, signature string, message string) (VerifiedMessage, error) {
+	return verifyMessageLineEndings(network, address, signature, message, true)
+}
+
+func verifyMessageLineEndings(network Network, address string, signature string, message string, anyMagic bool) (VerifiedMessage, error) {
+	verified, err := verifyMessage(network, address, signature, message, anyMagic)
+	if err != nil && strings.Contains(message, "\r\n") {
+		if lfVerified, lfErr := verifyMessage(network, address, signature, strings.ReplaceAll(message, "\r\n", "\n"), anyMagic); lfErr == nil {
+			return lfVerified, nil
+		}
+	}
+
+	return verified, err
+}
+
+func verifyMessage(network Network, address string, signature string, message string, anyMagic bool) (VerifiedMessage, error) {
+	if isHexAddress(address) {
+		return VerifyEIP191(network, address, signature, []byte(message))
+	}
+
 	signatureBytes, err := ValidateSignature(signature)
 	if err != nil {
+		if decoded, decodeErr := base64.StdEncoding.DecodeString(signature); decodeErr == nil && len(decoded) > 0 {
+			return VerifyBIP322(network, address, signature, []byte(message))
+		}
+
 		return VerifiedMessage{}, err
 	}
 
-	return VerifySignature(address, signatureBytes, []byte(message))
+	return verifySignature(network, address, signatureBytes, []byte(message), anyMagic)
 }
 
-// VerifySignature takes an address, signature and message and returns VerifiedMessage
-func VerifySignature(address string, signature []byte, message []byte) (VerifiedMessage, error) {
-	var buf bytes.Buffer
+// VerifySignature takes the network, an address, signature and message and returns VerifiedMessage.
+// The BIP137 header byte selects which address types the signature can prove (see decodeSignatureHeader) and the
+// chain of the address selects the message magic (see signatureHeaders)
+func VerifySignature(network Network, address string, signature []byte, message []byte) (VerifiedMessage, error) {
+	return verifySignature(network, address, signature, message, false)
+}
 
-	bitcoinSignatureHeader := []byte("Bitcoin Signed Message:\n")
-	litecoinSignatureHeader := []byte("Litecoin Signed Message:\n")
+func verifySignature(network Network, address string, signature []byte, message []byte, anyMagic bool) (VerifiedMessage, error) {
+	if len(signature) != expectedSignatureLen {
+		return VerifiedMessage{}, fmt.Errorf("signature bytes wrong length expected 65 got %d", len(signature))
+	}
 
-	btcMatch, _ := regexp.MatchString("^(1|3|bc1).", address)
-	if btcMatch { // todo: not sure if 3 addresses can sign? And litecoin has 3 addresses too?
-		_ = wire.WriteVarBytes(&buf, 0, bitcoinSignatureHeader)
-	} else { // todo: handle other signature types that Bitcoin/Litecoin?
-		_ = wire.WriteVarBytes(&buf, 0, litecoinSignatureHeader)
+	header, addressTypes, err := decodeSignatureHeader(signature[0])
+	if err != nil {
+		return VerifiedMessage{}, err
 	}
 
+	compactSignature := append([]byte{header}, signature[1:]...)
+
+	headers := signatureHeaders(network, address)
+	if anyMagic {
+		headers = appendMessageMagics(headers)
+	}
+
+	for _, signatureHeader := range headers {
+		// A signature over another magic may not recover a key at all
+		publicKey, _, err := ecdsa.RecoverCompact(compactSignature, signedMessageHash(signatureHeader, message))
+		if err != nil {
+			continue
+		}
+
+		addrs, _ := GetAddresses(VerifiedMessage{
+			PublicKeyHex: hex.EncodeToString(publicKey.SerializeUncompressed()),
+			Network:      network,
+		})
+
+		magic := string(signatureHeader)
+		if anyMagic {
+			magic = ""
+		}
+
+		addressType := matchAddressType(addrs, address, magic, addressTypes)
+		if addressType == "" {
+			continue
+		}
+
+		return VerifiedMessage{
+			Address:      address,
+			Signature:    signature,
+			Message:      message,
+			IsValid:      true,
+			PublicKeyHex: hex.EncodeToString(publicKey.SerializeUncompressed()),
+			Network:      network,
+			AddressType:  addressType,
+			MessageMagic: string(signatureHeader),
+		}, nil
+	}
+
+	return VerifiedMessage{}, errors.New("Invalid signature address not match")
+}
+
+// decodeSignatureHeader decodes the BIP137 header byte and returns the header normalised for
+// ecdsa.RecoverCompact (27-34) and the address type names the signature can prove.
+//
+//	27-30 P2PKH uncompressed
+//	31-34 P2PKH compressed (Electrum also uses these for P2SH-P2WPKH and P2WPKH)
+//	35-38 P2SH-P2WPKH
+//	39-42 P2WPKH
+func decodeSignatureHeader(header byte) (byte, []string, error) {
+	if header < 27 || header > 42 {
+		return 0, nil, fmt.Errorf("signature header byte %d out of range 27-42", header)
+	}
+
+	recoveryID := (header - 27) & 3
+	compressedHeader := 27 + recoveryID + 4
+
+	switch {
+	case header < 31:
+		return header, []string{TypeP2PKH}, nil
+	case header < 35:
+		return header, []string{TypeP2PKHCompressed, TypeP2SHP2WPKH, TypeP2WPKH}, nil
+	case header < 39:
+		return compressedHeader, []string{TypeP2SHP2WPKH}, nil
+	}
+
+	return compressedHeader, []string{TypeP2WPKH}, nil
+}
+
+// matchAddressType returns the ID of the derived address type that equals address, belongs to a coin using the
+// magic (any coin if magic is empty) and is one of the allowed type names. Empty string if there is no match
+func matchAddressType(addrs Addresses, address, magic string, allowed []string) string {
+	for _, derived := range addrs.Derived {
+		if !containsString(allowed, derived.Type) || !sameAddress(derived.Address, address) {
+			continue
+		}
+
+		if coin := GetCoin(derived.Coin); magic != "" && (coin == nil || coin.MessageMagic != magic) {
+			continue
+		}
+
+		return derived.ID
+	}
+
+	return ""
+}
+
+// sameAddress compares addresses. Base58 addresses are case sensitive but bech32 and CashAddr addresses (which
+// are always derived in lower case) are not
+func sameAddress(derived, address string) bool {
+	if derived == address {
+		return true
+	}
+
+	return derived == strings.ToLower(derived) && strings.EqualFold(derived, address)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// signedMessageHash the double sha256 of the magic and message (each prefixed with its length) that is signed
+func signedMessageHash(magic []byte, message []byte) []byte {
+	var buf bytes.Buffer
+
+	_ = wire.WriteVarBytes(&buf, 0, magic)
 	_ = wire.WriteVarBytes(&buf, 0, message)
-	messageHash := chainhash.DoubleHashB(buf.Bytes())
 
-	publicKey, _, err := ecdsa.RecoverCompact(signature, messageHash)
-	if err != nil {
-		return VerifiedMessage{}, err
+	return chainhash.DoubleHashB(buf.Bytes())
+}
+
+// signatureHeaders returns the message magic(s) to try for the address on the network. Every registered coin
+// that recognises the address is tried (eg testnet Bitcoin and Litecoin share base58 version bytes), falling
+// back to every known magic for unrecognised addresses
+func signatureHeaders(network Network, address string) [][]byte {
+	var headers [][]byte
+
+	for _, coin := range Coins() {
+		params, ok := coin.Params(network)
+		if !ok || coin.MessageMagic == "" || !isAddressForParams(address, params) {
+			continue
+		}
+
+		// coins can share a magic (eg Bitcoin and Bitcoin Cash)
+		if containsHeader(headers, coin.MessageMagic) {
+			continue
+		}
+
+		headers = append(headers, []byte(coin.MessageMagic))
+	}
+
+	if len(headers) == 0 {
+		headers = appendMessageMagics(headers)
 	}
 
-	addrs, _ := GetAddresses(VerifiedMessage{PublicKeyHex: hex.EncodeToString(publicKey.SerializeUncompressed())})
-	if address != addrs.BitcoinP2PKH && address != addrs.BitcoinP2PKHCompressed &&
-		address != addrs.LitecoinP2PKH && address != addrs.LitecoinP2PKHCompressed {
-		return VerifiedMessage{}, errors.New("Invalid signature address not match")
+	return headers
+}
+
+// appendMessageMagics appends the message magic of every registered coin that is not already in headers
+func appendMessageMagics(headers [][]byte) [][]byte {
+	for _, coin := range Coins() {
+		if coin.MessageMagic != "" && !containsHeader(headers, coin.MessageMagic) {
+			headers = append(headers, []byte(coin.MessageMagic))
+		}
+	}
+
+	return headers
+}
+
+// MagicName returns the message magic without the trailing ":\n" (or the leading 0x19 of EIP-191) eg Dogecoin
+// Signed Message
+func MagicName(magic string) string {
+	return strings.TrimPrefix(strings.TrimSuffix(magic, ":\n"), "\x19")
+}
+
+func containsHeader(headers [][]byte, magic string) bool {
+	for _, header := range headers {
+		if string(header) == magic {
+			return true
+		}
 	}
 
-	return VerifiedMessage{
-		Address:      address,
-		Signature:    signature,
-		Message:      message,
-		IsValid:      true,
-		PublicKeyHex: hex.EncodeToString(publicKey.SerializeUncompressed()),
-	}, nil
+	return false
 }
 
-// ParseVerifyTxt takes the opendime verify.txt format and returns address, signature, message
+// ParseVerifyTxt takes the opendime verify.txt format and returns address, signature, message of its signed message.
+// Errors from ParseSignedMessage are returned as is so callers can check the line and errors.Is
 // based on https://github.com/richardkiss/pycoin/blob/main/pycoin/contrib/msg_signing.py
 func ParseVerifyTxt(fn string) (string, string, string, error) {
-	var (
-		err       error
-		address   string
-		message   string
-		signature string
-	)
-
-	file, err := os.OpenFile(fn, os.O_RDONLY|syscall.O_NOATIME, 0)
+	file, err := openNoAtime(fn)
 	if err != nil {
 		return "", "", "", err
 	}
+	defer file.Close()
 
-	bufb := make([]byte, expectedVerifyTxtLen)
-	_, err = file.Read(bufb)
+	message, err := ParseSignedMessage(file)
 	if err != nil {
 		return "", "", "", err
 	}
 
-	bufs := string(bufb)
+	return message.Address, message.Signature, message.Message, nil
+}
+
+// SignMessage signs the message with the private key (WIF) and returns the P2PKH address and base64 compact
+// signature. The coin (and its message magic) comes from the WIF prefix (see WifCoin) unless coinName is given.
+// coinName is required when coins with different magics share the prefix (ErrAmbiguousWif), eg Bitcoin and Litecoin
+// on testnet. Message newlines are normalised to CRLF to match ParseVerifyTxt
+func SignMessage(network Network, coinName string, key string, message string) (string, string, error) {
+	modes, secretHex, compressed, err := ValidateWif(network, key)
+	if err != nil {
+		return "", "", err
+	}
+
+	if coinName == "" {
+		coinName, err = WifCoin(modes)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	coin := GetCoin(coinName)
+	if coin == nil || coin.MessageMagic == "" {
+		return "", "", fmt.Errorf("coin '%s' does not support signed messages", coinName)
+	}
+
+	params, ok := coin.Params(network)
+	if !ok {
+		return "", "", fmt.Errorf("coin '%s' does not exist on network %s", coinName, network)
+	}
+
+	secret, err := hex.DecodeString(secretHex)
+	if err != nil {
+		return "", "", err
+	}
+
+	privateKey, publicKey := btcec.PrivKeyFromBytes(secret)
 
-	// Strip any windows line endings to make the string easier to work with
-	bufs = strings.ReplaceAll(bufs, "\r\n", "\n")
+	typeName := TypeP2PKH
+	if compressed {
+		typeName = TypeP2PKHCompressed
+	}
 
-	// Get the message
-	if strings.Index(bufs, vtHeaderBitcoin) == 0 {
-		message = bufs[len(vtHeaderBitcoin):strings.Index(bufs, vtSignedMessage)]
-	} else if strings.Index(bufs, vtHeaderLitecoin) == 0 {
-		message = bufs[len(vtHeaderLitecoin):strings.Index(bufs, vtSignedMessage)]
-	} else {
-		return "", "", "", fmt.Errorf("verify.txt does not start with '%s' or '%s'", vtHeaderBitcoin, vtHeaderLitecoin)
+	var address string
+	for _, addressType := range AddressTypes() {
+		if addressType.Coin == coin && addressType.Name == typeName {
+			address, err = addressType.Derive(publicKey, params)
+			if err != nil {
+				return "", "", err
+			}
+		}
 	}
 
-	// Ensure the final message has DOS newlines as specified in the RFC
-	message = strings.ReplaceAll(message, "\n", "\r\n")
+	if address == "" {
+		return "", "", fmt.Errorf("coin '%s' has no %s address", coinName, typeName)
+	}
 
-	// Get the address and signature
-	sigAreaStart := strings.Index(bufs, vtSignedMessage) + len(vtSignedMessage)
-	sigAreaEnd := sigAreaStart + strings.Index(bufs[sigAreaStart:], vtFooterPrefix)
-	sigArea := bufs[sigAreaStart:sigAreaEnd]
+	signature := ecdsa.SignCompact(privateKey, signedMessageHash([]byte(coin.MessageMagic), []byte(crlfMessage(message))), compressed)
 
-	sigAreaSplit := strings.Split(sigArea, "\n")
-	if len(sigAreaSplit) < 2 {
-		return "", "", "", fmt.Errorf("verify.txt does not end with address and signature")
+	return address, base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// FormatSignedMessage returns the verify.txt style armor for the signed message using the coin's header eg
+// -----BEGIN LITECOIN SIGNED MESSAGE-----
+func FormatSignedMessage(coinName, address, signature, message string) string {
+	name := "BITCOIN"
+	if coin := GetCoin(coinName); coin != nil && coin.MessageMagic != "" {
+		name = strings.ToUpper(strings.Fields(coin.MessageMagic)[0])
 	}
 
-	address = sigAreaSplit[0]
-	signature = sigAreaSplit[1]
+	lines := []string{
+		"-----BEGIN " + name + " SIGNED MESSAGE-----",
+		crlfMessage(message),
+		"-----BEGIN SIGNATURE-----",
+		address,
+		signature,
+		"-----END " + name + " SIGNED MESSAGE-----",
+		"",
+	}
+
+	return strings.Join(lines, "\r\n")
+}
 
-	return address, signature, message, nil
+// crlfMessage normalises newlines to CRLF as used in verify.txt
+func crlfMessage(message string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(message, "\r\n", "\n"), "\n", "\r\n")
 }
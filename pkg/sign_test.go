@@ -2,7 +2,9 @@ package pkg
 
 import (
 	"encoding/hex"
+	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 )
@@ -23,7 +25,7 @@ func TestVerifySignature(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := VerifySignature(tt.args.address, tt.args.signature, tt.args.message)
+			got, err := VerifySignature(MainNet, tt.args.address, tt.args.signature, tt.args.message)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("VerifySignature() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -91,6 +93,27 @@ func TestVerifyMessage(t *testing.T) {
 				Message:      validBitcoinMessageHex,
 				IsValid:      true,
 				PublicKeyHex: "046afa3afc399f7f332866e37f475938589de0a3298a3aa062c8f4c74450e3d3b287224ee09db6d217912f4706147bb96762d1e11e7ce2e928fd61ecdbd2e37a99",
+				Network:      MainNet,
+				AddressType:  "BitcoinP2PKHCompressed",
+				MessageMagic: "Bitcoin Signed Message:\n",
+			},
+			wantErr: false,
+		}, {
+			name: "valid bitcoin cash",
+			args: args{
+				address:   "bitcoincash:qqt85y3st6mlej3jgl2s0kawp22tp5f7zq4zja7wr8",
+				signature: "Hz0pDxS09fEdjKrSJenLYsz05gakT6eW9GdzKDopnlwUL3bf3mQeJcS+takCIMuavK/NLOorXWXZCqV0KBMlwgU=",
+				message:   "Hello World",
+			},
+			want: VerifiedMessage{
+				Address:      "bitcoincash:qqt85y3st6mlej3jgl2s0kawp22tp5f7zq4zja7wr8",
+				Signature:    validBitcoinSignatureHex,
+				Message:      validBitcoinMessageHex,
+				IsValid:      true,
+				PublicKeyHex: "046afa3afc399f7f332866e37f475938589de0a3298a3aa062c8f4c74450e3d3b287224ee09db6d217912f4706147bb96762d1e11e7ce2e928fd61ecdbd2e37a99",
+				Network:      MainNet,
+				AddressType:  "BitcoinCashP2PKHCompressed",
+				MessageMagic: "Bitcoin Signed Message:\n",
 			},
 			wantErr: false,
 		}, {
@@ -133,13 +156,16 @@ func TestVerifyMessage(t *testing.T) {
 				Message:      validLitecoinMessageHex,
 				IsValid:      true,
 				PublicKeyHex: "04a2e8f5aa9c46242cdc6463adac2ef8e6bb8b17202c06d17c647066ed143535ac1f93e66cc499170185ec79b2ef5c04119282544fea4c8072ff87711e13597bcf",
+				Network:      MainNet,
+				AddressType:  "LitecoinP2PKHCompressed",
+				MessageMagic: "Litecoin Signed Message:\n",
 			},
 			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := VerifyMessage(tt.args.address, tt.args.signature, tt.args.message)
+			got, err := VerifyMessage(MainNet, tt.args.address, tt.args.signature, tt.args.message)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("VerifyMessage() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -167,6 +193,17 @@ func TestParseVerifyTxt(t *testing.T) {
 	ltcFp.Close()
 	defer os.Remove(ltcFp.Name())
 
+	twoFp, _ := os.CreateTemp("", "pvt*")
+	_, _ = twoFp.WriteString(verifyTxtBtc + verifyTxtLtc)
+	twoFp.Close()
+	defer os.Remove(twoFp.Name())
+
+	// Cut before the signature used to panic slicing with the -1 from strings.Index
+	truncatedFp, _ := os.CreateTemp("", "pvt*")
+	_, _ = truncatedFp.WriteString(verifyTxtBtc[:120])
+	truncatedFp.Close()
+	defer os.Remove(truncatedFp.Name())
+
 	type args struct {
 		fn string
 	}
@@ -196,6 +233,18 @@ func TestParseVerifyTxt(t *testing.T) {
 			want1:   "HAVOlsYZ4/sj1lVHlqeYd4jbxRRkD5zqp6MG6mNKPmfEdE8rwByiQ+aFTuEpXswhV4y5S5dxREq3pkdq4CjU3/A=",
 			want2:   "UNSEALED -- UNSEALED -- UNSEALED\r\nNonce: 961f7ecaa917101d4241a43a  Serial: PZZUNUKLGRIFCICKJIYDEEIC74\r\nVersion: 2.3.0 time=20171018.143523 git=master@8fb7cfd coin=LTC",
 			wantErr: false,
+		}, {
+			name: "two signed messages",
+			args: args{
+				fn: twoFp.Name(),
+			},
+			wantErr: true,
+		}, {
+			name: "truncated",
+			args: args{
+				fn: truncatedFp.Name(),
+			},
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
@@ -217,3 +266,226 @@ func TestParseVerifyTxt(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyMessageTestNet(t *testing.T) {
+	tests := []struct {
+		name      string
+		address   string
+		signature string
+		message   string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "valid bitcoin testnet",
+			address:   "mhZoPvHi9rXdNsuxTXtMe97mWoDeZgGkBg",
+			signature: "Hz0pDxS09fEdjKrSJenLYsz05gakT6eW9GdzKDopnlwUL3bf3mQeJcS+takCIMuavK/NLOorXWXZCqV0KBMlwgU=",
+			message:   "Hello World",
+			want:      "046afa3afc399f7f332866e37f475938589de0a3298a3aa062c8f4c74450e3d3b287224ee09db6d217912f4706147bb96762d1e11e7ce2e928fd61ecdbd2e37a99",
+		}, {
+			name:      "valid litecoin testnet shares address prefix with bitcoin",
+			address:   "mhAXGJrJALzZJ3TfHu9mFrbuVsUxY9kWRR",
+			signature: "H021r+HxbXZo2Vkuyq0D/pfz8kllqDzmOzczJXBanIytdsbZKPlg3q1NhytyLXp03DQa//0zoOjoJfVUjZORql8=",
+			message:   "Hello World",
+			want:      "04a2e8f5aa9c46242cdc6463adac2ef8e6bb8b17202c06d17c647066ed143535ac1f93e66cc499170185ec79b2ef5c04119282544fea4c8072ff87711e13597bcf",
+		}, {
+			name:      "invalid mainnet address on testnet",
+			address:   "133r6sCjLq6NbmSLjxuypDuSeocwenu1Qg",
+			signature: "Hz0pDxS09fEdjKrSJenLYsz05gakT6eW9GdzKDopnlwUL3bf3mQeJcS+takCIMuavK/NLOorXWXZCqV0KBMlwgU=",
+			message:   "Hello World",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyMessage(TestNet, tt.address, tt.signature, tt.message)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyMessage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.PublicKeyHex != tt.want {
+				t.Errorf("VerifyMessage() PublicKeyHex = %v, want %v", got.PublicKeyHex, tt.want)
+			}
+			if !tt.wantErr && got.Network != TestNet {
+				t.Errorf("VerifyMessage() Network = %v, want %v", got.Network, TestNet)
+			}
+		})
+	}
+}
+
+func TestVerifyMessageBIP137(t *testing.T) {
+	const (
+		// Signatures of "Hello World" by the same key with BIP137 headers for P2PKH compressed (31-34, also used
+		// by Electrum for segwit), P2SH-P2WPKH (35-38) and P2WPKH (39-42)
+		bitcoinCompressed  = "H6S9vgFjfs8bh3yySu8BNO1kGu3cPmpZmy14TkufGy0fSJLVffwbnZj6Zd0OLNKYanFn4ivn4MYhKUjjo0VHAqc="
+		bitcoinP2SHP2WPKH  = "I6S9vgFjfs8bh3yySu8BNO1kGu3cPmpZmy14TkufGy0fSJLVffwbnZj6Zd0OLNKYanFn4ivn4MYhKUjjo0VHAqc="
+		bitcoinP2WPKH      = "J6S9vgFjfs8bh3yySu8BNO1kGu3cPmpZmy14TkufGy0fSJLVffwbnZj6Zd0OLNKYanFn4ivn4MYhKUjjo0VHAqc="
+		litecoinP2SHP2WPKH = "JHNbW/seabjV8JQq7G5yjwoQhRybbgpcUK/ekAbwRTW9H+o2unXs1QK6uILyged600gSpbSgk6Xf1I3zvYpUO+Q="
+		litecoinP2WPKH     = "KHNbW/seabjV8JQq7G5yjwoQhRybbgpcUK/ekAbwRTW9H+o2unXs1QK6uILyged600gSpbSgk6Xf1I3zvYpUO+Q="
+		publicKeyHex       = "042509ef79a4796f752e024d7b4ba295f84397ba5aba836718b614118f3c46a54e2233647ac3d78fdd15756e282c2a845b3ef8f64ecc374cb3587aad40a038a2cf"
+	)
+
+	tests := []struct {
+		name      string
+		address   string
+		signature string
+		want      string
+		wantErr   bool
+	}{
+		{"bitcoin p2wpkh", "bc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqzfhzrq", bitcoinP2WPKH, "BitcoinP2WPKH", false},
+		{"bitcoin p2wpkh upper case", "BC1QV3YKVNP4QZKTQZ65L925JGPE3TPKKTVQZFHZRQ", bitcoinP2WPKH, "BitcoinP2WPKH", false},
+		{"bitcoin p2sh-p2wpkh", "3FiBeABktE7t7WKWrUrX4RKNrq3dVbokdR", bitcoinP2SHP2WPKH, "BitcoinP2SHP2WPKH", false},
+		{"bitcoin p2wpkh electrum header", "bc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqzfhzrq", bitcoinCompressed, "BitcoinP2WPKH", false},
+		{"bitcoin p2sh-p2wpkh electrum header", "3FiBeABktE7t7WKWrUrX4RKNrq3dVbokdR", bitcoinCompressed, "BitcoinP2SHP2WPKH", false},
+		{"litecoin p2wpkh", "ltc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqx4dxms", litecoinP2WPKH, "LitecoinP2WPKH", false},
+		{"litecoin p2sh-p2wpkh", "MMvKx3biqLyJv1bQxMqrt4ZnBXe5ZETyYb", litecoinP2SHP2WPKH, "LitecoinP2SHP2WPKH", false},
+		{"p2wpkh header for p2pkh address", "1A9GW5SxA4qW5ZhymW1uPaj6N4vnVJVWZW", bitcoinP2WPKH, "", true},
+		{"p2sh-p2wpkh header for p2wpkh address", "bc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqzfhzrq", bitcoinP2SHP2WPKH, "", true},
+		{"bitcoin signature for litecoin address", "ltc1qv3ykvnp4qzktqz65l925jgpe3tpkktvqx4dxms", bitcoinP2WPKH, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyMessage(MainNet, tt.address, tt.signature, "Hello World")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyMessage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.AddressType != tt.want {
+				t.Errorf("VerifyMessage() AddressType = %v, want %v", got.AddressType, tt.want)
+			}
+			if !tt.wantErr && got.PublicKeyHex != publicKeyHex {
+				t.Errorf("VerifyMessage() PublicKeyHex = %v, want %v", got.PublicKeyHex, publicKeyHex)
+			}
+		})
+	}
+}
+
+func Test_decodeSignatureHeader(t *testing.T) {
+	tests := []struct {
+		header     byte
+		wantHeader byte
+		wantTypes  []string
+		wantErr    bool
+	}{
+		{27, 27, []string{TypeP2PKH}, false},
+		{34, 34, []string{TypeP2PKHCompressed, TypeP2SHP2WPKH, TypeP2WPKH}, false},
+		{36, 32, []string{TypeP2SHP2WPKH}, false},
+		{42, 34, []string{TypeP2WPKH}, false},
+		{26, 0, nil, true},
+		{43, 0, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.header), func(t *testing.T) {
+			gotHeader, gotTypes, err := decodeSignatureHeader(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeSignatureHeader() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotHeader != tt.wantHeader || !reflect.DeepEqual(gotTypes, tt.wantTypes) {
+				t.Errorf("decodeSignatureHeader() = %v %v, want %v %v", gotHeader, gotTypes, tt.wantHeader, tt.wantTypes)
+			}
+		})
+	}
+}
+
+func TestSignMessage(t *testing.T) {
+	const secretHex = "dc192045a9261a395445d220890d0969fd7dd2bacec12b9ab3c9827cb0df7bf3"
+
+	tests := []struct {
+		name        string
+		network     Network
+		coin        string
+		prefix      string
+		compress    bool
+		wantAddress string
+		wantType    string
+		wantErr     bool
+	}{
+		{"bitcoin uncompressed", MainNet, Bitcoin, "80", false, "13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu", "BitcoinP2PKH", false},
+		{"bitcoin compressed", MainNet, Bitcoin, "80", true, "1A9GW5SxA4qW5ZhymW1uPaj6N4vnVJVWZW", "BitcoinP2PKHCompressed", false},
+		{"litecoin compressed", MainNet, "", "b0", true, "LUNDmHknEj5ZLNQ8we1CfbnraHJ4iPRtuG", "LitecoinP2PKHCompressed", false},
+		{"dogecoin", MainNet, "", "9e", false, "D7msicMkTtuVPEju2qA6BoZenrFvrMCm24", "DogecoinP2PKH", false},
+		{"litecoin testnet by coin", TestNet, Litecoin, "ef", true, "mpfDo8Xvy6GkrgBbV4zHDVwRE4XVRX9Hit", "LitecoinP2PKHCompressed", false},
+		{"dash compressed", MainNet, "", "cc", true, "Xjq7LL6r7n46EWJZdPL8F7QtCQWUczLj2P", "DashP2PKHCompressed", false},
+		{"dash testnet by coin", TestNet, Dash, "ef", true, "yVTiMHBHZKiAaFE7CEeXH8qEUgzr3eveTt", "DashP2PKHCompressed", false},
+		{"bitcoin and bitcoin cash share the prefix and magic", MainNet, "", "80", true, "1A9GW5SxA4qW5ZhymW1uPaj6N4vnVJVWZW", "BitcoinP2PKHCompressed", false},
+		{"testnet coins share the prefix", TestNet, "", "ef", true, "", "", true},
+		{"ethereum has no message magic", MainNet, Ethereum, "80", true, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := "Nonce: 1234\nVersion: test"
+
+			address, signature, err := SignMessage(tt.network, tt.coin, ToWif(tt.prefix, secretHex, tt.compress), message)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SignMessage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if address != tt.wantAddress {
+				t.Errorf("SignMessage() address = %v, want %v", address, tt.wantAddress)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			// Round trip through the verify.txt armor and parser
+			coin := tt.coin
+			if coin == "" {
+				modes, _, _, _ := ValidateWif(tt.network, ToWif(tt.prefix, secretHex, tt.compress))
+				coin = modes[0]
+			}
+
+			fn := filepath.Join(t.TempDir(), "verify.txt")
+			if err := os.WriteFile(fn, []byte(FormatSignedMessage(coin, address, signature, message)), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			gotAddress, gotSignature, gotMessage, err := ParseVerifyTxt(fn)
+			if err != nil {
+				t.Fatalf("ParseVerifyTxt() error = %v", err)
+			}
+			if gotAddress != address || gotSignature != signature {
+				t.Errorf("ParseVerifyTxt() = %v %v, want %v %v", gotAddress, gotSignature, address, signature)
+			}
+
+			verified, err := VerifyMessage(tt.network, gotAddress, gotSignature, gotMessage)
+			if err != nil {
+				t.Fatalf("VerifyMessage() error = %v", err)
+			}
+			if verified.AddressType != tt.wantType {
+				t.Errorf("VerifyMessage() AddressType = %v, want %v", verified.AddressType, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestVerifyMessageAnyMagic(t *testing.T) {
+	const (
+		key     = "5KVDhAZchPz2Ywmm3sWvLdPEbKZChuqrpfAehpEy7vmZNosaqgC"
+		address = "13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu"
+		message = "Signed by a Dogecoin wallet"
+	)
+
+	// The Bitcoin key signs with the Dogecoin magic so the Bitcoin address does not verify by its chain
+	_, signature, err := SignMessage(MainNet, Dogecoin, key, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := VerifyMessage(MainNet, address, signature, message); err == nil {
+		t.Fatal("VerifyMessage() want error for the wrong chain magic")
+	}
+
+	got, err := VerifyMessageAnyMagic(MainNet, address, signature, message)
+	if err != nil {
+		t.Fatalf("VerifyMessageAnyMagic() error = %v", err)
+	}
+
+	if !got.IsValid || got.AddressType != "BitcoinP2PKH" || got.MessageMagic != "Dogecoin Signed Message:\n" {
+		t.Errorf("VerifyMessageAnyMagic() = %+v", got)
+	}
+
+	if _, err := VerifyMessageAnyMagic(MainNet, address, signature, "Another message"); err == nil {
+		t.Error("VerifyMessageAnyMagic() want error for another message")
+	}
+}
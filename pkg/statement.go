@@ -0,0 +1,117 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UnsealedBanner line added to the message once an Opendime is unsealed
+const UnsealedBanner = "UNSEALED -- UNSEALED -- UNSEALED"
+
+// StatementTimeLayout layout of the build time eg time=20190207.130255
+const StatementTimeLayout = "20060102.150405"
+
+// unsealedMarker the word of UnsealedBanner looked for, firmware versions differ in the rest of the banner
+var unsealedMarker, _, _ = strings.Cut(UnsealedBanner, " ")
+
+// ErrNotStatement the message is not an Opendime statement (no Nonce and Serial)
+var ErrNotStatement = errors.New("message is not an Opendime statement")
+
+// OpendimeStatement the fields of the message an Opendime signs in verify.txt eg
+//
+//	Nonce: 1675bf38ec241a2308585ad0  Serial: DDRRNOCZJRIFCIBAEBJDOJQY74
+//	Version: 2.4.0 time=20190207.130255 git=master@e233940e coin=BTC
+type OpendimeStatement struct {
+	Nonce    string
+	Serial   string
+	Firmware string
+	// BuildTime zero if the message has no time field
+	BuildTime time.Time
+	GitRef    string
+	// Coin symbol eg BTC or LTC, empty for old firmware without the coin field
+	Coin string
+	// Unsealed true if the message has the "UNSEALED -- UNSEALED" banner
+	Unsealed bool
+}
+
+// ParseStatement parses the message from verify.txt. ErrNotStatement is returned if the message has no Nonce and
+// Serial (eg it was signed by a wallet, not an Opendime)
+func ParseStatement(message string) (OpendimeStatement, error) {
+	var statement OpendimeStatement
+
+	for _, line := range strings.Split(strings.ReplaceAll(message, "\r\n", "\n"), "\n") {
+		fields := strings.Fields(line)
+
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+
+			if strings.HasPrefix(field, unsealedMarker) {
+				statement.Unsealed = true
+				continue
+			}
+
+			if strings.HasSuffix(field, ":") && i+1 < len(fields) {
+				i++
+				switch field {
+				case "Nonce:":
+					statement.Nonce = fields[i]
+				case "Serial:":
+					statement.Serial = fields[i]
+				case "Version:":
+					statement.Firmware = fields[i]
+				}
+
+				continue
+			}
+
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+
+			switch key {
+			case "time":
+				buildTime, err := time.Parse(StatementTimeLayout, value)
+				if err != nil {
+					return OpendimeStatement{}, fmt.Errorf("statement build time '%s' malformed: %w", value, err)
+				}
+				statement.BuildTime = buildTime
+			case "git":
+				statement.GitRef = value
+			case "coin":
+				statement.Coin = value
+			}
+		}
+	}
+
+	if statement.Nonce == "" || statement.Serial == "" {
+		return OpendimeStatement{}, ErrNotStatement
+	}
+
+	return statement, nil
+}
+
+// CoinMismatch returns an error if the statement coin field is not the coin of the address type the signature
+// proved eg coin=LTC signed for a Bitcoin address. Statements without a coin field are assumed to match
+func (s OpendimeStatement) CoinMismatch(verifiedMessage VerifiedMessage) error {
+	if s.Coin == "" || verifiedMessage.AddressType == "" {
+		return nil
+	}
+
+	for _, addressType := range AddressTypes() {
+		if addressType.ID != verifiedMessage.AddressType {
+			continue
+		}
+
+		if !strings.EqualFold(addressType.Coin.Symbol, s.Coin) {
+			return fmt.Errorf("statement says coin=%s but %s is a %s %s address", s.Coin,
+				verifiedMessage.Address, addressType.Coin.Name, verifiedMessage.Network)
+		}
+
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseStatement(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    OpendimeStatement
+		wantErr string
+	}{
+		{
+			name:    "bitcoin sealed",
+			message: "Nonce: 1675bf38ec241a2308585ad0  Serial: DDRRNOCZJRIFCIBAEBJDOJQY74\r\nVersion: 2.4.0 time=20190207.130255 git=master@e233940e coin=BTC",
+			want: OpendimeStatement{
+				Nonce: "1675bf38ec241a2308585ad0", Serial: "DDRRNOCZJRIFCIBAEBJDOJQY74", Firmware: "2.4.0",
+				BuildTime: time.Date(2019, 2, 7, 13, 2, 55, 0, time.UTC), GitRef: "master@e233940e", Coin: "BTC",
+			},
+		}, {
+			name:    "litecoin unsealed",
+			message: "UNSEALED -- UNSEALED -- UNSEALED\nNonce: 961f7ecaa917101d4241a43a  Serial: PZZUNUKLGRIFCICKJIYDEEIC74\nVersion: 2.3.0 time=20171018.143523 git=master@8fb7cfd coin=LTC",
+			want: OpendimeStatement{
+				Nonce: "961f7ecaa917101d4241a43a", Serial: "PZZUNUKLGRIFCICKJIYDEEIC74", Firmware: "2.3.0",
+				BuildTime: time.Date(2017, 10, 18, 14, 35, 23, 0, time.UTC), GitRef: "master@8fb7cfd", Coin: "LTC",
+				Unsealed: true,
+			},
+		}, {
+			name:    "old firmware without coin or time",
+			message: "Nonce: 00112233  Serial: OLDSERIAL\nVersion: 2.0.0",
+			want:    OpendimeStatement{Nonce: "00112233", Serial: "OLDSERIAL", Firmware: "2.0.0"},
+		},
+		{name: "wallet message", message: "Hello World", wantErr: ErrNotStatement.Error()},
+		{name: "bad time", message: "Nonce: 00  Serial: S\nVersion: 2.4.0 time=yesterday", wantErr: "statement build time 'yesterday' malformed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStatement(tt.message)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Errorf("ParseStatement() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseStatement() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseStatement() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpendimeStatement_CoinMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		coin     string
+		verified VerifiedMessage
+		wantErr  string
+	}{
+		{"bitcoin", "BTC", VerifiedMessage{Address: "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR", AddressType: "BitcoinP2PKH", Network: MainNet}, ""},
+		{"litecoin lowercase", "ltc", VerifiedMessage{Address: "LhNxvyyxBGv1Z9CKUaYPE5azvFCMnDMbRN", AddressType: "LitecoinP2PKH", Network: MainNet}, ""},
+		{"no coin field", "", VerifiedMessage{Address: "LhNxvyyxBGv1Z9CKUaYPE5azvFCMnDMbRN", AddressType: "LitecoinP2PKH", Network: MainNet}, ""},
+		{
+			"litecoin statement for bitcoin testnet address", "LTC",
+			VerifiedMessage{Address: "mhZoPvHi9rXdNsuxTXtMe97mWoDeZgGkBg", AddressType: "BitcoinP2PKHCompressed", Network: TestNet},
+			"statement says coin=LTC but mhZoPvHi9rXdNsuxTXtMe97mWoDeZgGkBg is a Bitcoin testnet address",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := OpendimeStatement{Coin: tt.coin}.CoinMismatch(tt.verified)
+			if (err == nil) != (tt.wantErr == "") || (err != nil && err.Error() != tt.wantErr) {
+				t.Errorf("CoinMismatch() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package pkg
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	// bip21Decimals smallest unit of the BIP21 coins (satoshi)
+	bip21Decimals = 8
+	// ethereumDecimals wei per ether
+	ethereumDecimals = 18
+)
+
+var amountRegexp = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// PaymentURI returns a payment URI for the derived address, EIP-681 for Ethereum and BIP21 for the other coins.
+// amount is in whole coins eg 0.001 and is optional like label. EIP-681 has no label so it is left out
+func PaymentURI(derived DerivedAddress, amount, label string) (string, error) {
+	coin := GetCoin(derived.Coin)
+	if coin == nil {
+		return "", fmt.Errorf("coin '%s' not registered", derived.Coin)
+	}
+
+	if amount != "" && !amountRegexp.MatchString(amount) {
+		return "", fmt.Errorf("amount '%s' must be a decimal number eg 0.001", amount)
+	}
+
+	if derived.Coin == Ethereum {
+		uri := coin.URIScheme + ":" + derived.Address
+
+		if amount != "" {
+			wei, err := decimalToUnits(amount, ethereumDecimals)
+			if err != nil {
+				return "", err
+			}
+
+			uri += "?value=" + wei.String()
+		}
+
+		return uri, nil
+	}
+
+	uri := derived.Address
+	if coin.URIScheme != "" {
+		uri = coin.URIScheme + ":" + uri
+	}
+
+	var params []string
+
+	if amount != "" {
+		if _, err := decimalToUnits(amount, bip21Decimals); err != nil {
+			return "", err
+		}
+
+		params = append(params, "amount="+amount)
+	}
+
+	if label != "" {
+		// BIP21 percent encodes spaces, QueryEscape would use +
+		params = append(params, "label="+strings.ReplaceAll(url.QueryEscape(label), "+", "%20"))
+	}
+
+	if len(params) > 0 {
+		uri += "?" + strings.Join(params, "&")
+	}
+
+	return uri, nil
+}
+
+// decimalToUnits converts a decimal amount to the integer number of smallest units
+func decimalToUnits(amount string, decimals int) (*big.Int, error) {
+	whole, fraction, _ := strings.Cut(amount, ".")
+
+	if len(fraction) > decimals {
+		return nil, fmt.Errorf("amount '%s' has more than %d decimal places", amount, decimals)
+	}
+
+	units, ok := new(big.Int).SetString(whole+fraction+strings.Repeat("0", decimals-len(fraction)), 10)
+	if !ok {
+		return nil, fmt.Errorf("amount '%s' malformed", amount)
+	}
+
+	return units, nil
+}
@@ -0,0 +1,44 @@
+package pkg
+
+import "testing"
+
+func TestPaymentURI(t *testing.T) {
+	var (
+		bitcoin     = DerivedAddress{ID: "BitcoinP2WPKH", Coin: Bitcoin, Address: "bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5"}
+		litecoin    = DerivedAddress{ID: "LitecoinP2PKH", Coin: Litecoin, Address: "LfzdHsHSPx3pxXrsvN9nviMaQeejdnT81s"}
+		ethereum    = DerivedAddress{ID: "Ethereum", Coin: Ethereum, Address: "0x76270d9D9afC0cf4EbfFBafE6401E01cb0F021Ce"}
+		bitcoinCash = DerivedAddress{ID: "BitcoinCashP2PKH", Coin: BitcoinCash, Address: "bitcoincash:qr3a2ed6zc7xmjrr580esu8a9vdkskml2vu3vxcuuy"}
+	)
+
+	tests := []struct {
+		name    string
+		derived DerivedAddress
+		amount  string
+		label   string
+		want    string
+		wantErr bool
+	}{
+		{name: "bip21 address only", derived: bitcoin, want: "bitcoin:bc1qpjtaggfhsnhkcyg967k3jmsxtm5hzg72q8ejr5"},
+		{name: "bip21 amount and label", derived: litecoin, amount: "0.5", label: "Tips & thanks",
+			want: "litecoin:LfzdHsHSPx3pxXrsvN9nviMaQeejdnT81s?amount=0.5&label=Tips%20%26%20thanks"},
+		{name: "cashaddr keeps its prefix", derived: bitcoinCash, amount: "1",
+			want: "bitcoincash:qr3a2ed6zc7xmjrr580esu8a9vdkskml2vu3vxcuuy?amount=1"},
+		{name: "eip681 value in wei", derived: ethereum, amount: "0.0123", label: "ignored",
+			want: "ethereum:0x76270d9D9afC0cf4EbfFBafE6401E01cb0F021Ce?value=12300000000000000"},
+		{name: "too many decimals", derived: bitcoin, amount: "0.000000001", wantErr: true},
+		{name: "not a number", derived: bitcoin, amount: "1e-3", wantErr: true},
+		{name: "unknown coin", derived: DerivedAddress{Coin: "Nocoin"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PaymentURI(tt.derived, tt.amount, tt.label)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PaymentURI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("PaymentURI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,437 @@
+package pkg
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Dialect of a signed message, how the message, address and signature are laid out
+type Dialect string
+
+// Signed message dialects understood by ParseSignedMessages
+const (
+	// DialectArmor verify.txt from an Opendime, also used by Electrum and Sparrow
+	DialectArmor Dialect = "armor"
+	// DialectRFC2440 Bitcoin-Qt and web tools, an RFC 2440 style clear signed message with a dash escaped body and
+	// an Address: header in the signature block
+	DialectRFC2440 Dialect = "rfc2440"
+	// DialectLabelled Address:, Message: and Signature: labelled lines
+	DialectLabelled Dialect = "labelled"
+	// DialectPlain no armor, the address, message and signature on their own lines
+	DialectPlain Dialect = "plain"
+)
+
+// verify.txt armor lines, without line endings
+const (
+	vtSignatureLine = "-----BEGIN SIGNATURE-----"
+	vtArmorDashes   = "-----"
+	// vtDashEscape RFC 2440 prefix of body lines starting with a dash
+	vtDashEscape = "- "
+)
+
+var (
+	vtHeaderRegexp       = regexp.MustCompile(`^-----BEGIN ([A-Z][A-Z ]*) SIGNED MESSAGE-----$`)
+	vtFooterRegexp       = regexp.MustCompile(`^-----END ([A-Z][A-Z ]*) SIGNED MESSAGE-----$`)
+	rfcSignatureRegexp   = regexp.MustCompile(`^-----BEGIN ([A-Z][A-Z ]*) SIGNATURE-----$`)
+	rfcFooterRegexp      = regexp.MustCompile(`^-----END ([A-Z][A-Z ]*) (SIGNATURE|SIGNED MESSAGE)-----$`)
+	rfcArmorHeaderRegexp = regexp.MustCompile(`^([A-Za-z][A-Za-z-]*): ?(.*)$`)
+	rfcHashHeaderRegexp  = regexp.MustCompile(`^Hash: .+$`)
+	labelRegexp          = regexp.MustCompile(`(?i)^\s*(address|message|signature)\s*:\s?(.*)$`)
+	plainAddressRegexp   = regexp.MustCompile(`^([a-z]+:)?[A-Za-z0-9]{25,100}$`)
+	plainSignatureRegexp = regexp.MustCompile(`^[A-Za-z0-9+/]{80,}={0,2}$`)
+)
+
+// Errors returned (wrapped in a *ParseError) by ParseSignedMessages
+var (
+	ErrNoSignedMessage  = errors.New("no signed message found, expected -----BEGIN BITCOIN SIGNED MESSAGE----- or similar")
+	ErrUnexpectedText   = errors.New("unexpected text outside a signed message")
+	ErrNoSignatureBlock = errors.New("signed message has no -----BEGIN SIGNATURE----- line")
+	ErrNoAddress        = errors.New("signature block has no address")
+	ErrNoSignature      = errors.New("signature block has no signature")
+	ErrNoMessage        = errors.New("signed message has no message")
+	ErrUnexpectedLine   = errors.New("signature block has more than an address and signature")
+	ErrNoFooter         = errors.New("signed message has no -----END SIGNED MESSAGE----- line")
+	ErrFooterMismatch   = errors.New("signed message footer does not match the header")
+)
+
+// ErrMultipleMessages returned (wrapped in a *ParseError on the line of the second block) by ParseSignedMessage
+var ErrMultipleMessages = errors.New("more than one signed message, expected one")
+
+// ParseError a malformed verify.txt. Line is 1 based
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// SignedMessage one signed message block of a verify.txt
+type SignedMessage struct {
+	Dialect Dialect
+	// Armor the coin name from the header eg BITCOIN or LITECOIN, empty for the labelled and plain dialects
+	Armor     string
+	Address   string
+	Signature string
+	// Message with CRLF line endings as an Opendime signs it (VerifyMessage also tries LF)
+	Message string
+	// Line of the header (or first line), 1 based
+	Line int
+}
+
+// ParseSignedMessages parses every signed message read from r. The dialect (see Dialect) is detected from the
+// first line. Any line endings (LF or CRLF) and any length are accepted, blank lines, spaces and NUL padding between
+// and after the blocks (an Opendime pads verify.txt to 512 bytes) are ignored. The labelled and plain dialects hold
+// a single message
+func ParseSignedMessages(r io.Reader) ([]SignedMessage, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	first := 0
+	for first < len(lines) && blank(lines[first]) {
+		first++
+	}
+
+	if first == len(lines) {
+		return nil, &ParseError{Line: max(len(lines), 1), Err: ErrNoSignedMessage}
+	}
+
+	switch trimmed := trimLine(lines[first]); {
+	case vtHeaderRegexp.MatchString(trimmed):
+		return parseArmor(lines, first)
+	case labelRegexp.MatchString(trimmed):
+		return parseLabelled(lines, first)
+	}
+
+	return parsePlain(lines, first)
+}
+
+// ParseSignedMessage parses the one signed message read from r. A verify.txt holding more than one is an error rather
+// than only the first being checked
+func ParseSignedMessage(r io.Reader) (SignedMessage, error) {
+	messages, err := ParseSignedMessages(r)
+	if err != nil {
+		return SignedMessage{}, err
+	}
+
+	if len(messages) > 1 {
+		return SignedMessage{}, &ParseError{Line: messages[1].Line, Err: ErrMultipleMessages}
+	}
+
+	return messages[0], nil
+}
+
+// readLines reads every line from r without line endings
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+
+	reader := bufio.NewReader(r)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if line == "" && err == io.EOF {
+			return lines, nil
+		}
+
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if len(lines) == 0 {
+			line = strings.TrimPrefix(line, "\ufeff") // UTF-8 byte order mark
+		}
+
+		lines = append(lines, line)
+
+		if err == io.EOF {
+			return lines, nil
+		}
+	}
+}
+
+// trimLine trims spaces, tabs and NUL padding
+func trimLine(line string) string {
+	return strings.Trim(line, " \t\x00")
+}
+
+func blank(line string) bool {
+	return trimLine(line) == ""
+}
+
+// parseArmor parses the armor and RFC 2440 dialects, one or more blocks each starting with a
+// -----BEGIN COIN SIGNED MESSAGE----- header
+func parseArmor(lines []string, start int) ([]SignedMessage, error) {
+	const (
+		outside = iota
+		inMessage
+		inSignature
+		inRFCHeaders
+		inRFCSignature
+	)
+
+	var (
+		messages     []SignedMessage
+		current      SignedMessage
+		messageLines []string
+		signature    []string
+		state        = outside
+	)
+
+	for idx := start; idx < len(lines); idx++ {
+		line, lineNumber := lines[idx], idx+1
+		trimmed := trimLine(line)
+
+		switch state {
+		case outside:
+			if trimmed == "" {
+				continue
+			}
+
+			match := vtHeaderRegexp.FindStringSubmatch(trimmed)
+			if match == nil {
+				return nil, &ParseError{Line: lineNumber, Err: ErrUnexpectedText}
+			}
+
+			current, messageLines, signature = SignedMessage{Dialect: DialectArmor, Armor: match[1], Line: lineNumber}, nil, nil
+			state = inMessage
+		case inMessage:
+			if trimmed == vtSignatureLine {
+				current.Message = strings.Join(messageLines, "\r\n")
+				state = inSignature
+
+				continue
+			}
+
+			if match := rfcSignatureRegexp.FindStringSubmatch(trimmed); match != nil {
+				current.Dialect = DialectRFC2440
+				current.Message = strings.Join(rfcBody(messageLines), "\r\n")
+				state = inRFCHeaders
+
+				continue
+			}
+
+			if vtHeaderRegexp.MatchString(trimmed) || vtFooterRegexp.MatchString(trimmed) {
+				return nil, &ParseError{Line: lineNumber, Err: ErrNoSignatureBlock}
+			}
+
+			messageLines = append(messageLines, line)
+		case inSignature:
+			if match := vtFooterRegexp.FindStringSubmatch(trimmed); match != nil {
+				switch {
+				case len(signature) == 0:
+					return nil, &ParseError{Line: lineNumber, Err: ErrNoAddress}
+				case len(signature) == 1:
+					return nil, &ParseError{Line: lineNumber, Err: ErrNoSignature}
+				case match[1] != current.Armor:
+					return nil, &ParseError{Line: lineNumber, Err: ErrFooterMismatch}
+				}
+
+				current.Address, current.Signature = signature[0], signature[1]
+				messages = append(messages, current)
+				state = outside
+
+				continue
+			}
+
+			if strings.HasPrefix(trimmed, vtArmorDashes) {
+				return nil, &ParseError{Line: lineNumber, Err: ErrNoFooter}
+			}
+
+			if trimmed == "" {
+				continue
+			}
+
+			if len(signature) == 2 {
+				return nil, &ParseError{Line: lineNumber, Err: ErrUnexpectedLine}
+			}
+
+			signature = append(signature, trimmed)
+		case inRFCHeaders:
+			// Armor headers eg Version: Bitcoin-qt (1.0) and Address: 1... end at a blank line
+			if trimmed == "" {
+				state = inRFCSignature
+				continue
+			}
+
+			match := rfcArmorHeaderRegexp.FindStringSubmatch(trimmed)
+			if match == nil {
+				// No blank line, this is the signature block body
+				state = inRFCSignature
+				idx--
+
+				continue
+			}
+
+			if strings.EqualFold(match[1], "Address") {
+				current.Address = strings.TrimSpace(match[2])
+			}
+		case inRFCSignature:
+			if match := rfcFooterRegexp.FindStringSubmatch(trimmed); match != nil {
+				// The address may be the first line of the body rather than a header
+				if current.Address == "" && len(signature) > 1 {
+					current.Address, signature = signature[0], signature[1:]
+				}
+
+				switch {
+				case current.Address == "":
+					return nil, &ParseError{Line: lineNumber, Err: ErrNoAddress}
+				case len(signature) == 0:
+					return nil, &ParseError{Line: lineNumber, Err: ErrNoSignature}
+				case match[1] != current.Armor:
+					return nil, &ParseError{Line: lineNumber, Err: ErrFooterMismatch}
+				}
+
+				// Long (BIP322) signatures may be wrapped over several lines
+				current.Signature = strings.Join(signature, "")
+				messages = append(messages, current)
+				state = outside
+
+				continue
+			}
+
+			if strings.HasPrefix(trimmed, vtArmorDashes) {
+				return nil, &ParseError{Line: lineNumber, Err: ErrNoFooter}
+			}
+
+			if trimmed != "" {
+				signature = append(signature, trimmed)
+			}
+		}
+	}
+
+	switch state {
+	case inMessage:
+		return nil, &ParseError{Line: current.Line, Err: ErrNoSignatureBlock}
+	case inSignature, inRFCHeaders, inRFCSignature:
+		return nil, &ParseError{Line: current.Line, Err: ErrNoFooter}
+	}
+
+	return messages, nil
+}
+
+// rfcBody removes the Hash: armor headers and the one blank line that ends them (RFC 2440 7.1) from the start of an
+// RFC 2440 clear signed body and undoes the dash escaping. Any other "Name: value" line, or a second blank line, is
+// part of the message
+func rfcBody(lines []string) []string {
+	idx := 0
+	for idx < len(lines) && rfcHashHeaderRegexp.MatchString(lines[idx]) {
+		idx++
+	}
+
+	if idx < len(lines) && lines[idx] == "" {
+		idx++
+	}
+
+	lines = lines[idx:]
+
+	body := make([]string, 0, len(lines))
+	for _, line := range lines {
+		body = append(body, strings.TrimPrefix(line, vtDashEscape))
+	}
+
+	return body
+}
+
+// parseLabelled parses Address:, Message: and Signature: lines. The message continues over the lines that follow
+// until the next label
+func parseLabelled(lines []string, start int) ([]SignedMessage, error) {
+	var (
+		fields       = map[string]string{}
+		messageLines []string
+		inMessage    bool
+	)
+
+	for idx := start; idx < len(lines); idx++ {
+		line, lineNumber := lines[idx], idx+1
+
+		match := labelRegexp.FindStringSubmatch(line)
+		if match == nil {
+			switch {
+			case inMessage:
+				messageLines = append(messageLines, line)
+			case !blank(line):
+				return nil, &ParseError{Line: lineNumber, Err: ErrUnexpectedText}
+			}
+
+			continue
+		}
+
+		label := strings.ToLower(match[1])
+		if _, ok := fields[label]; ok {
+			return nil, &ParseError{Line: lineNumber, Err: ErrUnexpectedLine}
+		}
+
+		fields[label] = trimLine(match[2])
+
+		inMessage = label == "message"
+		if inMessage {
+			// The message is taken as is, only the space after the colon is dropped
+			messageLines = []string{match[2]}
+		}
+	}
+
+	// Blank lines after the message are padding
+	for len(messageLines) > 0 && blank(messageLines[len(messageLines)-1]) {
+		messageLines = messageLines[:len(messageLines)-1]
+	}
+
+	line := start + 1
+
+	switch _, hasMessage := fields["message"]; {
+	case fields["address"] == "":
+		return nil, &ParseError{Line: line, Err: ErrNoAddress}
+	case fields["signature"] == "":
+		return nil, &ParseError{Line: line, Err: ErrNoSignature}
+	case !hasMessage:
+		return nil, &ParseError{Line: line, Err: ErrNoMessage}
+	}
+
+	return []SignedMessage{{
+		Dialect:   DialectLabelled,
+		Address:   fields["address"],
+		Signature: fields["signature"],
+		Message:   strings.Join(messageLines, "\r\n"),
+		Line:      line,
+	}}, nil
+}
+
+// parsePlain parses a message without armor, the address on the first line, the signature on the last and the
+// message in between
+func parsePlain(lines []string, start int) ([]SignedMessage, error) {
+	last := len(lines) - 1
+	for blank(lines[last]) {
+		last--
+	}
+
+	address, signature := trimLine(lines[start]), trimLine(lines[last])
+
+	if !plainAddressRegexp.MatchString(address) {
+		return nil, &ParseError{Line: start + 1, Err: ErrNoSignedMessage}
+	}
+
+	if decoded, err := base64.StdEncoding.DecodeString(signature); last == start || err != nil ||
+		!plainSignatureRegexp.MatchString(signature) || len(decoded) < expectedSignatureLen {
+		return nil, &ParseError{Line: last + 1, Err: ErrNoSignature}
+	}
+
+	return []SignedMessage{{
+		Dialect:   DialectPlain,
+		Address:   address,
+		Signature: signature,
+		Message:   strings.Join(lines[start+1:last], "\r\n"),
+		Line:      start + 1,
+	}}, nil
+}
@@ -0,0 +1,206 @@
+package pkg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestParseSignedMessages(t *testing.T) {
+	const (
+		btcAddress   = "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR"
+		btcSignature = "G1pnvdb0RfKfv3Jhg4x0XBQqv1KQx3WFRaxTiUVN84fpIzxOBgapJb/Dpy6auJ28xcHaBxl3XHBbJejfokjgtmg="
+		btcMessage   = "Nonce: 1675bf38ec241a2308585ad0  Serial: DDRRNOCZJRIFCIBAEBJDOJQY74\r\nVersion: 2.4.0 time=20190207.130255 git=master@e233940e coin=BTC"
+		btcBlock     = "-----BEGIN BITCOIN SIGNED MESSAGE-----\r\n" + btcMessage + "\r\n-----BEGIN SIGNATURE-----\r\n" + btcAddress + "\r\n" + btcSignature + "\r\n-----END BITCOIN SIGNED MESSAGE-----\r\n"
+		ltcBlock     = "-----BEGIN LITECOIN SIGNED MESSAGE-----\nHello World\n-----BEGIN SIGNATURE-----\nLLsXEU59RyoMmjgCkUAghxTLr6FXoRCgQT\nH021r+HxbXZo2Vkuyq0D/pfz8kllqDzmOzczJXBanIytdsbZKPlg3q1NhytyLXp03DQa//0zoOjoJfVUjZORql8=\n-----END LITECOIN SIGNED MESSAGE-----"
+	)
+
+	btc := SignedMessage{Dialect: DialectArmor, Armor: "BITCOIN", Address: btcAddress, Signature: btcSignature, Message: btcMessage, Line: 1}
+	ltc := SignedMessage{
+		Dialect: DialectArmor, Armor: "LITECOIN", Address: "LLsXEU59RyoMmjgCkUAghxTLr6FXoRCgQT", Message: "Hello World",
+		Signature: "H021r+HxbXZo2Vkuyq0D/pfz8kllqDzmOzczJXBanIytdsbZKPlg3q1NhytyLXp03DQa//0zoOjoJfVUjZORql8=", Line: 9,
+	}
+
+	longMessage := strings.Repeat("A long message line that goes on and on\r\n", 100) + "end"
+
+	tests := []struct {
+		name     string
+		input    string
+		want     []SignedMessage
+		wantErr  error
+		wantLine int
+	}{
+		{"opendime padded", btcBlock + "\r\n\r\n" + strings.Repeat(" ", 68) + "\r\n" + strings.Repeat(" ", 68) + "\r\n\r\n", []SignedMessage{btc}, nil, 0},
+		{"lf line endings", strings.ReplaceAll(btcBlock, "\r\n", "\n"), []SignedMessage{btc}, nil, 0},
+		{"nul padding", btcBlock + strings.Repeat("\x00", 300), []SignedMessage{btc}, nil, 0},
+		{"byte order mark", "\ufeff" + btcBlock, []SignedMessage{btc}, nil, 0},
+		{"leading blank lines", "\n\n" + btcBlock, []SignedMessage{{Dialect: DialectArmor, Armor: "BITCOIN", Address: btcAddress, Signature: btcSignature, Message: btcMessage, Line: 3}}, nil, 0},
+		{"two blocks", btcBlock + "\n" + ltcBlock, []SignedMessage{btc, ltc}, nil, 0},
+		{
+			"longer than 512 bytes",
+			"-----BEGIN BITCOIN SIGNED MESSAGE-----\n" + strings.ReplaceAll(longMessage, "\r\n", "\n") + "\n-----BEGIN SIGNATURE-----\n" + btcAddress + "\n" + btcSignature + "\n-----END BITCOIN SIGNED MESSAGE-----\n",
+			[]SignedMessage{{Dialect: DialectArmor, Armor: "BITCOIN", Address: btcAddress, Signature: btcSignature, Message: longMessage, Line: 1}}, nil, 0,
+		},
+		{"blank message line kept", "-----BEGIN BITCOIN SIGNED MESSAGE-----\na\n\nb \n-----BEGIN SIGNATURE-----\n" + btcAddress + "\n" + btcSignature + "\n-----END BITCOIN SIGNED MESSAGE-----",
+			[]SignedMessage{{Dialect: DialectArmor, Armor: "BITCOIN", Address: btcAddress, Signature: btcSignature, Message: "a\r\n\r\nb ", Line: 1}}, nil, 0},
+		{"empty", "", nil, ErrNoSignedMessage, 1},
+		{"only padding", "\n   \n\x00\x00", nil, ErrNoSignedMessage, 3},
+		{"no header", "Hello\n" + btcBlock, nil, ErrNoSignedMessage, 1},
+		{"text after", btcBlock + "trailing junk\n", nil, ErrUnexpectedText, 8},
+		{"no signature block", "-----BEGIN BITCOIN SIGNED MESSAGE-----\nHello\n", nil, ErrNoSignatureBlock, 1},
+		{"footer before signature block", "-----BEGIN BITCOIN SIGNED MESSAGE-----\nHello\n-----END BITCOIN SIGNED MESSAGE-----\n", nil, ErrNoSignatureBlock, 3},
+		{"no address", "-----BEGIN BITCOIN SIGNED MESSAGE-----\nHello\n-----BEGIN SIGNATURE-----\n-----END BITCOIN SIGNED MESSAGE-----\n", nil, ErrNoAddress, 4},
+		{"no signature", "-----BEGIN BITCOIN SIGNED MESSAGE-----\nHello\n-----BEGIN SIGNATURE-----\n" + btcAddress + "\n-----END BITCOIN SIGNED MESSAGE-----\n", nil, ErrNoSignature, 5},
+		{"extra line", "-----BEGIN BITCOIN SIGNED MESSAGE-----\nHello\n-----BEGIN SIGNATURE-----\n" + btcAddress + "\n" + btcSignature + "\nmore\n-----END BITCOIN SIGNED MESSAGE-----\n", nil, ErrUnexpectedLine, 6},
+		{"no footer", "-----BEGIN BITCOIN SIGNED MESSAGE-----\nHello\n-----BEGIN SIGNATURE-----\n" + btcAddress + "\n" + btcSignature + "\n", nil, ErrNoFooter, 1},
+		{"wrong footer", "-----BEGIN BITCOIN SIGNED MESSAGE-----\nHello\n-----BEGIN SIGNATURE-----\n" + btcAddress + "\n" + btcSignature + "\n-----END LITECOIN SIGNED MESSAGE-----\n", nil, ErrFooterMismatch, 6},
+		{"truncated footer", "-----BEGIN BITCOIN SIGNED MESSAGE-----\nHello\n-----BEGIN SIGNATURE-----\n" + btcAddress + "\n" + btcSignature + "\n-----END BITC", nil, ErrNoFooter, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// One byte at a time to check nothing depends on the size of a read
+			got, err := ParseSignedMessages(iotest.OneByteReader(strings.NewReader(tt.input)))
+
+			if tt.wantErr != nil {
+				var parseErr *ParseError
+				if !errors.Is(err, tt.wantErr) || !errors.As(err, &parseErr) || parseErr.Line != tt.wantLine {
+					t.Fatalf("ParseSignedMessages() error = %v, want %v on line %d", err, tt.wantErr, tt.wantLine)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseSignedMessages() error = %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseSignedMessages() = %+v, want %+v", got, tt.want)
+			}
+
+			for idx := range got {
+				if got[idx] != tt.want[idx] {
+					t.Errorf("ParseSignedMessages()[%d] = %+v, want %+v", idx, got[idx], tt.want[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestParseSignedMessage(t *testing.T) {
+	const block = "-----BEGIN LITECOIN SIGNED MESSAGE-----\nHello World\n-----BEGIN SIGNATURE-----\nLLsXEU59RyoMmjgCkUAghxTLr6FXoRCgQT\nH021r+HxbXZo2Vkuyq0D/pfz8kllqDzmOzczJXBanIytdsbZKPlg3q1NhytyLXp03DQa//0zoOjoJfVUjZORql8=\n-----END LITECOIN SIGNED MESSAGE-----\n"
+
+	if got, err := ParseSignedMessage(strings.NewReader(block)); err != nil || got.Message != "Hello World" {
+		t.Errorf("ParseSignedMessage() = %+v, %v", got, err)
+	}
+
+	// Only checking the first block would let a second, unverified, block ride along
+	var parseErr *ParseError
+	if _, err := ParseSignedMessage(strings.NewReader(block + "\n" + block)); !errors.Is(err, ErrMultipleMessages) ||
+		!errors.As(err, &parseErr) || parseErr.Line != 8 {
+		t.Errorf("ParseSignedMessage() two blocks error = %v", err)
+	}
+
+	if _, err := ParseSignedMessage(strings.NewReader("")); !errors.Is(err, ErrNoSignedMessage) {
+		t.Errorf("ParseSignedMessage() empty error = %v", err)
+	}
+}
+
+func TestParseSignedMessagesReadError(t *testing.T) {
+	wantErr := errors.New("device removed")
+
+	if _, err := ParseSignedMessages(iotest.ErrReader(wantErr)); !errors.Is(err, wantErr) {
+		t.Errorf("ParseSignedMessages() error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	err := &ParseError{Line: 6, Err: ErrFooterMismatch}
+
+	if got := err.Error(); got != "line 6: signed message footer does not match the header" {
+		t.Errorf("ParseError.Error() = %v", got)
+	}
+}
+
+func TestParseSignedMessagesDialects(t *testing.T) {
+	// One synthetic fixture per wallet layout (see testdata/signedmessages/README.md), each must parse as the dialect
+	// and verify
+	tests := []struct {
+		file        string
+		wantDialect Dialect
+		wantAddress string
+		wantMessage string
+	}{
+		{"armor_opendime.txt", DialectArmor, "1Mmg2eycKHomhjAikEAVehHpCSHTREhLfR", "Nonce: 1675bf38ec241a2308585ad0  Serial: DDRRNOCZJRIFCIBAEBJDOJQY74\r\nVersion: 2.4.0 time=20190207.130255 git=master@e233940e coin=BTC"},
+		{"armor_electrum.txt", DialectArmor, "133r6sCjLq6NbmSLjxuypDuSeocwenu1Qg", "Electrum signs the message as typed\r\nwith LF line endings"},
+		{"armor_litecoin.txt", DialectArmor, "LLsXEU59RyoMmjgCkUAghxTLr6FXoRCgQT", "Hello World"},
+		{"rfc2440_bitcoinqt.txt", DialectRFC2440, "133r6sCjLq6NbmSLjxuypDuSeocwenu1Qg", "Hello World!"},
+		{"rfc2440_dash_escaped.txt", DialectRFC2440, "133r6sCjLq6NbmSLjxuypDuSeocwenu1Qg", "-----BEGIN PGP MESSAGE-----\r\n- dash escaped lines\r\nare unescaped"},
+		{"rfc2440_header_like_body.txt", DialectRFC2440, "13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu", "Subject: hi\r\n\r\nbody"},
+		{"rfc2440_no_hash.txt", DialectRFC2440, "13dnBMR7AV1CrEZJJFAXe3Q3uiXdXXHGnu", "\r\nstarts after a blank line"},
+		{"labelled_sparrow.txt", DialectLabelled, "133r6sCjLq6NbmSLjxuypDuSeocwenu1Qg", "Sparrow signed\r\nthis message"},
+		{"labelled_bip322.txt", DialectLabelled, "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l", "Hello World"},
+		{"plain_web.txt", DialectPlain, "133r6sCjLq6NbmSLjxuypDuSeocwenu1Qg", "Signed without any armor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", "signedmessages", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			got, err := ParseSignedMessages(f)
+			if err != nil {
+				t.Fatalf("ParseSignedMessages() error = %v", err)
+			}
+
+			if len(got) != 1 || got[0].Dialect != tt.wantDialect || got[0].Address != tt.wantAddress || got[0].Message != tt.wantMessage {
+				t.Fatalf("ParseSignedMessages() = %+v, want %s %s %q", got, tt.wantDialect, tt.wantAddress, tt.wantMessage)
+			}
+
+			if _, err := VerifyMessage(MainNet, got[0].Address, got[0].Signature, got[0].Message); err != nil {
+				t.Errorf("VerifyMessage() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestParseSignedMessagesDialectErrors(t *testing.T) {
+	const (
+		address   = "133r6sCjLq6NbmSLjxuypDuSeocwenu1Qg"
+		signature = "IMzCsdClG4gihm1Nm2i32frH6P73p9nQR7Nk55OqWXLBGw2CNJRKVbQ9bT4m/q1Kw7cqyE324ZTmLUgQMjOAFF4="
+	)
+
+	tests := []struct {
+		name     string
+		input    string
+		wantErr  error
+		wantLine int
+	}{
+		{"rfc2440 no address", "-----BEGIN BITCOIN SIGNED MESSAGE-----\nHello\n-----BEGIN BITCOIN SIGNATURE-----\n\n" + signature + "\n-----END BITCOIN SIGNATURE-----\n", ErrNoAddress, 6},
+		{"rfc2440 no signature", "-----BEGIN BITCOIN SIGNED MESSAGE-----\nHello\n-----BEGIN BITCOIN SIGNATURE-----\nAddress: " + address + "\n\n-----END BITCOIN SIGNATURE-----\n", ErrNoSignature, 6},
+		{"rfc2440 no footer", "-----BEGIN BITCOIN SIGNED MESSAGE-----\nHello\n-----BEGIN BITCOIN SIGNATURE-----\nAddress: " + address + "\n\n" + signature + "\n", ErrNoFooter, 1},
+		{"rfc2440 wrong footer", "-----BEGIN BITCOIN SIGNED MESSAGE-----\nHello\n-----BEGIN BITCOIN SIGNATURE-----\nAddress: " + address + "\n\n" + signature + "\n-----END LITECOIN SIGNATURE-----\n", ErrFooterMismatch, 7},
+		{"labelled no address", "Message: Hello\nSignature: " + signature + "\n", ErrNoAddress, 1},
+		{"labelled no signature", "Address: " + address + "\nMessage: Hello\n", ErrNoSignature, 1},
+		{"labelled no message", "Address: " + address + "\nSignature: " + signature + "\n", ErrNoMessage, 1},
+		{"labelled twice", "Address: " + address + "\nAddress: " + address + "\n", ErrUnexpectedLine, 2},
+		{"labelled text before message", "Address: " + address + "\nHello\n", ErrUnexpectedText, 2},
+		{"plain no signature", address + "\nHello\nnot a signature\n", ErrNoSignature, 3},
+		{"plain only address", address + "\n", ErrNoSignature, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSignedMessages(strings.NewReader(tt.input))
+
+			var parseErr *ParseError
+			if !errors.Is(err, tt.wantErr) || !errors.As(err, &parseErr) || parseErr.Line != tt.wantLine {
+				t.Fatalf("ParseSignedMessages() error = %v, want %v on line %d", err, tt.wantErr, tt.wantLine)
+			}
+		})
+	}
+}
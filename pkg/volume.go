@@ -0,0 +1,23 @@
+package pkg
+
+import "path/filepath"
+
+// Files on an Opendime volume, relative to the mount point
+var (
+	VerifyTxtPath = filepath.Join("advanced", "verify.txt")
+	// VersionTxtPath optional firmware version file
+	VersionTxtPath = filepath.Join("advanced", "version.txt")
+	// ChallengeFilePath a fresh nonce written here is signed into a new verify.txt by the Opendime
+	ChallengeFilePath = filepath.Join("advanced", "nonce.txt")
+)
+
+// ChallengeNonceSize bytes of randomness in a challenge nonce, the same size as the nonces Opendime makes itself
+const ChallengeNonceSize = 12
+
+// Files in the root of an Opendime volume
+const (
+	AddressTxtName    = "address.txt"
+	PrivateKeyTxtName = "private-key.txt"
+	ReadmeTxtName     = "README.txt"
+	IndexHtmName      = "index.htm"
+)
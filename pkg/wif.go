@@ -4,24 +4,38 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/btcsuite/btcd/btcutil/base58"
 )
 
 const (
-	prefixBitcoin  = 0x80
-	prefixLitecoin = 0xb0
-
 	compressedWif = 0x01
 
-	bitcoin  = "Bitcoin"
-	litecoin = "Litecoin"
+	// Bitcoin coin name used as WIF mode
+	Bitcoin = "Bitcoin"
+	// Litecoin coin name used as WIF mode
+	Litecoin = "Litecoin"
+	// Dogecoin coin name used as WIF mode
+	Dogecoin = "Dogecoin"
+	// Ethereum coin name
+	Ethereum = "Ethereum"
+	// BitcoinCash coin name
+	BitcoinCash = "Bitcoin Cash"
+	// Dash coin name used as WIF mode
+	Dash = "Dash"
 )
 
-// ValidateWif validate WIF is valid and return decoded mode (Bitcoin/Litecoin) secret exponent hex and isCompressed (and error)
-func ValidateWif(key string) (string, string, bool, error) {
+// ErrAmbiguousWif the WIF prefix byte is used by more than one coin on the network with different message magics (eg
+// Bitcoin and Litecoin on testnet)
+var ErrAmbiguousWif = errors.New("WIF prefix is used by more than one coin")
+
+// ValidateWif validate WIF is valid for the network and return decoded modes (every coin using the WIF prefix eg
+// Bitcoin and Bitcoin Cash, in registration order) secret exponent hex and isCompressed (and error)
+func ValidateWif(network Network, key string) ([]string, string, bool, error) {
 	var (
-		mode           string
+		modes          []string
 		isCompressed   bool
 		secretExponent []byte
 	)
@@ -36,7 +50,7 @@ func ValidateWif(key string) (string, string, bool, error) {
 	switch keyBytesLen {
 	case expectedCompressedLen:
 		if keyBytes[keyBytesLen-5] != compressedWif {
-			return "", "", false, errors.New("WIF malformed/compression byte not 01")
+			return nil, "", false, errors.New("WIF malformed/compression byte not 01")
 		}
 		isCompressed = true
 		secretExponent = keyBytes[1 : keyBytesLen-5]
@@ -44,21 +58,50 @@ func ValidateWif(key string) (string, string, bool, error) {
 		isCompressed = false
 		secretExponent = keyBytes[1 : keyBytesLen-4]
 	default:
-		return "", "", false, errors.New("WIF malformed/wrong length")
+		return nil, "", false, errors.New("WIF malformed/wrong length")
 	}
 
-	switch keyBytes[0] {
-	case prefixBitcoin:
-		mode = bitcoin
-	case prefixLitecoin:
-		mode = litecoin
-	default:
-		return "", "", false, errors.New("WIF malformed/wrong prefix byte")
+	// Coins share prefix bytes (eg Bitcoin and Bitcoin Cash, or testnet Bitcoin and Litecoin) so every match is returned
+	for _, coin := range Coins() {
+		params, ok := coin.Params(network)
+		if ok && params != nil && keyBytes[0] == params.PrivateKeyID {
+			modes = append(modes, coin.Name)
+		}
+	}
+
+	if len(modes) == 0 {
+		return nil, "", false, errors.New("WIF malformed/wrong prefix byte")
 	}
 
 	secretExponentHex := hex.EncodeToString(secretExponent)
 
-	return mode, secretExponentHex, isCompressed, nil
+	return modes, secretExponentHex, isCompressed, nil
+}
+
+// WifCoin returns the coin to sign with for the modes of a WIF. Coins sharing the prefix and the message magic (eg
+// Bitcoin and Bitcoin Cash) sign alike so the first registered is returned. If the magics differ (eg Bitcoin and
+// Litecoin on testnet) ErrAmbiguousWif names the coins to choose from
+func WifCoin(modes []string) (string, error) {
+	if len(modes) == 0 {
+		return "", errors.New("WIF prefix is not used by any coin")
+	}
+
+	for _, mode := range modes[1:] {
+		if messageMagic(mode) != messageMagic(modes[0]) {
+			return "", fmt.Errorf("%w (%s), choose one", ErrAmbiguousWif, strings.Join(modes, ", "))
+		}
+	}
+
+	return modes[0], nil
+}
+
+// messageMagic of the coin or empty if the coin is not registered
+func messageMagic(coinName string) string {
+	if coin := GetCoin(coinName); coin != nil {
+		return coin.MessageMagic
+	}
+
+	return ""
 }
 
 // ToWif encode a WIF given the prefix, secret exponent hex and compression flag
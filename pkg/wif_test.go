@@ -1,6 +1,9 @@
 package pkg
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -11,44 +14,44 @@ func Test_ValidateWif(t *testing.T) {
 	tests := []struct {
 		name    string
 		args    args
-		want    string
+		want    []string
 		want1   string
 		want2   bool
 		wantErr bool
 	}{
-		{"invalid wrong compression byte", args{key: "KwUhWnQRC7mKrEvMmYjzs2Qtw3LLktTQjL8GmjNbvVi8vjxYZZAd"}, "", "", false, true},
-		{"invalid wrong prefix byte", args{key: "4XAZUtEJi962ESjyenv7vUU7qwzQ1WjJDVxLv5d7wVBNQ6CWD5e"}, "", "", false, true},
-		{"invalid too short", args{key: "KwUhWnQRC7mK"}, "", "", false, true},
-		{"invalid too long", args{key: "KwUhWnQRC7mKrEvMmYjzs2Qtw3LLktTQjL8GmjNbvVi8vM677LgMKKKKK"}, "", "", false, true},
+		{"invalid wrong compression byte", args{key: "KwUhWnQRC7mKrEvMmYjzs2Qtw3LLktTQjL8GmjNbvVi8vjxYZZAd"}, nil, "", false, true},
+		{"invalid wrong prefix byte", args{key: "4XAZUtEJi962ESjyenv7vUU7qwzQ1WjJDVxLv5d7wVBNQ6CWD5e"}, nil, "", false, true},
+		{"invalid too short", args{key: "KwUhWnQRC7mK"}, nil, "", false, true},
+		{"invalid too long", args{key: "KwUhWnQRC7mKrEvMmYjzs2Qtw3LLktTQjL8GmjNbvVi8vM677LgMKKKKK"}, nil, "", false, true},
 		{
 			"valid bitcoin compressed fc3f",
 			args{key: "L5g3omnu8BYUS5zUA74AW1eSbZ1xx72HzSVgJcejsvMTn3P579qd"},
-			"Bitcoin", "fc3fa47324ceb77e1160833eddd30ea15efa22a6e59c204921e12fbbab1becb8", true, false,
+			[]string{Bitcoin, BitcoinCash}, "fc3fa47324ceb77e1160833eddd30ea15efa22a6e59c204921e12fbbab1becb8", true, false,
 		},
 		{
 			"valid bitcoin uncompressed fc3f",
 			args{key: "5KjNw6cmtUK1KpoYytfnCZKTC11DgDhAjvMYZYBpKncuHd6YzkX"},
-			"Bitcoin", "fc3fa47324ceb77e1160833eddd30ea15efa22a6e59c204921e12fbbab1becb8", false, false,
+			[]string{Bitcoin, BitcoinCash}, "fc3fa47324ceb77e1160833eddd30ea15efa22a6e59c204921e12fbbab1becb8", false, false,
 		},
 		{
 			"valid litecoin compressed 07b5",
 			args{key: "T3JxxXhbbVjvd5ZEKBgs5NxGstyepyUJYY2XdY19VTtJSEep3SM3"},
-			"Litecoin", "07b5eb6760c9b0cef7009acc4b2f01d847a5da1e7aa97373f7db996db295ed26", true, false,
+			[]string{Litecoin}, "07b5eb6760c9b0cef7009acc4b2f01d847a5da1e7aa97373f7db996db295ed26", true, false,
 		},
 		{
 			"valid litecoin uncompressed 07b5",
 			args{key: "6uBR1M6aDM76oh141wz4eF36s3iPAWZU5syzEiTmX1euSDQkoLG"},
-			"Litecoin", "07b5eb6760c9b0cef7009acc4b2f01d847a5da1e7aa97373f7db996db295ed26", false, false,
+			[]string{Litecoin}, "07b5eb6760c9b0cef7009acc4b2f01d847a5da1e7aa97373f7db996db295ed26", false, false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, got1, got2, err := ValidateWif(tt.args.key)
+			got, got1, got2, err := ValidateWif(MainNet, tt.args.key)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ValidateWif() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
+			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("ValidateWif() got = %v, want %v", got, tt.want)
 			}
 			if got1 != tt.want1 {
@@ -61,6 +64,66 @@ func Test_ValidateWif(t *testing.T) {
 	}
 }
 
+func Test_ValidateWifNetwork(t *testing.T) {
+	tests := []struct {
+		name    string
+		network Network
+		key     string
+		want    []string
+		want1   string
+		want2   bool
+		wantErr bool
+	}{
+		{
+			"valid testnet compressed 07b5", TestNet, "cMqgyhQGdBTb1gPd9xZ8ELuxZGdkRLZ6oNGjt9q7RcN9B672pLMm",
+			[]string{Bitcoin, Litecoin, BitcoinCash, Dash}, "07b5eb6760c9b0cef7009acc4b2f01d847a5da1e7aa97373f7db996db295ed26", true, false,
+		},
+		{
+			"valid regtest uncompressed 07b5", RegTest, "91eK7xNau9iNJNcV8U61jSotZEWd7sedf9Smc9oF9J5LXT8iDiF",
+			[]string{Bitcoin, Litecoin, Dogecoin, BitcoinCash, Dash}, "07b5eb6760c9b0cef7009acc4b2f01d847a5da1e7aa97373f7db996db295ed26", false, false,
+		},
+		{"invalid mainnet key on testnet", TestNet, "KwUhWnQRC7mKrEvMmYjzs2Qtw3LLktTQjL8GmjNbvVi8vM677LgM", nil, "", false, true},
+		{"invalid testnet key on mainnet", MainNet, "cMqgyhQGdBTb1gPd9xZ8ELuxZGdkRLZ6oNGjt9q7RcN9B672pLMm", nil, "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, got2, err := ValidateWif(tt.network, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateWif() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateWif() got = %v, want %v", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("ValidateWif() got1 = %v, want %v", got1, tt.want1)
+			}
+			if got2 != tt.want2 {
+				t.Errorf("ValidateWif() got2 = %v, want %v", got2, tt.want2)
+			}
+		})
+	}
+}
+
+func TestWifCoin(t *testing.T) {
+	if got, err := WifCoin([]string{Litecoin}); err != nil || got != Litecoin {
+		t.Errorf("WifCoin() = %v, %v, want %v", got, err, Litecoin)
+	}
+
+	// Bitcoin Cash signs with the Bitcoin magic so a shared prefix is not ambiguous
+	if got, err := WifCoin([]string{Bitcoin, BitcoinCash}); err != nil || got != Bitcoin {
+		t.Errorf("WifCoin() = %v, %v, want %v", got, err, Bitcoin)
+	}
+
+	_, err := WifCoin([]string{Bitcoin, Litecoin, BitcoinCash})
+	if !errors.Is(err, ErrAmbiguousWif) {
+		t.Fatalf("WifCoin() error = %v, want %v", err, ErrAmbiguousWif)
+	}
+	if !strings.Contains(err.Error(), "Bitcoin, Litecoin, Bitcoin Cash") {
+		t.Errorf("WifCoin() error = %v, want the coins named", err)
+	}
+}
+
 func Test_ToWif(t *testing.T) {
 	type args struct {
 		prefixHex string
@@ -146,7 +209,7 @@ func TestValidateWif(t *testing.T) {
 	tests := []struct {
 		name    string
 		args    args
-		want    string
+		want    []string
 		want1   string
 		want2   bool
 		wantErr bool
@@ -155,12 +218,12 @@ func TestValidateWif(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, got1, got2, err := ValidateWif(tt.args.key)
+			got, got1, got2, err := ValidateWif(MainNet, tt.args.key)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ValidateWif() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
+			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("ValidateWif() got = %v, want %v", got, tt.want)
 			}
 			if got1 != tt.want1 {